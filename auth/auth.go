@@ -32,9 +32,12 @@ func Auth(username string, sharedSecret, publicKey []byte) (*PlayerProfile, erro
 	if err != nil {
 		return nil, err
 	}
+	if len(profile.UUID) != 32 {
+		return nil, fmt.Errorf("invalid UUID %q", profile.UUID)
+	}
 	realUniqueId, err := util.StringToUUID(util.ToHyphenUUID(profile.UUID))
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	profile.RealUUID = realUniqueId
 	return &profile, nil
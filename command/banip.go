@@ -0,0 +1,59 @@
+package command
+
+import (
+	"fmt"
+	"github.com/olsdavis/goelan/permission"
+	"github.com/olsdavis/goelan/server"
+	"net"
+	"strings"
+)
+
+type BanIPCommand struct{}
+
+func (cmd BanIPCommand) Labels() []string {
+	return []string{"ban-ip"}
+}
+
+func (cmd BanIPCommand) MinArgs() int {
+	return 1
+}
+
+func (cmd BanIPCommand) RequiredPermission() string {
+	return permission.BanPermission
+}
+
+func (cmd BanIPCommand) Help() string {
+	return "ban-ip (ip|player) <reason>"
+}
+
+func (cmd BanIPCommand) Description() string {
+	return "Bans the given IP address, or the one of the given player, for the given reason."
+}
+
+func (cmd BanIPCommand) Execute(label string, args []string, sender CommandSender) {
+	ip := args[0]
+	if ok, target := server.Get().GetPlayerByName(args[0]); ok {
+		ip = target.RemoteIP()
+	} else if net.ParseIP(ip) == nil {
+		sender.SendMessage(fmt.Sprintf("%v is neither a valid IP address nor an online player.", args[0]))
+		return
+	}
+	var reason string
+	if len(args) > 1 {
+		reason = strings.Join(args[1:], " ")
+	} else {
+		reason = "Your IP address has been banned."
+	}
+	server.Get().IPBanList.AddIP(ip, reason)
+	var kicked []*server.Connection
+	server.Get().ForEachPlayerSync(func(c *server.Connection) {
+		// the list matches all the forms of the address
+		if banned, _ := server.Get().IPBanList.IsBanned(c.RemoteIP()); banned {
+			kicked = append(kicked, c)
+		}
+	})
+	for _, c := range kicked {
+		c.Disconnect(reason)
+	}
+	sender.SendMessage(fmt.Sprintf("Banned IP address %v.", ip))
+}
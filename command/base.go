@@ -2,6 +2,10 @@ package command
 
 func RegisterBaseCommands() {
 	RegisterCommand(BanCommand{})
+	RegisterCommand(BanIPCommand{})
+	RegisterCommand(ChunksCommand{})
 	RegisterCommand(HelpCommand{})
 	RegisterCommand(StopCommand{})
+	RegisterCommand(WorldCommand{})
+	RegisterCommand(WorldBorderCommand{})
 }
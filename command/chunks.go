@@ -0,0 +1,41 @@
+package command
+
+import (
+	"fmt"
+	"github.com/olsdavis/goelan/permission"
+	"github.com/olsdavis/goelan/server"
+)
+
+type ChunksCommand struct{}
+
+func (cmd ChunksCommand) Labels() []string {
+	return []string{"chunks"}
+}
+
+func (cmd ChunksCommand) MinArgs() int {
+	return 0
+}
+
+func (cmd ChunksCommand) RequiredPermission() string {
+	return permission.ChunkStats
+}
+
+func (cmd ChunksCommand) Help() string {
+	return "chunks"
+}
+
+func (cmd ChunksCommand) Description() string {
+	return "Shows the chunks being loaded or generated in each world."
+}
+
+func (cmd ChunksCommand) Execute(label string, args []string, sender CommandSender) {
+	for _, w := range server.Get().GetWorlds() {
+		pool := server.Get().GetChunkPool(w)
+		if pool == nil {
+			continue
+		}
+		stats := pool.Stats()
+		sender.SendMessage(fmt.Sprintf("%v: %v queued, %v in progress, %v completed, %v cancelled.",
+			w.Name, stats.Queued, stats.Active, stats.Completed, stats.Cancelled))
+	}
+}
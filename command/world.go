@@ -0,0 +1,57 @@
+package command
+
+import (
+	"fmt"
+	"github.com/olsdavis/goelan/permission"
+	"github.com/olsdavis/goelan/server"
+	"strings"
+)
+
+type WorldCommand struct{}
+
+func (cmd WorldCommand) Labels() []string {
+	return []string{"world"}
+}
+
+func (cmd WorldCommand) MinArgs() int {
+	return 0
+}
+
+func (cmd WorldCommand) RequiredPermission() string {
+	return permission.ChangeWorld
+}
+
+func (cmd WorldCommand) Help() string {
+	return "world <player> <world>"
+}
+
+func (cmd WorldCommand) Description() string {
+	return "Lists the worlds, or moves the given player to the spawn of the given world."
+}
+
+func (cmd WorldCommand) Execute(label string, args []string, sender CommandSender) {
+	if len(args) == 0 {
+		names := make([]string, 0)
+		for _, w := range server.Get().GetWorlds() {
+			names = append(names, fmt.Sprintf("%v (%v)", w.Name, w.Dimension))
+		}
+		sender.SendMessage("Worlds: " + strings.Join(names, ", "))
+		return
+	}
+	ok, conn := server.Get().GetPlayerByName(args[0])
+	if !ok {
+		sender.SendMessage(fmt.Sprintf("The player %v could not be found.", args[0]))
+		return
+	}
+	if len(args) < 2 {
+		sender.SendMessage(fmt.Sprintf("%v is in the world %v.", args[0], conn.Player.GetLocation().World.Name))
+		return
+	}
+	w := server.Get().GetWorldByName(args[1])
+	if w == nil {
+		sender.SendMessage(fmt.Sprintf("The world %v could not be found.", args[1]))
+		return
+	}
+	conn.Teleport(w.GetSpawnLocation())
+	sender.SendMessage(fmt.Sprintf("%v has been moved to the world %v.", args[0], w.Name))
+}
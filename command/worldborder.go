@@ -0,0 +1,134 @@
+package command
+
+import (
+	"fmt"
+	"github.com/olsdavis/goelan/permission"
+	"github.com/olsdavis/goelan/server"
+	"github.com/olsdavis/goelan/world"
+	"math"
+	"strconv"
+	"time"
+)
+
+// the longest resizing of the border, in seconds, which fits in a time.Duration
+const maxBorderSeconds = float64(math.MaxInt64 / int64(time.Second))
+
+type WorldBorderCommand struct{}
+
+func (cmd WorldBorderCommand) Labels() []string {
+	return []string{"worldborder"}
+}
+
+func (cmd WorldBorderCommand) MinArgs() int {
+	return 1
+}
+
+func (cmd WorldBorderCommand) RequiredPermission() string {
+	return permission.WorldBorder
+}
+
+func (cmd WorldBorderCommand) Help() string {
+	return "worldborder (world) <center (x) (z) | set (diameter) <seconds> | damage (per block) <safe zone> | warning (distance|time) (value)>"
+}
+
+func (cmd WorldBorderCommand) Description() string {
+	return "Shows or changes the border of the given world."
+}
+
+func (cmd WorldBorderCommand) Execute(label string, args []string, sender CommandSender) {
+	w := server.Get().GetWorldByName(args[0])
+	if w == nil {
+		sender.SendMessage(fmt.Sprintf("The world %v could not be found.", args[0]))
+		return
+	}
+	if len(args) == 1 {
+		border := w.GetBorder()
+		sender.SendMessage(fmt.Sprintf("The border of %v is centered on %v %v, and is %v blocks wide.", w.Name, border.CenterX, border.CenterZ, border.Diameter))
+		if border.Remaining > 0 {
+			sender.SendMessage(fmt.Sprintf("It will be %v blocks wide in %v.", border.TargetDiameter, border.Remaining))
+		}
+		return
+	}
+	if !changeBorder(w, args[1], args[2:]) {
+		sender.SendMessage("Usage: " + cmd.Help())
+		return
+	}
+	server.Get().UpdateBorder(w)
+	sender.SendMessage(fmt.Sprintf("The border of %v has been changed.", w.Name))
+}
+
+// changeBorder runs the given subcommand on the border of the given
+// world. Returns false if the subcommand or its arguments are invalid:
+// the numbers must be finite, the diameter positive, the center inside
+// the world, the resizing not longer than maxBorderSeconds, and the other
+// values may not be negative.
+func changeBorder(w *world.World, subcommand string, args []string) bool {
+	border := w.GetBorder()
+	if subcommand == "warning" {
+		if len(args) != 2 {
+			return false
+		}
+		value, err := strconv.ParseInt(args[1], 10, 32)
+		if err != nil || value < 0 {
+			return false
+		}
+		switch args[0] {
+		case "distance":
+			w.SetBorderWarning(int32(value), border.WarningTime)
+		case "time":
+			w.SetBorderWarning(border.WarningBlocks, int32(value))
+		default:
+			return false
+		}
+		return true
+	}
+	values, ok := parseNumbers(args)
+	if !ok {
+		return false
+	}
+	switch {
+	case subcommand == "center" && len(values) == 2:
+		if math.Abs(values[0]) > world.PortalTeleportBoundary || math.Abs(values[1]) > world.PortalTeleportBoundary {
+			return false
+		}
+		w.SetBorderCenter(values[0], values[1])
+	case subcommand == "set" && (len(values) == 1 || len(values) == 2):
+		if values[0] <= 0 {
+			return false
+		}
+		duration := time.Duration(0)
+		if len(values) == 2 {
+			if values[1] < 0 || values[1] > maxBorderSeconds {
+				return false
+			}
+			duration = time.Duration(values[1] * float64(time.Second))
+		}
+		w.ResizeBorder(values[0], duration)
+	case subcommand == "damage" && (len(values) == 1 || len(values) == 2):
+		safeZone := border.SafeZone
+		if len(values) == 2 {
+			safeZone = values[1]
+		}
+		if values[0] < 0 || safeZone < 0 {
+			return false
+		}
+		w.SetBorderDamage(values[0], safeZone)
+	default:
+		return false
+	}
+	return true
+}
+
+// parseNumbers parses the given arguments as finite numbers. Returns
+// false if one of them is not a finite number.
+func parseNumbers(args []string) ([]float64, bool) {
+	ret := make([]float64, len(args))
+	for i, arg := range args {
+		value, err := strconv.ParseFloat(arg, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, false
+		}
+		ret[i] = value
+	}
+	return ret, true
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"github.com/olsdavis/goelan/world"
+	"strings"
+	"testing"
+)
+
+func TestChangeBorder(t *testing.T) {
+	w := world.NewWorld("border")
+	w.Info = world.NewWorldInfo("border", 0)
+	valid := []string{
+		"center 100 -200.5",
+		"set 1000",
+		"set 500 60",
+		"damage 0.5",
+		"damage 1 10",
+		"warning distance 10",
+		"warning time 0",
+	}
+	for _, command := range valid {
+		args := strings.Fields(command)
+		if !changeBorder(w, args[0], args[1:]) {
+			t.Error("The command should be accepted:", command)
+		}
+	}
+	border := w.GetBorder()
+	if border.CenterX != 100 || border.CenterZ != -200.5 || border.TargetDiameter != 500 ||
+		border.DamagePerBlock != 1 || border.SafeZone != 10 || border.WarningBlocks != 10 || border.WarningTime != 0 {
+		t.Errorf("Unexpected border %+v", border)
+	}
+
+	invalid := []string{
+		"center NaN 0",
+		"center 0 Inf",
+		"center 3e8 0",
+		"center 1",
+		"set 0",
+		"set -100",
+		"set NaN",
+		"set +Inf",
+		"set 100 -5",
+		"set 100 NaN",
+		"set 100 1e10",
+		"damage -1",
+		"damage NaN",
+		"damage 1 -5",
+		"damage 1 Inf",
+		"warning distance -1",
+		"warning time -5",
+		"warning time NaN",
+		"warning height 5",
+		"grow 10",
+	}
+	for _, command := range invalid {
+		args := strings.Fields(command)
+		if changeBorder(w, args[0], args[1:]) {
+			t.Error("The command should be rejected:", command)
+		}
+	}
+	// the diameter moves during the resizing
+	after := w.GetBorder()
+	after.Diameter, after.Remaining = border.Diameter, border.Remaining
+	if after != border {
+		t.Errorf("The rejected commands should not change the border, got %+v", after)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/olsdavis/goelan/command"
 	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/rcon"
 	"github.com/olsdavis/goelan/server"
 	"os"
 	"strings"
@@ -18,12 +19,27 @@ func main() {
 	go command.ReadInput()
 	go srv.Start()
 
+	// nil if RCON is disabled, so that it never receives
+	var rconCommands <-chan rcon.Command
+	if srv.IsRconEnabled() {
+		if listener, err := rcon.Listen(srv.GetRconAddress(), srv.GetRconPassword()); err != nil {
+			log.Error("Could not start RCON:", err)
+		} else {
+			defer listener.Close()
+			go listener.Serve()
+			rconCommands = listener.Commands()
+		}
+	}
+
 	for srv.IsRunning() {
 		select {
 		case <-srv.ExitChan:
 			break
 		case line := <-command.ConsoleChannel:
 			command.ExecuteCommand(strings.TrimSpace(line), command.ConsoleSender)
+		case cmd := <-rconCommands:
+			command.ExecuteCommand(strings.TrimSpace(cmd.Line), cmd.Sender)
+			cmd.Done()
 		}
 	}
 
@@ -0,0 +1,155 @@
+//go:build ignore
+// +build ignore
+
+// This program generates materials.go from blocks.txt and items.txt.
+// It is invoked by "go generate".
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// entry is a line of blocks.txt or items.txt.
+type entry struct {
+	id         int
+	name       string
+	identifier string
+	hardness   string
+	opacity    string
+	light      string
+	stack      string
+	variants   []variant
+}
+
+type variant struct {
+	metadata int
+	name     string
+}
+
+func main() {
+	blocks := read("blocks.txt", true)
+	items := read("items.txt", false)
+
+	// the items named like their block are suffixed by "Item"
+	names := make(map[string]bool)
+	for _, e := range blocks {
+		names[e.name] = true
+	}
+	for i := range items {
+		if names[items[i].name] {
+			items[i].identifier += "Item"
+		}
+	}
+	entries := append(blocks, items...)
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintln(buf, "// Code generated by \"go run gen.go\"; DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package material")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "var (")
+	for _, e := range entries {
+		fmt.Fprintf(buf, "%v = Material{ID: %v, Name: %q, Hardness: %v, Opacity: %v, LightEmission: %v, StackSize: %v}\n",
+			e.identifier, e.id, e.name, e.hardness, e.opacity, e.light, e.stack)
+	}
+	fmt.Fprintln(buf, ")")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "// materialMap contains all the materials, by ID.")
+	fmt.Fprintln(buf, "var materialMap = map[int]Material{")
+	for _, e := range entries {
+		fmt.Fprintf(buf, "%v.ID: %v,\n", e.identifier, e.identifier)
+	}
+	fmt.Fprintln(buf, "}")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "// variantMap contains the named metadata values, by ID.")
+	fmt.Fprintln(buf, "var variantMap = map[int][]Variant{")
+	for _, e := range entries {
+		if len(e.variants) == 0 {
+			continue
+		}
+		fmt.Fprintf(buf, "%v.ID: {", e.identifier)
+		for _, v := range e.variants {
+			fmt.Fprintf(buf, "{%v, %q}, ", v.metadata, v.name)
+		}
+		fmt.Fprintln(buf, "},")
+	}
+	fmt.Fprintln(buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = ioutil.WriteFile("materials.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// read reads the entries of the given file.
+func read(file string, blocks bool) []entry {
+	f, err := os.Open(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	columns := 6
+	if !blocks {
+		columns = 3
+	}
+	ret := make([]entry, 0)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != columns && len(fields) != columns+1 {
+			log.Fatalf("%v:%v: expected %v columns", file, line, columns)
+		}
+		id, err := strconv.Atoi(fields[0])
+		if err != nil {
+			log.Fatalf("%v:%v: invalid ID: %v", file, line, err)
+		}
+		e := entry{id: id, name: fields[1], identifier: identifier(fields[1])}
+		if blocks {
+			e.hardness, e.opacity, e.light, e.stack = fields[2], fields[3], fields[4], fields[5]
+		} else {
+			e.hardness, e.opacity, e.light, e.stack = "0", "0", "0", fields[2]
+		}
+		if len(fields) > columns {
+			for _, v := range strings.Split(fields[columns], ",") {
+				kv := strings.SplitN(v, ":", 2)
+				metadata, err := strconv.Atoi(kv[0])
+				if err != nil || len(kv) != 2 || metadata < 0 || metadata > 15 {
+					log.Fatalf("%v:%v: invalid variant %q", file, line, v)
+				}
+				e.variants = append(e.variants, variant{metadata, kv[1]})
+			}
+		}
+		ret = append(ret, e)
+	}
+	if err = scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	return ret
+}
+
+// identifier returns the Go identifier of the given name: "stone_slab" => "StoneSlab".
+func identifier(name string) string {
+	ret := ""
+	for _, part := range strings.Split(name, "_") {
+		if part != "" {
+			ret += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return ret
+}
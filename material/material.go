@@ -1,32 +1,176 @@
 package material
 
-var (
-	Stone Material = Material{1, "stone"}
-	Grass = Material{2, "grass"}
-	Dirt = Material{3, "dirt"}
-	Cobblestone = Material{4, "cobblestone"}
-	WoodPlank = Material{5, "planks"}
-	Sapling = Material{6, "sapling"}
-	Bedrock = Material{7, "bedrock"}
+//go:generate go run gen.go
+
+import "strings"
+
+const (
+	// the namespace of the vanilla materials
+	namespace = "minecraft:"
+	// the IDs of the items start after the ones of the blocks
+	firstItemID = 256
+	// the opacity of the blocks which do not let any light through
+	maxOpacity = 15
 )
 
 var (
-	materialMap map[int]Material = map[int]Material{
-		Stone.ID: Stone,
-		Grass.ID: Grass,
-		Dirt.ID: Dirt,
-		Cobblestone.ID: Cobblestone,
-		WoodPlank.ID: WoodPlank,
-		Sapling.ID: Sapling,
-		Bedrock.ID: Bedrock,
-	}
+	// WoodPlank is the former name of Planks.
+	WoodPlank = Planks
+
+	nameMap        = make(map[string]Material)   // the blocks, and the items which are not blocks
+	itemNameMap    = make(map[string]Material)   // the items which are not blocks
+	variantNameMap = make(map[string]stateEntry) // the variants, by name
 )
 
+// stateEntry is a material, with a metadata value.
+type stateEntry struct {
+	mat      Material
+	metadata byte
+}
+
+func init() {
+	for _, mat := range materialMap {
+		if mat.IsBlock() {
+			nameMap[mat.Name] = mat
+		} else {
+			itemNameMap[mat.Name] = mat
+		}
+	}
+	for name, mat := range itemNameMap {
+		if _, ok := nameMap[name]; !ok {
+			nameMap[name] = mat
+		}
+	}
+	for id, variants := range variantMap {
+		for _, v := range variants {
+			// the lowest state wins, if two variants share a name
+			if e, ok := variantNameMap[v.Name]; ok && e.mat.ID < id {
+				continue
+			}
+			variantNameMap[v.Name] = stateEntry{materialMap[id], v.Metadata}
+		}
+	}
+}
+
+// Material struct represents a block or an item of Minecraft 1.12.2.
+// The IDs below 256 are blocks, and may be held as items; the others
+// are only items.
 type Material struct {
-	ID   int
-	Name string
+	ID            int
+	Name          string  // without namespace
+	Hardness      float32 // the time factor to break the block (-1 if unbreakable)
+	Opacity       byte    // the light absorbed by the block, from 0 (transparent) to 15 (opaque)
+	LightEmission byte    // the light emitted by the block, from 0 to 15
+	StackSize     int     // the maximal size of the stacks of its item (0 if it cannot be held)
+}
+
+// Variant struct is a named metadata value of a material (the
+// damage value of an item): the granite is the stone of metadata 1.
+type Variant struct {
+	Metadata byte
+	Name     string
+}
+
+// IsBlock returns true if the material can be placed in the world.
+func (m Material) IsBlock() bool {
+	return m.ID < firstItemID
+}
+
+// IsItem returns true if the material can be held.
+func (m Material) IsItem() bool {
+	return m.StackSize > 0
+}
+
+// IsTransparent returns true if the light goes through the block,
+// even partially.
+func (m Material) IsTransparent() bool {
+	return m.Opacity < maxOpacity
+}
+
+// IsBreakable returns true if the block may be broken in survival mode.
+func (m Material) IsBreakable() bool {
+	return m.Hardness >= 0
+}
+
+// NamespacedName returns the name of the material, with the "minecraft:" namespace.
+func (m Material) NamespacedName() string {
+	return namespace + m.Name
+}
+
+// Variants returns the named metadata values of the material.
+func (m Material) Variants() []Variant {
+	return variantMap[m.ID]
+}
+
+// VariantName returns the name of the given metadata value of the
+// material, or the name of the material if it is not a variant.
+func (m Material) VariantName(metadata byte) string {
+	for _, v := range variantMap[m.ID] {
+		if v.Metadata == metadata {
+			return v.Name
+		}
+	}
+	return m.Name
 }
 
+// String returns the namespaced name of the material.
+func (m Material) String() string {
+	return m.NamespacedName()
+}
+
+// GetById returns the material of the given ID, or
+// a material of ID 0 (air) if there is none.
 func GetById(id int) Material {
 	return materialMap[id]
 }
+
+// Exists returns true if there is a material of the given ID.
+func Exists(id int) bool {
+	_, ok := materialMap[id]
+	return ok
+}
+
+// GetByName returns the material of the given name, with or without
+// the "minecraft:" namespace. The blocks take precedence over the
+// items of the same name (such as "minecraft:wheat").
+func GetByName(name string) (Material, bool) {
+	mat, ok := nameMap[strings.TrimPrefix(name, namespace)]
+	return mat, ok
+}
+
+// GetItemByName is GetByName, but the items take precedence over
+// the blocks of the same name.
+func GetItemByName(name string) (Material, bool) {
+	name = strings.TrimPrefix(name, namespace)
+	if mat, ok := itemNameMap[name]; ok {
+		return mat, true
+	}
+	return GetByName(name)
+}
+
+// GetByState returns the material and the metadata of the given
+// global block state: id << 4 | metadata.
+func GetByState(state int) (Material, byte) {
+	return materialMap[state>>4], byte(state & 0x0F)
+}
+
+// GetVariantByName returns the material and the metadata of the variant
+// of the given name ("minecraft:granite"), or of the material of this
+// name with a metadata of 0.
+func GetVariantByName(name string) (Material, byte, bool) {
+	name = strings.TrimPrefix(name, namespace)
+	if e, ok := variantNameMap[name]; ok {
+		return e.mat, e.metadata, true
+	}
+	mat, ok := GetByName(name)
+	return mat, 0, ok
+}
+
+// All returns all the materials, in no particular order.
+func All() []Material {
+	ret := make([]Material, 0, len(materialMap))
+	for _, mat := range materialMap {
+		ret = append(ret, mat)
+	}
+	return ret
+}
@@ -0,0 +1,60 @@
+package material
+
+import "testing"
+
+func TestRegistry(t *testing.T) {
+	for id, mat := range materialMap {
+		if mat.ID != id {
+			t.Errorf("Material %v registered with ID %v", mat, id)
+		}
+		if !mat.IsBlock() && !mat.IsItem() {
+			t.Errorf("Item %v should be stackable", mat)
+		}
+	}
+	if !Exists(2267) || Exists(253) || Exists(451) {
+		t.Error("Unexpected registered IDs")
+	}
+	if GetById(Bedrock.ID) != Bedrock || Bedrock.IsBreakable() || !Glass.IsTransparent() || Stone.IsTransparent() {
+		t.Error("Unexpected block properties")
+	}
+	if Glowstone.LightEmission != 15 || DiamondSword.StackSize != 1 || EnderPearl.StackSize != 16 {
+		t.Error("Unexpected material properties")
+	}
+}
+
+func TestLookups(t *testing.T) {
+	if mat, ok := GetByName("minecraft:wheat"); !ok || mat != Wheat {
+		t.Error("The blocks should take precedence, got", mat)
+	}
+	if mat, ok := GetItemByName("minecraft:wheat"); !ok || mat != WheatItem {
+		t.Error("The items should take precedence, got", mat)
+	}
+	if mat, ok := GetItemByName("stone"); !ok || mat != Stone {
+		t.Error("The blocks should be found as items, got", mat)
+	}
+	if mat, ok := GetByName("diamond_sword"); !ok || mat.ID != 276 {
+		t.Error("Unexpected item", mat)
+	}
+	if _, ok := GetByName("unknown"); ok {
+		t.Error("Unknown names should not be found")
+	}
+
+	if mat, metadata := GetByState(35<<4 | 14); mat != Wool || metadata != 14 {
+		t.Error("Unexpected state", mat, metadata)
+	}
+	if name := Wool.VariantName(14); name != "red_wool" {
+		t.Error("Unexpected variant name", name)
+	}
+	if name := Glass.VariantName(3); name != "glass" {
+		t.Error("Unexpected variant name", name)
+	}
+	if mat, metadata, ok := GetVariantByName("minecraft:granite"); !ok || mat != Stone || metadata != 1 {
+		t.Error("Unexpected variant", mat, metadata)
+	}
+	if mat, metadata, ok := GetVariantByName("bookshelf"); !ok || mat != Bookshelf || metadata != 0 {
+		t.Error("Unexpected variant", mat, metadata)
+	}
+	if Stone.String() != "minecraft:stone" {
+		t.Error("Unexpected name", Stone.String())
+	}
+}
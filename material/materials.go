@@ -0,0 +1,979 @@
+// Code generated by "go run gen.go"; DO NOT EDIT.
+
+package material
+
+var (
+	Air                        = Material{ID: 0, Name: "air", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	Stone                      = Material{ID: 1, Name: "stone", Hardness: 1.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Grass                      = Material{ID: 2, Name: "grass", Hardness: 0.6, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Dirt                       = Material{ID: 3, Name: "dirt", Hardness: 0.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Cobblestone                = Material{ID: 4, Name: "cobblestone", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Planks                     = Material{ID: 5, Name: "planks", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Sapling                    = Material{ID: 6, Name: "sapling", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Bedrock                    = Material{ID: 7, Name: "bedrock", Hardness: -1, Opacity: 15, LightEmission: 0, StackSize: 64}
+	FlowingWater               = Material{ID: 8, Name: "flowing_water", Hardness: 100, Opacity: 3, LightEmission: 0, StackSize: 0}
+	Water                      = Material{ID: 9, Name: "water", Hardness: 100, Opacity: 3, LightEmission: 0, StackSize: 0}
+	FlowingLava                = Material{ID: 10, Name: "flowing_lava", Hardness: 100, Opacity: 0, LightEmission: 15, StackSize: 0}
+	Lava                       = Material{ID: 11, Name: "lava", Hardness: 100, Opacity: 0, LightEmission: 15, StackSize: 0}
+	Sand                       = Material{ID: 12, Name: "sand", Hardness: 0.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Gravel                     = Material{ID: 13, Name: "gravel", Hardness: 0.6, Opacity: 15, LightEmission: 0, StackSize: 64}
+	GoldOre                    = Material{ID: 14, Name: "gold_ore", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	IronOre                    = Material{ID: 15, Name: "iron_ore", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	CoalOre                    = Material{ID: 16, Name: "coal_ore", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Log                        = Material{ID: 17, Name: "log", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Leaves                     = Material{ID: 18, Name: "leaves", Hardness: 0.2, Opacity: 1, LightEmission: 0, StackSize: 64}
+	Sponge                     = Material{ID: 19, Name: "sponge", Hardness: 0.6, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Glass                      = Material{ID: 20, Name: "glass", Hardness: 0.3, Opacity: 0, LightEmission: 0, StackSize: 64}
+	LapisOre                   = Material{ID: 21, Name: "lapis_ore", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	LapisBlock                 = Material{ID: 22, Name: "lapis_block", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Dispenser                  = Material{ID: 23, Name: "dispenser", Hardness: 3.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Sandstone                  = Material{ID: 24, Name: "sandstone", Hardness: 0.8, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Noteblock                  = Material{ID: 25, Name: "noteblock", Hardness: 0.8, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Bed                        = Material{ID: 26, Name: "bed", Hardness: 0.2, Opacity: 0, LightEmission: 0, StackSize: 0}
+	GoldenRail                 = Material{ID: 27, Name: "golden_rail", Hardness: 0.7, Opacity: 0, LightEmission: 0, StackSize: 64}
+	DetectorRail               = Material{ID: 28, Name: "detector_rail", Hardness: 0.7, Opacity: 0, LightEmission: 0, StackSize: 64}
+	StickyPiston               = Material{ID: 29, Name: "sticky_piston", Hardness: 0.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Web                        = Material{ID: 30, Name: "web", Hardness: 4, Opacity: 1, LightEmission: 0, StackSize: 64}
+	Tallgrass                  = Material{ID: 31, Name: "tallgrass", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Deadbush                   = Material{ID: 32, Name: "deadbush", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Piston                     = Material{ID: 33, Name: "piston", Hardness: 0.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	PistonHead                 = Material{ID: 34, Name: "piston_head", Hardness: 0.5, Opacity: 0, LightEmission: 0, StackSize: 0}
+	Wool                       = Material{ID: 35, Name: "wool", Hardness: 0.8, Opacity: 15, LightEmission: 0, StackSize: 64}
+	PistonExtension            = Material{ID: 36, Name: "piston_extension", Hardness: -1, Opacity: 0, LightEmission: 0, StackSize: 0}
+	YellowFlower               = Material{ID: 37, Name: "yellow_flower", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	RedFlower                  = Material{ID: 38, Name: "red_flower", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BrownMushroom              = Material{ID: 39, Name: "brown_mushroom", Hardness: 0, Opacity: 0, LightEmission: 1, StackSize: 64}
+	RedMushroom                = Material{ID: 40, Name: "red_mushroom", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	GoldBlock                  = Material{ID: 41, Name: "gold_block", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	IronBlock                  = Material{ID: 42, Name: "iron_block", Hardness: 5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	DoubleStoneSlab            = Material{ID: 43, Name: "double_stone_slab", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 0}
+	StoneSlab                  = Material{ID: 44, Name: "stone_slab", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	BrickBlock                 = Material{ID: 45, Name: "brick_block", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Tnt                        = Material{ID: 46, Name: "tnt", Hardness: 0, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Bookshelf                  = Material{ID: 47, Name: "bookshelf", Hardness: 1.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	MossyCobblestone           = Material{ID: 48, Name: "mossy_cobblestone", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Obsidian                   = Material{ID: 49, Name: "obsidian", Hardness: 50, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Torch                      = Material{ID: 50, Name: "torch", Hardness: 0, Opacity: 0, LightEmission: 14, StackSize: 64}
+	Fire                       = Material{ID: 51, Name: "fire", Hardness: 0, Opacity: 0, LightEmission: 15, StackSize: 0}
+	MobSpawner                 = Material{ID: 52, Name: "mob_spawner", Hardness: 5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	OakStairs                  = Material{ID: 53, Name: "oak_stairs", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Chest                      = Material{ID: 54, Name: "chest", Hardness: 2.5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	RedstoneWire               = Material{ID: 55, Name: "redstone_wire", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	DiamondOre                 = Material{ID: 56, Name: "diamond_ore", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	DiamondBlock               = Material{ID: 57, Name: "diamond_block", Hardness: 5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	CraftingTable              = Material{ID: 58, Name: "crafting_table", Hardness: 2.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Wheat                      = Material{ID: 59, Name: "wheat", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	Farmland                   = Material{ID: 60, Name: "farmland", Hardness: 0.6, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Furnace                    = Material{ID: 61, Name: "furnace", Hardness: 3.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	LitFurnace                 = Material{ID: 62, Name: "lit_furnace", Hardness: 3.5, Opacity: 15, LightEmission: 13, StackSize: 0}
+	StandingSign               = Material{ID: 63, Name: "standing_sign", Hardness: 1, Opacity: 0, LightEmission: 0, StackSize: 0}
+	WoodenDoor                 = Material{ID: 64, Name: "wooden_door", Hardness: 3, Opacity: 0, LightEmission: 0, StackSize: 0}
+	Ladder                     = Material{ID: 65, Name: "ladder", Hardness: 0.4, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Rail                       = Material{ID: 66, Name: "rail", Hardness: 0.7, Opacity: 0, LightEmission: 0, StackSize: 64}
+	StoneStairs                = Material{ID: 67, Name: "stone_stairs", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	WallSign                   = Material{ID: 68, Name: "wall_sign", Hardness: 1, Opacity: 0, LightEmission: 0, StackSize: 0}
+	Lever                      = Material{ID: 69, Name: "lever", Hardness: 0.5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	StonePressurePlate         = Material{ID: 70, Name: "stone_pressure_plate", Hardness: 0.5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	IronDoor                   = Material{ID: 71, Name: "iron_door", Hardness: 5, Opacity: 0, LightEmission: 0, StackSize: 0}
+	WoodenPressurePlate        = Material{ID: 72, Name: "wooden_pressure_plate", Hardness: 0.5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	RedstoneOre                = Material{ID: 73, Name: "redstone_ore", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	LitRedstoneOre             = Material{ID: 74, Name: "lit_redstone_ore", Hardness: 3, Opacity: 15, LightEmission: 9, StackSize: 0}
+	UnlitRedstoneTorch         = Material{ID: 75, Name: "unlit_redstone_torch", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	RedstoneTorch              = Material{ID: 76, Name: "redstone_torch", Hardness: 0, Opacity: 0, LightEmission: 7, StackSize: 64}
+	StoneButton                = Material{ID: 77, Name: "stone_button", Hardness: 0.5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	SnowLayer                  = Material{ID: 78, Name: "snow_layer", Hardness: 0.1, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Ice                        = Material{ID: 79, Name: "ice", Hardness: 0.5, Opacity: 3, LightEmission: 0, StackSize: 64}
+	Snow                       = Material{ID: 80, Name: "snow", Hardness: 0.2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Cactus                     = Material{ID: 81, Name: "cactus", Hardness: 0.4, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Clay                       = Material{ID: 82, Name: "clay", Hardness: 0.6, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Reeds                      = Material{ID: 83, Name: "reeds", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	Jukebox                    = Material{ID: 84, Name: "jukebox", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Fence                      = Material{ID: 85, Name: "fence", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Pumpkin                    = Material{ID: 86, Name: "pumpkin", Hardness: 1, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Netherrack                 = Material{ID: 87, Name: "netherrack", Hardness: 0.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	SoulSand                   = Material{ID: 88, Name: "soul_sand", Hardness: 0.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Glowstone                  = Material{ID: 89, Name: "glowstone", Hardness: 0.3, Opacity: 15, LightEmission: 15, StackSize: 64}
+	Portal                     = Material{ID: 90, Name: "portal", Hardness: -1, Opacity: 0, LightEmission: 11, StackSize: 0}
+	LitPumpkin                 = Material{ID: 91, Name: "lit_pumpkin", Hardness: 1, Opacity: 15, LightEmission: 15, StackSize: 64}
+	Cake                       = Material{ID: 92, Name: "cake", Hardness: 0.5, Opacity: 0, LightEmission: 0, StackSize: 0}
+	UnpoweredRepeater          = Material{ID: 93, Name: "unpowered_repeater", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	PoweredRepeater            = Material{ID: 94, Name: "powered_repeater", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	StainedGlass               = Material{ID: 95, Name: "stained_glass", Hardness: 0.3, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Trapdoor                   = Material{ID: 96, Name: "trapdoor", Hardness: 3, Opacity: 0, LightEmission: 0, StackSize: 64}
+	MonsterEgg                 = Material{ID: 97, Name: "monster_egg", Hardness: 0.75, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Stonebrick                 = Material{ID: 98, Name: "stonebrick", Hardness: 1.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	BrownMushroomBlock         = Material{ID: 99, Name: "brown_mushroom_block", Hardness: 0.2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	RedMushroomBlock           = Material{ID: 100, Name: "red_mushroom_block", Hardness: 0.2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	IronBars                   = Material{ID: 101, Name: "iron_bars", Hardness: 5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	GlassPane                  = Material{ID: 102, Name: "glass_pane", Hardness: 0.3, Opacity: 0, LightEmission: 0, StackSize: 64}
+	MelonBlock                 = Material{ID: 103, Name: "melon_block", Hardness: 1, Opacity: 15, LightEmission: 0, StackSize: 64}
+	PumpkinStem                = Material{ID: 104, Name: "pumpkin_stem", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	MelonStem                  = Material{ID: 105, Name: "melon_stem", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	Vine                       = Material{ID: 106, Name: "vine", Hardness: 0.2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	FenceGate                  = Material{ID: 107, Name: "fence_gate", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BrickStairs                = Material{ID: 108, Name: "brick_stairs", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	StoneBrickStairs           = Material{ID: 109, Name: "stone_brick_stairs", Hardness: 1.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Mycelium                   = Material{ID: 110, Name: "mycelium", Hardness: 0.6, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Waterlily                  = Material{ID: 111, Name: "waterlily", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	NetherBrick                = Material{ID: 112, Name: "nether_brick", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	NetherBrickFence           = Material{ID: 113, Name: "nether_brick_fence", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	NetherBrickStairs          = Material{ID: 114, Name: "nether_brick_stairs", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	NetherWart                 = Material{ID: 115, Name: "nether_wart", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	EnchantingTable            = Material{ID: 116, Name: "enchanting_table", Hardness: 5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BrewingStand               = Material{ID: 117, Name: "brewing_stand", Hardness: 0.5, Opacity: 0, LightEmission: 1, StackSize: 0}
+	Cauldron                   = Material{ID: 118, Name: "cauldron", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 0}
+	EndPortal                  = Material{ID: 119, Name: "end_portal", Hardness: -1, Opacity: 0, LightEmission: 15, StackSize: 0}
+	EndPortalFrame             = Material{ID: 120, Name: "end_portal_frame", Hardness: -1, Opacity: 0, LightEmission: 1, StackSize: 64}
+	EndStone                   = Material{ID: 121, Name: "end_stone", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	DragonEgg                  = Material{ID: 122, Name: "dragon_egg", Hardness: 3, Opacity: 0, LightEmission: 1, StackSize: 64}
+	RedstoneLamp               = Material{ID: 123, Name: "redstone_lamp", Hardness: 0.3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	LitRedstoneLamp            = Material{ID: 124, Name: "lit_redstone_lamp", Hardness: 0.3, Opacity: 15, LightEmission: 15, StackSize: 0}
+	DoubleWoodenSlab           = Material{ID: 125, Name: "double_wooden_slab", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 0}
+	WoodenSlab                 = Material{ID: 126, Name: "wooden_slab", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Cocoa                      = Material{ID: 127, Name: "cocoa", Hardness: 0.2, Opacity: 0, LightEmission: 0, StackSize: 0}
+	SandstoneStairs            = Material{ID: 128, Name: "sandstone_stairs", Hardness: 0.8, Opacity: 15, LightEmission: 0, StackSize: 64}
+	EmeraldOre                 = Material{ID: 129, Name: "emerald_ore", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	EnderChest                 = Material{ID: 130, Name: "ender_chest", Hardness: 22.5, Opacity: 0, LightEmission: 7, StackSize: 64}
+	TripwireHook               = Material{ID: 131, Name: "tripwire_hook", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Tripwire                   = Material{ID: 132, Name: "tripwire", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	EmeraldBlock               = Material{ID: 133, Name: "emerald_block", Hardness: 5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	SpruceStairs               = Material{ID: 134, Name: "spruce_stairs", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	BirchStairs                = Material{ID: 135, Name: "birch_stairs", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	JungleStairs               = Material{ID: 136, Name: "jungle_stairs", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	CommandBlock               = Material{ID: 137, Name: "command_block", Hardness: -1, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Beacon                     = Material{ID: 138, Name: "beacon", Hardness: 3, Opacity: 0, LightEmission: 15, StackSize: 64}
+	CobblestoneWall            = Material{ID: 139, Name: "cobblestone_wall", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	FlowerPot                  = Material{ID: 140, Name: "flower_pot", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	Carrots                    = Material{ID: 141, Name: "carrots", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	Potatoes                   = Material{ID: 142, Name: "potatoes", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	WoodenButton               = Material{ID: 143, Name: "wooden_button", Hardness: 0.5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Skull                      = Material{ID: 144, Name: "skull", Hardness: 1, Opacity: 0, LightEmission: 0, StackSize: 0}
+	Anvil                      = Material{ID: 145, Name: "anvil", Hardness: 5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	TrappedChest               = Material{ID: 146, Name: "trapped_chest", Hardness: 2.5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	LightWeightedPressurePlate = Material{ID: 147, Name: "light_weighted_pressure_plate", Hardness: 0.5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	HeavyWeightedPressurePlate = Material{ID: 148, Name: "heavy_weighted_pressure_plate", Hardness: 0.5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	UnpoweredComparator        = Material{ID: 149, Name: "unpowered_comparator", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	PoweredComparator          = Material{ID: 150, Name: "powered_comparator", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	DaylightDetector           = Material{ID: 151, Name: "daylight_detector", Hardness: 0.2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	RedstoneBlock              = Material{ID: 152, Name: "redstone_block", Hardness: 5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	QuartzOre                  = Material{ID: 153, Name: "quartz_ore", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Hopper                     = Material{ID: 154, Name: "hopper", Hardness: 3, Opacity: 0, LightEmission: 0, StackSize: 64}
+	QuartzBlock                = Material{ID: 155, Name: "quartz_block", Hardness: 0.8, Opacity: 15, LightEmission: 0, StackSize: 64}
+	QuartzStairs               = Material{ID: 156, Name: "quartz_stairs", Hardness: 0.8, Opacity: 15, LightEmission: 0, StackSize: 64}
+	ActivatorRail              = Material{ID: 157, Name: "activator_rail", Hardness: 0.7, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Dropper                    = Material{ID: 158, Name: "dropper", Hardness: 3.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	StainedHardenedClay        = Material{ID: 159, Name: "stained_hardened_clay", Hardness: 1.25, Opacity: 15, LightEmission: 0, StackSize: 64}
+	StainedGlassPane           = Material{ID: 160, Name: "stained_glass_pane", Hardness: 0.3, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Leaves2                    = Material{ID: 161, Name: "leaves2", Hardness: 0.2, Opacity: 1, LightEmission: 0, StackSize: 64}
+	Log2                       = Material{ID: 162, Name: "log2", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	AcaciaStairs               = Material{ID: 163, Name: "acacia_stairs", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	DarkOakStairs              = Material{ID: 164, Name: "dark_oak_stairs", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Slime                      = Material{ID: 165, Name: "slime", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Barrier                    = Material{ID: 166, Name: "barrier", Hardness: -1, Opacity: 0, LightEmission: 0, StackSize: 64}
+	IronTrapdoor               = Material{ID: 167, Name: "iron_trapdoor", Hardness: 5, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Prismarine                 = Material{ID: 168, Name: "prismarine", Hardness: 1.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	SeaLantern                 = Material{ID: 169, Name: "sea_lantern", Hardness: 0.3, Opacity: 15, LightEmission: 15, StackSize: 64}
+	HayBlock                   = Material{ID: 170, Name: "hay_block", Hardness: 0.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Carpet                     = Material{ID: 171, Name: "carpet", Hardness: 0.1, Opacity: 0, LightEmission: 0, StackSize: 64}
+	HardenedClay               = Material{ID: 172, Name: "hardened_clay", Hardness: 1.25, Opacity: 15, LightEmission: 0, StackSize: 64}
+	CoalBlock                  = Material{ID: 173, Name: "coal_block", Hardness: 5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	PackedIce                  = Material{ID: 174, Name: "packed_ice", Hardness: 0.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	DoublePlant                = Material{ID: 175, Name: "double_plant", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	StandingBanner             = Material{ID: 176, Name: "standing_banner", Hardness: 1, Opacity: 0, LightEmission: 0, StackSize: 0}
+	WallBanner                 = Material{ID: 177, Name: "wall_banner", Hardness: 1, Opacity: 0, LightEmission: 0, StackSize: 0}
+	DaylightDetectorInverted   = Material{ID: 178, Name: "daylight_detector_inverted", Hardness: 0.2, Opacity: 0, LightEmission: 0, StackSize: 0}
+	RedSandstone               = Material{ID: 179, Name: "red_sandstone", Hardness: 0.8, Opacity: 15, LightEmission: 0, StackSize: 64}
+	RedSandstoneStairs         = Material{ID: 180, Name: "red_sandstone_stairs", Hardness: 0.8, Opacity: 15, LightEmission: 0, StackSize: 64}
+	DoubleStoneSlab2           = Material{ID: 181, Name: "double_stone_slab2", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 0}
+	StoneSlab2                 = Material{ID: 182, Name: "stone_slab2", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	SpruceFenceGate            = Material{ID: 183, Name: "spruce_fence_gate", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BirchFenceGate             = Material{ID: 184, Name: "birch_fence_gate", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	JungleFenceGate            = Material{ID: 185, Name: "jungle_fence_gate", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	DarkOakFenceGate           = Material{ID: 186, Name: "dark_oak_fence_gate", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	AcaciaFenceGate            = Material{ID: 187, Name: "acacia_fence_gate", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	SpruceFence                = Material{ID: 188, Name: "spruce_fence", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BirchFence                 = Material{ID: 189, Name: "birch_fence", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	JungleFence                = Material{ID: 190, Name: "jungle_fence", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	DarkOakFence               = Material{ID: 191, Name: "dark_oak_fence", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	AcaciaFence                = Material{ID: 192, Name: "acacia_fence", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 64}
+	SpruceDoor                 = Material{ID: 193, Name: "spruce_door", Hardness: 3, Opacity: 0, LightEmission: 0, StackSize: 0}
+	BirchDoor                  = Material{ID: 194, Name: "birch_door", Hardness: 3, Opacity: 0, LightEmission: 0, StackSize: 0}
+	JungleDoor                 = Material{ID: 195, Name: "jungle_door", Hardness: 3, Opacity: 0, LightEmission: 0, StackSize: 0}
+	AcaciaDoor                 = Material{ID: 196, Name: "acacia_door", Hardness: 3, Opacity: 0, LightEmission: 0, StackSize: 0}
+	DarkOakDoor                = Material{ID: 197, Name: "dark_oak_door", Hardness: 3, Opacity: 0, LightEmission: 0, StackSize: 0}
+	EndRod                     = Material{ID: 198, Name: "end_rod", Hardness: 0, Opacity: 0, LightEmission: 14, StackSize: 64}
+	ChorusPlant                = Material{ID: 199, Name: "chorus_plant", Hardness: 0.4, Opacity: 0, LightEmission: 0, StackSize: 64}
+	ChorusFlower               = Material{ID: 200, Name: "chorus_flower", Hardness: 0.4, Opacity: 0, LightEmission: 0, StackSize: 64}
+	PurpurBlock                = Material{ID: 201, Name: "purpur_block", Hardness: 1.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	PurpurPillar               = Material{ID: 202, Name: "purpur_pillar", Hardness: 1.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	PurpurStairs               = Material{ID: 203, Name: "purpur_stairs", Hardness: 1.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	PurpurDoubleSlab           = Material{ID: 204, Name: "purpur_double_slab", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 0}
+	PurpurSlab                 = Material{ID: 205, Name: "purpur_slab", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	EndBricks                  = Material{ID: 206, Name: "end_bricks", Hardness: 0.8, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Beetroots                  = Material{ID: 207, Name: "beetroots", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 0}
+	GrassPath                  = Material{ID: 208, Name: "grass_path", Hardness: 0.65, Opacity: 15, LightEmission: 0, StackSize: 64}
+	EndGateway                 = Material{ID: 209, Name: "end_gateway", Hardness: -1, Opacity: 0, LightEmission: 15, StackSize: 0}
+	RepeatingCommandBlock      = Material{ID: 210, Name: "repeating_command_block", Hardness: -1, Opacity: 15, LightEmission: 0, StackSize: 64}
+	ChainCommandBlock          = Material{ID: 211, Name: "chain_command_block", Hardness: -1, Opacity: 15, LightEmission: 0, StackSize: 64}
+	FrostedIce                 = Material{ID: 212, Name: "frosted_ice", Hardness: 0.5, Opacity: 3, LightEmission: 0, StackSize: 0}
+	Magma                      = Material{ID: 213, Name: "magma", Hardness: 0.5, Opacity: 15, LightEmission: 3, StackSize: 64}
+	NetherWartBlock            = Material{ID: 214, Name: "nether_wart_block", Hardness: 1, Opacity: 15, LightEmission: 0, StackSize: 64}
+	RedNetherBrick             = Material{ID: 215, Name: "red_nether_brick", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	BoneBlock                  = Material{ID: 216, Name: "bone_block", Hardness: 2, Opacity: 15, LightEmission: 0, StackSize: 64}
+	StructureVoid              = Material{ID: 217, Name: "structure_void", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Observer                   = Material{ID: 218, Name: "observer", Hardness: 3, Opacity: 15, LightEmission: 0, StackSize: 64}
+	WhiteShulkerBox            = Material{ID: 219, Name: "white_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	OrangeShulkerBox           = Material{ID: 220, Name: "orange_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	MagentaShulkerBox          = Material{ID: 221, Name: "magenta_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	LightBlueShulkerBox        = Material{ID: 222, Name: "light_blue_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	YellowShulkerBox           = Material{ID: 223, Name: "yellow_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	LimeShulkerBox             = Material{ID: 224, Name: "lime_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	PinkShulkerBox             = Material{ID: 225, Name: "pink_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GrayShulkerBox             = Material{ID: 226, Name: "gray_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	SilverShulkerBox           = Material{ID: 227, Name: "silver_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	CyanShulkerBox             = Material{ID: 228, Name: "cyan_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	PurpleShulkerBox           = Material{ID: 229, Name: "purple_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	BlueShulkerBox             = Material{ID: 230, Name: "blue_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	BrownShulkerBox            = Material{ID: 231, Name: "brown_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GreenShulkerBox            = Material{ID: 232, Name: "green_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RedShulkerBox              = Material{ID: 233, Name: "red_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	BlackShulkerBox            = Material{ID: 234, Name: "black_shulker_box", Hardness: 2, Opacity: 0, LightEmission: 0, StackSize: 1}
+	WhiteGlazedTerracotta      = Material{ID: 235, Name: "white_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	OrangeGlazedTerracotta     = Material{ID: 236, Name: "orange_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	MagentaGlazedTerracotta    = Material{ID: 237, Name: "magenta_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	LightBlueGlazedTerracotta  = Material{ID: 238, Name: "light_blue_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	YellowGlazedTerracotta     = Material{ID: 239, Name: "yellow_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	LimeGlazedTerracotta       = Material{ID: 240, Name: "lime_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	PinkGlazedTerracotta       = Material{ID: 241, Name: "pink_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	GrayGlazedTerracotta       = Material{ID: 242, Name: "gray_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	SilverGlazedTerracotta     = Material{ID: 243, Name: "silver_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	CyanGlazedTerracotta       = Material{ID: 244, Name: "cyan_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	PurpleGlazedTerracotta     = Material{ID: 245, Name: "purple_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	BlueGlazedTerracotta       = Material{ID: 246, Name: "blue_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	BrownGlazedTerracotta      = Material{ID: 247, Name: "brown_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	GreenGlazedTerracotta      = Material{ID: 248, Name: "green_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	RedGlazedTerracotta        = Material{ID: 249, Name: "red_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	BlackGlazedTerracotta      = Material{ID: 250, Name: "black_glazed_terracotta", Hardness: 1.4, Opacity: 15, LightEmission: 0, StackSize: 64}
+	Concrete                   = Material{ID: 251, Name: "concrete", Hardness: 1.8, Opacity: 15, LightEmission: 0, StackSize: 64}
+	ConcretePowder             = Material{ID: 252, Name: "concrete_powder", Hardness: 0.5, Opacity: 15, LightEmission: 0, StackSize: 64}
+	StructureBlock             = Material{ID: 255, Name: "structure_block", Hardness: -1, Opacity: 15, LightEmission: 0, StackSize: 64}
+	IronShovel                 = Material{ID: 256, Name: "iron_shovel", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	IronPickaxe                = Material{ID: 257, Name: "iron_pickaxe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	IronAxe                    = Material{ID: 258, Name: "iron_axe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	FlintAndSteel              = Material{ID: 259, Name: "flint_and_steel", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Apple                      = Material{ID: 260, Name: "apple", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Bow                        = Material{ID: 261, Name: "bow", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Arrow                      = Material{ID: 262, Name: "arrow", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Coal                       = Material{ID: 263, Name: "coal", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Diamond                    = Material{ID: 264, Name: "diamond", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	IronIngot                  = Material{ID: 265, Name: "iron_ingot", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	GoldIngot                  = Material{ID: 266, Name: "gold_ingot", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	IronSword                  = Material{ID: 267, Name: "iron_sword", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	WoodenSword                = Material{ID: 268, Name: "wooden_sword", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	WoodenShovel               = Material{ID: 269, Name: "wooden_shovel", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	WoodenPickaxe              = Material{ID: 270, Name: "wooden_pickaxe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	WoodenAxe                  = Material{ID: 271, Name: "wooden_axe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	StoneSword                 = Material{ID: 272, Name: "stone_sword", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	StoneShovel                = Material{ID: 273, Name: "stone_shovel", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	StonePickaxe               = Material{ID: 274, Name: "stone_pickaxe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	StoneAxe                   = Material{ID: 275, Name: "stone_axe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DiamondSword               = Material{ID: 276, Name: "diamond_sword", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DiamondShovel              = Material{ID: 277, Name: "diamond_shovel", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DiamondPickaxe             = Material{ID: 278, Name: "diamond_pickaxe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DiamondAxe                 = Material{ID: 279, Name: "diamond_axe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Stick                      = Material{ID: 280, Name: "stick", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Bowl                       = Material{ID: 281, Name: "bowl", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	MushroomStew               = Material{ID: 282, Name: "mushroom_stew", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GoldenSword                = Material{ID: 283, Name: "golden_sword", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GoldenShovel               = Material{ID: 284, Name: "golden_shovel", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GoldenPickaxe              = Material{ID: 285, Name: "golden_pickaxe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GoldenAxe                  = Material{ID: 286, Name: "golden_axe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	String                     = Material{ID: 287, Name: "string", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Feather                    = Material{ID: 288, Name: "feather", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Gunpowder                  = Material{ID: 289, Name: "gunpowder", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	WoodenHoe                  = Material{ID: 290, Name: "wooden_hoe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	StoneHoe                   = Material{ID: 291, Name: "stone_hoe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	IronHoe                    = Material{ID: 292, Name: "iron_hoe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DiamondHoe                 = Material{ID: 293, Name: "diamond_hoe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GoldenHoe                  = Material{ID: 294, Name: "golden_hoe", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	WheatSeeds                 = Material{ID: 295, Name: "wheat_seeds", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	WheatItem                  = Material{ID: 296, Name: "wheat", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Bread                      = Material{ID: 297, Name: "bread", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	LeatherHelmet              = Material{ID: 298, Name: "leather_helmet", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	LeatherChestplate          = Material{ID: 299, Name: "leather_chestplate", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	LeatherLeggings            = Material{ID: 300, Name: "leather_leggings", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	LeatherBoots               = Material{ID: 301, Name: "leather_boots", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	ChainmailHelmet            = Material{ID: 302, Name: "chainmail_helmet", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	ChainmailChestplate        = Material{ID: 303, Name: "chainmail_chestplate", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	ChainmailLeggings          = Material{ID: 304, Name: "chainmail_leggings", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	ChainmailBoots             = Material{ID: 305, Name: "chainmail_boots", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	IronHelmet                 = Material{ID: 306, Name: "iron_helmet", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	IronChestplate             = Material{ID: 307, Name: "iron_chestplate", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	IronLeggings               = Material{ID: 308, Name: "iron_leggings", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	IronBoots                  = Material{ID: 309, Name: "iron_boots", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DiamondHelmet              = Material{ID: 310, Name: "diamond_helmet", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DiamondChestplate          = Material{ID: 311, Name: "diamond_chestplate", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DiamondLeggings            = Material{ID: 312, Name: "diamond_leggings", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DiamondBoots               = Material{ID: 313, Name: "diamond_boots", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GoldenHelmet               = Material{ID: 314, Name: "golden_helmet", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GoldenChestplate           = Material{ID: 315, Name: "golden_chestplate", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GoldenLeggings             = Material{ID: 316, Name: "golden_leggings", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GoldenBoots                = Material{ID: 317, Name: "golden_boots", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Flint                      = Material{ID: 318, Name: "flint", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Porkchop                   = Material{ID: 319, Name: "porkchop", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	CookedPorkchop             = Material{ID: 320, Name: "cooked_porkchop", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Painting                   = Material{ID: 321, Name: "painting", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	GoldenApple                = Material{ID: 322, Name: "golden_apple", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Sign                       = Material{ID: 323, Name: "sign", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 16}
+	WoodenDoorItem             = Material{ID: 324, Name: "wooden_door", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Bucket                     = Material{ID: 325, Name: "bucket", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 16}
+	WaterBucket                = Material{ID: 326, Name: "water_bucket", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	LavaBucket                 = Material{ID: 327, Name: "lava_bucket", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Minecart                   = Material{ID: 328, Name: "minecart", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Saddle                     = Material{ID: 329, Name: "saddle", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	IronDoorItem               = Material{ID: 330, Name: "iron_door", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Redstone                   = Material{ID: 331, Name: "redstone", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Snowball                   = Material{ID: 332, Name: "snowball", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 16}
+	Boat                       = Material{ID: 333, Name: "boat", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Leather                    = Material{ID: 334, Name: "leather", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	MilkBucket                 = Material{ID: 335, Name: "milk_bucket", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Brick                      = Material{ID: 336, Name: "brick", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	ClayBall                   = Material{ID: 337, Name: "clay_ball", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	ReedsItem                  = Material{ID: 338, Name: "reeds", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Paper                      = Material{ID: 339, Name: "paper", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Book                       = Material{ID: 340, Name: "book", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	SlimeBall                  = Material{ID: 341, Name: "slime_ball", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	ChestMinecart              = Material{ID: 342, Name: "chest_minecart", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	FurnaceMinecart            = Material{ID: 343, Name: "furnace_minecart", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Egg                        = Material{ID: 344, Name: "egg", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 16}
+	Compass                    = Material{ID: 345, Name: "compass", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	FishingRod                 = Material{ID: 346, Name: "fishing_rod", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Clock                      = Material{ID: 347, Name: "clock", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	GlowstoneDust              = Material{ID: 348, Name: "glowstone_dust", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Fish                       = Material{ID: 349, Name: "fish", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	CookedFish                 = Material{ID: 350, Name: "cooked_fish", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Dye                        = Material{ID: 351, Name: "dye", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Bone                       = Material{ID: 352, Name: "bone", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Sugar                      = Material{ID: 353, Name: "sugar", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	CakeItem                   = Material{ID: 354, Name: "cake", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	BedItem                    = Material{ID: 355, Name: "bed", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Repeater                   = Material{ID: 356, Name: "repeater", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Cookie                     = Material{ID: 357, Name: "cookie", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	FilledMap                  = Material{ID: 358, Name: "filled_map", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Shears                     = Material{ID: 359, Name: "shears", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Melon                      = Material{ID: 360, Name: "melon", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	PumpkinSeeds               = Material{ID: 361, Name: "pumpkin_seeds", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	MelonSeeds                 = Material{ID: 362, Name: "melon_seeds", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Beef                       = Material{ID: 363, Name: "beef", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	CookedBeef                 = Material{ID: 364, Name: "cooked_beef", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Chicken                    = Material{ID: 365, Name: "chicken", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	CookedChicken              = Material{ID: 366, Name: "cooked_chicken", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	RottenFlesh                = Material{ID: 367, Name: "rotten_flesh", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	EnderPearl                 = Material{ID: 368, Name: "ender_pearl", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 16}
+	BlazeRod                   = Material{ID: 369, Name: "blaze_rod", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	GhastTear                  = Material{ID: 370, Name: "ghast_tear", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	GoldNugget                 = Material{ID: 371, Name: "gold_nugget", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	NetherWartItem             = Material{ID: 372, Name: "nether_wart", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Potion                     = Material{ID: 373, Name: "potion", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GlassBottle                = Material{ID: 374, Name: "glass_bottle", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	SpiderEye                  = Material{ID: 375, Name: "spider_eye", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	FermentedSpiderEye         = Material{ID: 376, Name: "fermented_spider_eye", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BlazePowder                = Material{ID: 377, Name: "blaze_powder", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	MagmaCream                 = Material{ID: 378, Name: "magma_cream", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BrewingStandItem           = Material{ID: 379, Name: "brewing_stand", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	CauldronItem               = Material{ID: 380, Name: "cauldron", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	EnderEye                   = Material{ID: 381, Name: "ender_eye", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	SpeckledMelon              = Material{ID: 382, Name: "speckled_melon", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	SpawnEgg                   = Material{ID: 383, Name: "spawn_egg", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	ExperienceBottle           = Material{ID: 384, Name: "experience_bottle", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	FireCharge                 = Material{ID: 385, Name: "fire_charge", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	WritableBook               = Material{ID: 386, Name: "writable_book", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	WrittenBook                = Material{ID: 387, Name: "written_book", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 16}
+	Emerald                    = Material{ID: 388, Name: "emerald", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	ItemFrame                  = Material{ID: 389, Name: "item_frame", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	FlowerPotItem              = Material{ID: 390, Name: "flower_pot", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Carrot                     = Material{ID: 391, Name: "carrot", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Potato                     = Material{ID: 392, Name: "potato", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BakedPotato                = Material{ID: 393, Name: "baked_potato", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	PoisonousPotato            = Material{ID: 394, Name: "poisonous_potato", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Map                        = Material{ID: 395, Name: "map", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	GoldenCarrot               = Material{ID: 396, Name: "golden_carrot", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	SkullItem                  = Material{ID: 397, Name: "skull", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	CarrotOnAStick             = Material{ID: 398, Name: "carrot_on_a_stick", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	NetherStar                 = Material{ID: 399, Name: "nether_star", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	PumpkinPie                 = Material{ID: 400, Name: "pumpkin_pie", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Fireworks                  = Material{ID: 401, Name: "fireworks", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	FireworkCharge             = Material{ID: 402, Name: "firework_charge", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	EnchantedBook              = Material{ID: 403, Name: "enchanted_book", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Comparator                 = Material{ID: 404, Name: "comparator", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Netherbrick                = Material{ID: 405, Name: "netherbrick", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Quartz                     = Material{ID: 406, Name: "quartz", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	TntMinecart                = Material{ID: 407, Name: "tnt_minecart", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	HopperMinecart             = Material{ID: 408, Name: "hopper_minecart", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	PrismarineShard            = Material{ID: 409, Name: "prismarine_shard", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	PrismarineCrystals         = Material{ID: 410, Name: "prismarine_crystals", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Rabbit                     = Material{ID: 411, Name: "rabbit", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	CookedRabbit               = Material{ID: 412, Name: "cooked_rabbit", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	RabbitStew                 = Material{ID: 413, Name: "rabbit_stew", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RabbitFoot                 = Material{ID: 414, Name: "rabbit_foot", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	RabbitHide                 = Material{ID: 415, Name: "rabbit_hide", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	ArmorStand                 = Material{ID: 416, Name: "armor_stand", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 16}
+	IronHorseArmor             = Material{ID: 417, Name: "iron_horse_armor", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	GoldenHorseArmor           = Material{ID: 418, Name: "golden_horse_armor", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DiamondHorseArmor          = Material{ID: 419, Name: "diamond_horse_armor", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Lead                       = Material{ID: 420, Name: "lead", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	NameTag                    = Material{ID: 421, Name: "name_tag", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	CommandBlockMinecart       = Material{ID: 422, Name: "command_block_minecart", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Mutton                     = Material{ID: 423, Name: "mutton", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	CookedMutton               = Material{ID: 424, Name: "cooked_mutton", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Banner                     = Material{ID: 425, Name: "banner", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 16}
+	EndCrystal                 = Material{ID: 426, Name: "end_crystal", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	SpruceDoorItem             = Material{ID: 427, Name: "spruce_door", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BirchDoorItem              = Material{ID: 428, Name: "birch_door", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	JungleDoorItem             = Material{ID: 429, Name: "jungle_door", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	AcaciaDoorItem             = Material{ID: 430, Name: "acacia_door", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	DarkOakDoorItem            = Material{ID: 431, Name: "dark_oak_door", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	ChorusFruit                = Material{ID: 432, Name: "chorus_fruit", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	ChorusFruitPopped          = Material{ID: 433, Name: "chorus_fruit_popped", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	Beetroot                   = Material{ID: 434, Name: "beetroot", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BeetrootSeeds              = Material{ID: 435, Name: "beetroot_seeds", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	BeetrootSoup               = Material{ID: 436, Name: "beetroot_soup", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DragonBreath               = Material{ID: 437, Name: "dragon_breath", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	SplashPotion               = Material{ID: 438, Name: "splash_potion", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	SpectralArrow              = Material{ID: 439, Name: "spectral_arrow", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	TippedArrow                = Material{ID: 440, Name: "tipped_arrow", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	LingeringPotion            = Material{ID: 441, Name: "lingering_potion", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Shield                     = Material{ID: 442, Name: "shield", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Elytra                     = Material{ID: 443, Name: "elytra", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	SpruceBoat                 = Material{ID: 444, Name: "spruce_boat", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	BirchBoat                  = Material{ID: 445, Name: "birch_boat", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	JungleBoat                 = Material{ID: 446, Name: "jungle_boat", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	AcaciaBoat                 = Material{ID: 447, Name: "acacia_boat", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	DarkOakBoat                = Material{ID: 448, Name: "dark_oak_boat", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	TotemOfUndying             = Material{ID: 449, Name: "totem_of_undying", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	ShulkerShell               = Material{ID: 450, Name: "shulker_shell", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	IronNugget                 = Material{ID: 452, Name: "iron_nugget", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 64}
+	KnowledgeBook              = Material{ID: 453, Name: "knowledge_book", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Record13                   = Material{ID: 2256, Name: "record_13", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RecordCat                  = Material{ID: 2257, Name: "record_cat", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RecordBlocks               = Material{ID: 2258, Name: "record_blocks", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RecordChirp                = Material{ID: 2259, Name: "record_chirp", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RecordFar                  = Material{ID: 2260, Name: "record_far", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RecordMall                 = Material{ID: 2261, Name: "record_mall", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RecordMellohi              = Material{ID: 2262, Name: "record_mellohi", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RecordStal                 = Material{ID: 2263, Name: "record_stal", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RecordStrad                = Material{ID: 2264, Name: "record_strad", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RecordWard                 = Material{ID: 2265, Name: "record_ward", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	Record11                   = Material{ID: 2266, Name: "record_11", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+	RecordWait                 = Material{ID: 2267, Name: "record_wait", Hardness: 0, Opacity: 0, LightEmission: 0, StackSize: 1}
+)
+
+// materialMap contains all the materials, by ID.
+var materialMap = map[int]Material{
+	Air.ID:                        Air,
+	Stone.ID:                      Stone,
+	Grass.ID:                      Grass,
+	Dirt.ID:                       Dirt,
+	Cobblestone.ID:                Cobblestone,
+	Planks.ID:                     Planks,
+	Sapling.ID:                    Sapling,
+	Bedrock.ID:                    Bedrock,
+	FlowingWater.ID:               FlowingWater,
+	Water.ID:                      Water,
+	FlowingLava.ID:                FlowingLava,
+	Lava.ID:                       Lava,
+	Sand.ID:                       Sand,
+	Gravel.ID:                     Gravel,
+	GoldOre.ID:                    GoldOre,
+	IronOre.ID:                    IronOre,
+	CoalOre.ID:                    CoalOre,
+	Log.ID:                        Log,
+	Leaves.ID:                     Leaves,
+	Sponge.ID:                     Sponge,
+	Glass.ID:                      Glass,
+	LapisOre.ID:                   LapisOre,
+	LapisBlock.ID:                 LapisBlock,
+	Dispenser.ID:                  Dispenser,
+	Sandstone.ID:                  Sandstone,
+	Noteblock.ID:                  Noteblock,
+	Bed.ID:                        Bed,
+	GoldenRail.ID:                 GoldenRail,
+	DetectorRail.ID:               DetectorRail,
+	StickyPiston.ID:               StickyPiston,
+	Web.ID:                        Web,
+	Tallgrass.ID:                  Tallgrass,
+	Deadbush.ID:                   Deadbush,
+	Piston.ID:                     Piston,
+	PistonHead.ID:                 PistonHead,
+	Wool.ID:                       Wool,
+	PistonExtension.ID:            PistonExtension,
+	YellowFlower.ID:               YellowFlower,
+	RedFlower.ID:                  RedFlower,
+	BrownMushroom.ID:              BrownMushroom,
+	RedMushroom.ID:                RedMushroom,
+	GoldBlock.ID:                  GoldBlock,
+	IronBlock.ID:                  IronBlock,
+	DoubleStoneSlab.ID:            DoubleStoneSlab,
+	StoneSlab.ID:                  StoneSlab,
+	BrickBlock.ID:                 BrickBlock,
+	Tnt.ID:                        Tnt,
+	Bookshelf.ID:                  Bookshelf,
+	MossyCobblestone.ID:           MossyCobblestone,
+	Obsidian.ID:                   Obsidian,
+	Torch.ID:                      Torch,
+	Fire.ID:                       Fire,
+	MobSpawner.ID:                 MobSpawner,
+	OakStairs.ID:                  OakStairs,
+	Chest.ID:                      Chest,
+	RedstoneWire.ID:               RedstoneWire,
+	DiamondOre.ID:                 DiamondOre,
+	DiamondBlock.ID:               DiamondBlock,
+	CraftingTable.ID:              CraftingTable,
+	Wheat.ID:                      Wheat,
+	Farmland.ID:                   Farmland,
+	Furnace.ID:                    Furnace,
+	LitFurnace.ID:                 LitFurnace,
+	StandingSign.ID:               StandingSign,
+	WoodenDoor.ID:                 WoodenDoor,
+	Ladder.ID:                     Ladder,
+	Rail.ID:                       Rail,
+	StoneStairs.ID:                StoneStairs,
+	WallSign.ID:                   WallSign,
+	Lever.ID:                      Lever,
+	StonePressurePlate.ID:         StonePressurePlate,
+	IronDoor.ID:                   IronDoor,
+	WoodenPressurePlate.ID:        WoodenPressurePlate,
+	RedstoneOre.ID:                RedstoneOre,
+	LitRedstoneOre.ID:             LitRedstoneOre,
+	UnlitRedstoneTorch.ID:         UnlitRedstoneTorch,
+	RedstoneTorch.ID:              RedstoneTorch,
+	StoneButton.ID:                StoneButton,
+	SnowLayer.ID:                  SnowLayer,
+	Ice.ID:                        Ice,
+	Snow.ID:                       Snow,
+	Cactus.ID:                     Cactus,
+	Clay.ID:                       Clay,
+	Reeds.ID:                      Reeds,
+	Jukebox.ID:                    Jukebox,
+	Fence.ID:                      Fence,
+	Pumpkin.ID:                    Pumpkin,
+	Netherrack.ID:                 Netherrack,
+	SoulSand.ID:                   SoulSand,
+	Glowstone.ID:                  Glowstone,
+	Portal.ID:                     Portal,
+	LitPumpkin.ID:                 LitPumpkin,
+	Cake.ID:                       Cake,
+	UnpoweredRepeater.ID:          UnpoweredRepeater,
+	PoweredRepeater.ID:            PoweredRepeater,
+	StainedGlass.ID:               StainedGlass,
+	Trapdoor.ID:                   Trapdoor,
+	MonsterEgg.ID:                 MonsterEgg,
+	Stonebrick.ID:                 Stonebrick,
+	BrownMushroomBlock.ID:         BrownMushroomBlock,
+	RedMushroomBlock.ID:           RedMushroomBlock,
+	IronBars.ID:                   IronBars,
+	GlassPane.ID:                  GlassPane,
+	MelonBlock.ID:                 MelonBlock,
+	PumpkinStem.ID:                PumpkinStem,
+	MelonStem.ID:                  MelonStem,
+	Vine.ID:                       Vine,
+	FenceGate.ID:                  FenceGate,
+	BrickStairs.ID:                BrickStairs,
+	StoneBrickStairs.ID:           StoneBrickStairs,
+	Mycelium.ID:                   Mycelium,
+	Waterlily.ID:                  Waterlily,
+	NetherBrick.ID:                NetherBrick,
+	NetherBrickFence.ID:           NetherBrickFence,
+	NetherBrickStairs.ID:          NetherBrickStairs,
+	NetherWart.ID:                 NetherWart,
+	EnchantingTable.ID:            EnchantingTable,
+	BrewingStand.ID:               BrewingStand,
+	Cauldron.ID:                   Cauldron,
+	EndPortal.ID:                  EndPortal,
+	EndPortalFrame.ID:             EndPortalFrame,
+	EndStone.ID:                   EndStone,
+	DragonEgg.ID:                  DragonEgg,
+	RedstoneLamp.ID:               RedstoneLamp,
+	LitRedstoneLamp.ID:            LitRedstoneLamp,
+	DoubleWoodenSlab.ID:           DoubleWoodenSlab,
+	WoodenSlab.ID:                 WoodenSlab,
+	Cocoa.ID:                      Cocoa,
+	SandstoneStairs.ID:            SandstoneStairs,
+	EmeraldOre.ID:                 EmeraldOre,
+	EnderChest.ID:                 EnderChest,
+	TripwireHook.ID:               TripwireHook,
+	Tripwire.ID:                   Tripwire,
+	EmeraldBlock.ID:               EmeraldBlock,
+	SpruceStairs.ID:               SpruceStairs,
+	BirchStairs.ID:                BirchStairs,
+	JungleStairs.ID:               JungleStairs,
+	CommandBlock.ID:               CommandBlock,
+	Beacon.ID:                     Beacon,
+	CobblestoneWall.ID:            CobblestoneWall,
+	FlowerPot.ID:                  FlowerPot,
+	Carrots.ID:                    Carrots,
+	Potatoes.ID:                   Potatoes,
+	WoodenButton.ID:               WoodenButton,
+	Skull.ID:                      Skull,
+	Anvil.ID:                      Anvil,
+	TrappedChest.ID:               TrappedChest,
+	LightWeightedPressurePlate.ID: LightWeightedPressurePlate,
+	HeavyWeightedPressurePlate.ID: HeavyWeightedPressurePlate,
+	UnpoweredComparator.ID:        UnpoweredComparator,
+	PoweredComparator.ID:          PoweredComparator,
+	DaylightDetector.ID:           DaylightDetector,
+	RedstoneBlock.ID:              RedstoneBlock,
+	QuartzOre.ID:                  QuartzOre,
+	Hopper.ID:                     Hopper,
+	QuartzBlock.ID:                QuartzBlock,
+	QuartzStairs.ID:               QuartzStairs,
+	ActivatorRail.ID:              ActivatorRail,
+	Dropper.ID:                    Dropper,
+	StainedHardenedClay.ID:        StainedHardenedClay,
+	StainedGlassPane.ID:           StainedGlassPane,
+	Leaves2.ID:                    Leaves2,
+	Log2.ID:                       Log2,
+	AcaciaStairs.ID:               AcaciaStairs,
+	DarkOakStairs.ID:              DarkOakStairs,
+	Slime.ID:                      Slime,
+	Barrier.ID:                    Barrier,
+	IronTrapdoor.ID:               IronTrapdoor,
+	Prismarine.ID:                 Prismarine,
+	SeaLantern.ID:                 SeaLantern,
+	HayBlock.ID:                   HayBlock,
+	Carpet.ID:                     Carpet,
+	HardenedClay.ID:               HardenedClay,
+	CoalBlock.ID:                  CoalBlock,
+	PackedIce.ID:                  PackedIce,
+	DoublePlant.ID:                DoublePlant,
+	StandingBanner.ID:             StandingBanner,
+	WallBanner.ID:                 WallBanner,
+	DaylightDetectorInverted.ID:   DaylightDetectorInverted,
+	RedSandstone.ID:               RedSandstone,
+	RedSandstoneStairs.ID:         RedSandstoneStairs,
+	DoubleStoneSlab2.ID:           DoubleStoneSlab2,
+	StoneSlab2.ID:                 StoneSlab2,
+	SpruceFenceGate.ID:            SpruceFenceGate,
+	BirchFenceGate.ID:             BirchFenceGate,
+	JungleFenceGate.ID:            JungleFenceGate,
+	DarkOakFenceGate.ID:           DarkOakFenceGate,
+	AcaciaFenceGate.ID:            AcaciaFenceGate,
+	SpruceFence.ID:                SpruceFence,
+	BirchFence.ID:                 BirchFence,
+	JungleFence.ID:                JungleFence,
+	DarkOakFence.ID:               DarkOakFence,
+	AcaciaFence.ID:                AcaciaFence,
+	SpruceDoor.ID:                 SpruceDoor,
+	BirchDoor.ID:                  BirchDoor,
+	JungleDoor.ID:                 JungleDoor,
+	AcaciaDoor.ID:                 AcaciaDoor,
+	DarkOakDoor.ID:                DarkOakDoor,
+	EndRod.ID:                     EndRod,
+	ChorusPlant.ID:                ChorusPlant,
+	ChorusFlower.ID:               ChorusFlower,
+	PurpurBlock.ID:                PurpurBlock,
+	PurpurPillar.ID:               PurpurPillar,
+	PurpurStairs.ID:               PurpurStairs,
+	PurpurDoubleSlab.ID:           PurpurDoubleSlab,
+	PurpurSlab.ID:                 PurpurSlab,
+	EndBricks.ID:                  EndBricks,
+	Beetroots.ID:                  Beetroots,
+	GrassPath.ID:                  GrassPath,
+	EndGateway.ID:                 EndGateway,
+	RepeatingCommandBlock.ID:      RepeatingCommandBlock,
+	ChainCommandBlock.ID:          ChainCommandBlock,
+	FrostedIce.ID:                 FrostedIce,
+	Magma.ID:                      Magma,
+	NetherWartBlock.ID:            NetherWartBlock,
+	RedNetherBrick.ID:             RedNetherBrick,
+	BoneBlock.ID:                  BoneBlock,
+	StructureVoid.ID:              StructureVoid,
+	Observer.ID:                   Observer,
+	WhiteShulkerBox.ID:            WhiteShulkerBox,
+	OrangeShulkerBox.ID:           OrangeShulkerBox,
+	MagentaShulkerBox.ID:          MagentaShulkerBox,
+	LightBlueShulkerBox.ID:        LightBlueShulkerBox,
+	YellowShulkerBox.ID:           YellowShulkerBox,
+	LimeShulkerBox.ID:             LimeShulkerBox,
+	PinkShulkerBox.ID:             PinkShulkerBox,
+	GrayShulkerBox.ID:             GrayShulkerBox,
+	SilverShulkerBox.ID:           SilverShulkerBox,
+	CyanShulkerBox.ID:             CyanShulkerBox,
+	PurpleShulkerBox.ID:           PurpleShulkerBox,
+	BlueShulkerBox.ID:             BlueShulkerBox,
+	BrownShulkerBox.ID:            BrownShulkerBox,
+	GreenShulkerBox.ID:            GreenShulkerBox,
+	RedShulkerBox.ID:              RedShulkerBox,
+	BlackShulkerBox.ID:            BlackShulkerBox,
+	WhiteGlazedTerracotta.ID:      WhiteGlazedTerracotta,
+	OrangeGlazedTerracotta.ID:     OrangeGlazedTerracotta,
+	MagentaGlazedTerracotta.ID:    MagentaGlazedTerracotta,
+	LightBlueGlazedTerracotta.ID:  LightBlueGlazedTerracotta,
+	YellowGlazedTerracotta.ID:     YellowGlazedTerracotta,
+	LimeGlazedTerracotta.ID:       LimeGlazedTerracotta,
+	PinkGlazedTerracotta.ID:       PinkGlazedTerracotta,
+	GrayGlazedTerracotta.ID:       GrayGlazedTerracotta,
+	SilverGlazedTerracotta.ID:     SilverGlazedTerracotta,
+	CyanGlazedTerracotta.ID:       CyanGlazedTerracotta,
+	PurpleGlazedTerracotta.ID:     PurpleGlazedTerracotta,
+	BlueGlazedTerracotta.ID:       BlueGlazedTerracotta,
+	BrownGlazedTerracotta.ID:      BrownGlazedTerracotta,
+	GreenGlazedTerracotta.ID:      GreenGlazedTerracotta,
+	RedGlazedTerracotta.ID:        RedGlazedTerracotta,
+	BlackGlazedTerracotta.ID:      BlackGlazedTerracotta,
+	Concrete.ID:                   Concrete,
+	ConcretePowder.ID:             ConcretePowder,
+	StructureBlock.ID:             StructureBlock,
+	IronShovel.ID:                 IronShovel,
+	IronPickaxe.ID:                IronPickaxe,
+	IronAxe.ID:                    IronAxe,
+	FlintAndSteel.ID:              FlintAndSteel,
+	Apple.ID:                      Apple,
+	Bow.ID:                        Bow,
+	Arrow.ID:                      Arrow,
+	Coal.ID:                       Coal,
+	Diamond.ID:                    Diamond,
+	IronIngot.ID:                  IronIngot,
+	GoldIngot.ID:                  GoldIngot,
+	IronSword.ID:                  IronSword,
+	WoodenSword.ID:                WoodenSword,
+	WoodenShovel.ID:               WoodenShovel,
+	WoodenPickaxe.ID:              WoodenPickaxe,
+	WoodenAxe.ID:                  WoodenAxe,
+	StoneSword.ID:                 StoneSword,
+	StoneShovel.ID:                StoneShovel,
+	StonePickaxe.ID:               StonePickaxe,
+	StoneAxe.ID:                   StoneAxe,
+	DiamondSword.ID:               DiamondSword,
+	DiamondShovel.ID:              DiamondShovel,
+	DiamondPickaxe.ID:             DiamondPickaxe,
+	DiamondAxe.ID:                 DiamondAxe,
+	Stick.ID:                      Stick,
+	Bowl.ID:                       Bowl,
+	MushroomStew.ID:               MushroomStew,
+	GoldenSword.ID:                GoldenSword,
+	GoldenShovel.ID:               GoldenShovel,
+	GoldenPickaxe.ID:              GoldenPickaxe,
+	GoldenAxe.ID:                  GoldenAxe,
+	String.ID:                     String,
+	Feather.ID:                    Feather,
+	Gunpowder.ID:                  Gunpowder,
+	WoodenHoe.ID:                  WoodenHoe,
+	StoneHoe.ID:                   StoneHoe,
+	IronHoe.ID:                    IronHoe,
+	DiamondHoe.ID:                 DiamondHoe,
+	GoldenHoe.ID:                  GoldenHoe,
+	WheatSeeds.ID:                 WheatSeeds,
+	WheatItem.ID:                  WheatItem,
+	Bread.ID:                      Bread,
+	LeatherHelmet.ID:              LeatherHelmet,
+	LeatherChestplate.ID:          LeatherChestplate,
+	LeatherLeggings.ID:            LeatherLeggings,
+	LeatherBoots.ID:               LeatherBoots,
+	ChainmailHelmet.ID:            ChainmailHelmet,
+	ChainmailChestplate.ID:        ChainmailChestplate,
+	ChainmailLeggings.ID:          ChainmailLeggings,
+	ChainmailBoots.ID:             ChainmailBoots,
+	IronHelmet.ID:                 IronHelmet,
+	IronChestplate.ID:             IronChestplate,
+	IronLeggings.ID:               IronLeggings,
+	IronBoots.ID:                  IronBoots,
+	DiamondHelmet.ID:              DiamondHelmet,
+	DiamondChestplate.ID:          DiamondChestplate,
+	DiamondLeggings.ID:            DiamondLeggings,
+	DiamondBoots.ID:               DiamondBoots,
+	GoldenHelmet.ID:               GoldenHelmet,
+	GoldenChestplate.ID:           GoldenChestplate,
+	GoldenLeggings.ID:             GoldenLeggings,
+	GoldenBoots.ID:                GoldenBoots,
+	Flint.ID:                      Flint,
+	Porkchop.ID:                   Porkchop,
+	CookedPorkchop.ID:             CookedPorkchop,
+	Painting.ID:                   Painting,
+	GoldenApple.ID:                GoldenApple,
+	Sign.ID:                       Sign,
+	WoodenDoorItem.ID:             WoodenDoorItem,
+	Bucket.ID:                     Bucket,
+	WaterBucket.ID:                WaterBucket,
+	LavaBucket.ID:                 LavaBucket,
+	Minecart.ID:                   Minecart,
+	Saddle.ID:                     Saddle,
+	IronDoorItem.ID:               IronDoorItem,
+	Redstone.ID:                   Redstone,
+	Snowball.ID:                   Snowball,
+	Boat.ID:                       Boat,
+	Leather.ID:                    Leather,
+	MilkBucket.ID:                 MilkBucket,
+	Brick.ID:                      Brick,
+	ClayBall.ID:                   ClayBall,
+	ReedsItem.ID:                  ReedsItem,
+	Paper.ID:                      Paper,
+	Book.ID:                       Book,
+	SlimeBall.ID:                  SlimeBall,
+	ChestMinecart.ID:              ChestMinecart,
+	FurnaceMinecart.ID:            FurnaceMinecart,
+	Egg.ID:                        Egg,
+	Compass.ID:                    Compass,
+	FishingRod.ID:                 FishingRod,
+	Clock.ID:                      Clock,
+	GlowstoneDust.ID:              GlowstoneDust,
+	Fish.ID:                       Fish,
+	CookedFish.ID:                 CookedFish,
+	Dye.ID:                        Dye,
+	Bone.ID:                       Bone,
+	Sugar.ID:                      Sugar,
+	CakeItem.ID:                   CakeItem,
+	BedItem.ID:                    BedItem,
+	Repeater.ID:                   Repeater,
+	Cookie.ID:                     Cookie,
+	FilledMap.ID:                  FilledMap,
+	Shears.ID:                     Shears,
+	Melon.ID:                      Melon,
+	PumpkinSeeds.ID:               PumpkinSeeds,
+	MelonSeeds.ID:                 MelonSeeds,
+	Beef.ID:                       Beef,
+	CookedBeef.ID:                 CookedBeef,
+	Chicken.ID:                    Chicken,
+	CookedChicken.ID:              CookedChicken,
+	RottenFlesh.ID:                RottenFlesh,
+	EnderPearl.ID:                 EnderPearl,
+	BlazeRod.ID:                   BlazeRod,
+	GhastTear.ID:                  GhastTear,
+	GoldNugget.ID:                 GoldNugget,
+	NetherWartItem.ID:             NetherWartItem,
+	Potion.ID:                     Potion,
+	GlassBottle.ID:                GlassBottle,
+	SpiderEye.ID:                  SpiderEye,
+	FermentedSpiderEye.ID:         FermentedSpiderEye,
+	BlazePowder.ID:                BlazePowder,
+	MagmaCream.ID:                 MagmaCream,
+	BrewingStandItem.ID:           BrewingStandItem,
+	CauldronItem.ID:               CauldronItem,
+	EnderEye.ID:                   EnderEye,
+	SpeckledMelon.ID:              SpeckledMelon,
+	SpawnEgg.ID:                   SpawnEgg,
+	ExperienceBottle.ID:           ExperienceBottle,
+	FireCharge.ID:                 FireCharge,
+	WritableBook.ID:               WritableBook,
+	WrittenBook.ID:                WrittenBook,
+	Emerald.ID:                    Emerald,
+	ItemFrame.ID:                  ItemFrame,
+	FlowerPotItem.ID:              FlowerPotItem,
+	Carrot.ID:                     Carrot,
+	Potato.ID:                     Potato,
+	BakedPotato.ID:                BakedPotato,
+	PoisonousPotato.ID:            PoisonousPotato,
+	Map.ID:                        Map,
+	GoldenCarrot.ID:               GoldenCarrot,
+	SkullItem.ID:                  SkullItem,
+	CarrotOnAStick.ID:             CarrotOnAStick,
+	NetherStar.ID:                 NetherStar,
+	PumpkinPie.ID:                 PumpkinPie,
+	Fireworks.ID:                  Fireworks,
+	FireworkCharge.ID:             FireworkCharge,
+	EnchantedBook.ID:              EnchantedBook,
+	Comparator.ID:                 Comparator,
+	Netherbrick.ID:                Netherbrick,
+	Quartz.ID:                     Quartz,
+	TntMinecart.ID:                TntMinecart,
+	HopperMinecart.ID:             HopperMinecart,
+	PrismarineShard.ID:            PrismarineShard,
+	PrismarineCrystals.ID:         PrismarineCrystals,
+	Rabbit.ID:                     Rabbit,
+	CookedRabbit.ID:               CookedRabbit,
+	RabbitStew.ID:                 RabbitStew,
+	RabbitFoot.ID:                 RabbitFoot,
+	RabbitHide.ID:                 RabbitHide,
+	ArmorStand.ID:                 ArmorStand,
+	IronHorseArmor.ID:             IronHorseArmor,
+	GoldenHorseArmor.ID:           GoldenHorseArmor,
+	DiamondHorseArmor.ID:          DiamondHorseArmor,
+	Lead.ID:                       Lead,
+	NameTag.ID:                    NameTag,
+	CommandBlockMinecart.ID:       CommandBlockMinecart,
+	Mutton.ID:                     Mutton,
+	CookedMutton.ID:               CookedMutton,
+	Banner.ID:                     Banner,
+	EndCrystal.ID:                 EndCrystal,
+	SpruceDoorItem.ID:             SpruceDoorItem,
+	BirchDoorItem.ID:              BirchDoorItem,
+	JungleDoorItem.ID:             JungleDoorItem,
+	AcaciaDoorItem.ID:             AcaciaDoorItem,
+	DarkOakDoorItem.ID:            DarkOakDoorItem,
+	ChorusFruit.ID:                ChorusFruit,
+	ChorusFruitPopped.ID:          ChorusFruitPopped,
+	Beetroot.ID:                   Beetroot,
+	BeetrootSeeds.ID:              BeetrootSeeds,
+	BeetrootSoup.ID:               BeetrootSoup,
+	DragonBreath.ID:               DragonBreath,
+	SplashPotion.ID:               SplashPotion,
+	SpectralArrow.ID:              SpectralArrow,
+	TippedArrow.ID:                TippedArrow,
+	LingeringPotion.ID:            LingeringPotion,
+	Shield.ID:                     Shield,
+	Elytra.ID:                     Elytra,
+	SpruceBoat.ID:                 SpruceBoat,
+	BirchBoat.ID:                  BirchBoat,
+	JungleBoat.ID:                 JungleBoat,
+	AcaciaBoat.ID:                 AcaciaBoat,
+	DarkOakBoat.ID:                DarkOakBoat,
+	TotemOfUndying.ID:             TotemOfUndying,
+	ShulkerShell.ID:               ShulkerShell,
+	IronNugget.ID:                 IronNugget,
+	KnowledgeBook.ID:              KnowledgeBook,
+	Record13.ID:                   Record13,
+	RecordCat.ID:                  RecordCat,
+	RecordBlocks.ID:               RecordBlocks,
+	RecordChirp.ID:                RecordChirp,
+	RecordFar.ID:                  RecordFar,
+	RecordMall.ID:                 RecordMall,
+	RecordMellohi.ID:              RecordMellohi,
+	RecordStal.ID:                 RecordStal,
+	RecordStrad.ID:                RecordStrad,
+	RecordWard.ID:                 RecordWard,
+	Record11.ID:                   Record11,
+	RecordWait.ID:                 RecordWait,
+}
+
+// variantMap contains the named metadata values, by ID.
+var variantMap = map[int][]Variant{
+	Stone.ID:               {{0, "stone"}, {1, "granite"}, {2, "smooth_granite"}, {3, "diorite"}, {4, "smooth_diorite"}, {5, "andesite"}, {6, "smooth_andesite"}},
+	Dirt.ID:                {{0, "dirt"}, {1, "coarse_dirt"}, {2, "podzol"}},
+	Planks.ID:              {{0, "oak_planks"}, {1, "spruce_planks"}, {2, "birch_planks"}, {3, "jungle_planks"}, {4, "acacia_planks"}, {5, "dark_oak_planks"}},
+	Sapling.ID:             {{0, "oak_sapling"}, {1, "spruce_sapling"}, {2, "birch_sapling"}, {3, "jungle_sapling"}, {4, "acacia_sapling"}, {5, "dark_oak_sapling"}},
+	Sand.ID:                {{0, "sand"}, {1, "red_sand"}},
+	Log.ID:                 {{0, "oak_log"}, {1, "spruce_log"}, {2, "birch_log"}, {3, "jungle_log"}},
+	Leaves.ID:              {{0, "oak_leaves"}, {1, "spruce_leaves"}, {2, "birch_leaves"}, {3, "jungle_leaves"}},
+	Sponge.ID:              {{0, "sponge"}, {1, "wet_sponge"}},
+	Sandstone.ID:           {{0, "sandstone"}, {1, "chiseled_sandstone"}, {2, "smooth_sandstone"}},
+	Tallgrass.ID:           {{0, "dead_shrub"}, {1, "tall_grass"}, {2, "fern"}},
+	Wool.ID:                {{0, "white_wool"}, {1, "orange_wool"}, {2, "magenta_wool"}, {3, "light_blue_wool"}, {4, "yellow_wool"}, {5, "lime_wool"}, {6, "pink_wool"}, {7, "gray_wool"}, {8, "silver_wool"}, {9, "cyan_wool"}, {10, "purple_wool"}, {11, "blue_wool"}, {12, "brown_wool"}, {13, "green_wool"}, {14, "red_wool"}, {15, "black_wool"}},
+	YellowFlower.ID:        {{0, "dandelion"}},
+	RedFlower.ID:           {{0, "poppy"}, {1, "blue_orchid"}, {2, "allium"}, {3, "houstonia"}, {4, "red_tulip"}, {5, "orange_tulip"}, {6, "white_tulip"}, {7, "pink_tulip"}, {8, "oxeye_daisy"}},
+	StoneSlab.ID:           {{0, "smooth_stone_slab"}, {1, "sandstone_slab"}, {2, "petrified_oak_slab"}, {3, "cobblestone_slab"}, {4, "brick_slab"}, {5, "stone_brick_slab"}, {6, "nether_brick_slab"}, {7, "quartz_slab"}},
+	StainedGlass.ID:        {{0, "white_stained_glass"}, {1, "orange_stained_glass"}, {2, "magenta_stained_glass"}, {3, "light_blue_stained_glass"}, {4, "yellow_stained_glass"}, {5, "lime_stained_glass"}, {6, "pink_stained_glass"}, {7, "gray_stained_glass"}, {8, "silver_stained_glass"}, {9, "cyan_stained_glass"}, {10, "purple_stained_glass"}, {11, "blue_stained_glass"}, {12, "brown_stained_glass"}, {13, "green_stained_glass"}, {14, "red_stained_glass"}, {15, "black_stained_glass"}},
+	MonsterEgg.ID:          {{0, "stone_monster_egg"}, {1, "cobblestone_monster_egg"}, {2, "stone_brick_monster_egg"}, {3, "mossy_brick_monster_egg"}, {4, "cracked_brick_monster_egg"}, {5, "chiseled_brick_monster_egg"}},
+	Stonebrick.ID:          {{0, "stonebrick"}, {1, "mossy_stonebrick"}, {2, "cracked_stonebrick"}, {3, "chiseled_stonebrick"}},
+	WoodenSlab.ID:          {{0, "oak_slab"}, {1, "spruce_slab"}, {2, "birch_slab"}, {3, "jungle_slab"}, {4, "acacia_slab"}, {5, "dark_oak_slab"}},
+	CobblestoneWall.ID:     {{0, "cobblestone_wall"}, {1, "mossy_cobblestone_wall"}},
+	Anvil.ID:               {{0, "anvil"}, {4, "chipped_anvil"}, {8, "damaged_anvil"}},
+	QuartzBlock.ID:         {{0, "quartz_block"}, {1, "chiseled_quartz_block"}, {2, "quartz_pillar"}},
+	StainedHardenedClay.ID: {{0, "white_stained_hardened_clay"}, {1, "orange_stained_hardened_clay"}, {2, "magenta_stained_hardened_clay"}, {3, "light_blue_stained_hardened_clay"}, {4, "yellow_stained_hardened_clay"}, {5, "lime_stained_hardened_clay"}, {6, "pink_stained_hardened_clay"}, {7, "gray_stained_hardened_clay"}, {8, "silver_stained_hardened_clay"}, {9, "cyan_stained_hardened_clay"}, {10, "purple_stained_hardened_clay"}, {11, "blue_stained_hardened_clay"}, {12, "brown_stained_hardened_clay"}, {13, "green_stained_hardened_clay"}, {14, "red_stained_hardened_clay"}, {15, "black_stained_hardened_clay"}},
+	StainedGlassPane.ID:    {{0, "white_stained_glass_pane"}, {1, "orange_stained_glass_pane"}, {2, "magenta_stained_glass_pane"}, {3, "light_blue_stained_glass_pane"}, {4, "yellow_stained_glass_pane"}, {5, "lime_stained_glass_pane"}, {6, "pink_stained_glass_pane"}, {7, "gray_stained_glass_pane"}, {8, "silver_stained_glass_pane"}, {9, "cyan_stained_glass_pane"}, {10, "purple_stained_glass_pane"}, {11, "blue_stained_glass_pane"}, {12, "brown_stained_glass_pane"}, {13, "green_stained_glass_pane"}, {14, "red_stained_glass_pane"}, {15, "black_stained_glass_pane"}},
+	Leaves2.ID:             {{0, "acacia_leaves"}, {1, "dark_oak_leaves"}},
+	Log2.ID:                {{0, "acacia_log"}, {1, "dark_oak_log"}},
+	Prismarine.ID:          {{0, "prismarine"}, {1, "prismarine_bricks"}, {2, "dark_prismarine"}},
+	Carpet.ID:              {{0, "white_carpet"}, {1, "orange_carpet"}, {2, "magenta_carpet"}, {3, "light_blue_carpet"}, {4, "yellow_carpet"}, {5, "lime_carpet"}, {6, "pink_carpet"}, {7, "gray_carpet"}, {8, "silver_carpet"}, {9, "cyan_carpet"}, {10, "purple_carpet"}, {11, "blue_carpet"}, {12, "brown_carpet"}, {13, "green_carpet"}, {14, "red_carpet"}, {15, "black_carpet"}},
+	DoublePlant.ID:         {{0, "sunflower"}, {1, "syringa"}, {2, "double_grass"}, {3, "double_fern"}, {4, "double_rose"}, {5, "paeonia"}},
+	RedSandstone.ID:        {{0, "red_sandstone"}, {1, "chiseled_red_sandstone"}, {2, "smooth_red_sandstone"}},
+	StoneSlab2.ID:          {{0, "red_sandstone_slab"}},
+	Concrete.ID:            {{0, "white_concrete"}, {1, "orange_concrete"}, {2, "magenta_concrete"}, {3, "light_blue_concrete"}, {4, "yellow_concrete"}, {5, "lime_concrete"}, {6, "pink_concrete"}, {7, "gray_concrete"}, {8, "silver_concrete"}, {9, "cyan_concrete"}, {10, "purple_concrete"}, {11, "blue_concrete"}, {12, "brown_concrete"}, {13, "green_concrete"}, {14, "red_concrete"}, {15, "black_concrete"}},
+	ConcretePowder.ID:      {{0, "white_concrete_powder"}, {1, "orange_concrete_powder"}, {2, "magenta_concrete_powder"}, {3, "light_blue_concrete_powder"}, {4, "yellow_concrete_powder"}, {5, "lime_concrete_powder"}, {6, "pink_concrete_powder"}, {7, "gray_concrete_powder"}, {8, "silver_concrete_powder"}, {9, "cyan_concrete_powder"}, {10, "purple_concrete_powder"}, {11, "blue_concrete_powder"}, {12, "brown_concrete_powder"}, {13, "green_concrete_powder"}, {14, "red_concrete_powder"}, {15, "black_concrete_powder"}},
+	Coal.ID:                {{0, "coal"}, {1, "charcoal"}},
+	GoldenApple.ID:         {{0, "golden_apple"}, {1, "enchanted_golden_apple"}},
+	Fish.ID:                {{0, "cod"}, {1, "salmon"}, {2, "clownfish"}, {3, "pufferfish"}},
+	CookedFish.ID:          {{0, "cooked_cod"}, {1, "cooked_salmon"}},
+	Dye.ID:                 {{0, "ink_sac"}, {1, "rose_red"}, {2, "cactus_green"}, {3, "cocoa_beans"}, {4, "lapis_lazuli"}, {5, "purple_dye"}, {6, "cyan_dye"}, {7, "light_gray_dye"}, {8, "gray_dye"}, {9, "pink_dye"}, {10, "lime_dye"}, {11, "dandelion_yellow"}, {12, "light_blue_dye"}, {13, "magenta_dye"}, {14, "orange_dye"}, {15, "bone_meal"}},
+	BedItem.ID:             {{0, "white_bed"}, {1, "orange_bed"}, {2, "magenta_bed"}, {3, "light_blue_bed"}, {4, "yellow_bed"}, {5, "lime_bed"}, {6, "pink_bed"}, {7, "gray_bed"}, {8, "silver_bed"}, {9, "cyan_bed"}, {10, "purple_bed"}, {11, "blue_bed"}, {12, "brown_bed"}, {13, "green_bed"}, {14, "red_bed"}, {15, "black_bed"}},
+	SkullItem.ID:           {{0, "skeleton_skull"}, {1, "wither_skeleton_skull"}, {2, "zombie_head"}, {3, "player_head"}, {4, "creeper_head"}, {5, "dragon_head"}},
+}
@@ -0,0 +1,69 @@
+package nbt
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+)
+
+// This file reads and writes compressed NBT data: the files (level.dat,
+// player data) are compressed with gzip, the chunks with zlib.
+
+// ReadGzip reads NBT data compressed with gzip.
+func ReadGzip(r io.Reader) (string, Compound, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return "", nil, err
+	}
+	defer gz.Close()
+	return Read(gz)
+}
+
+// WriteGzip writes NBT data compressed with gzip.
+func WriteGzip(w io.Writer, name string, root Compound) error {
+	gz := gzip.NewWriter(w)
+	if err := Write(gz, name, root); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ReadZlib reads NBT data compressed with zlib.
+func ReadZlib(r io.Reader) (string, Compound, error) {
+	z, err := zlib.NewReader(r)
+	if err != nil {
+		return "", nil, err
+	}
+	defer z.Close()
+	return Read(z)
+}
+
+// WriteZlib writes NBT data compressed with zlib.
+func WriteZlib(w io.Writer, name string, root Compound) error {
+	z := zlib.NewWriter(w)
+	if err := Write(z, name, root); err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+// ReadCompressed reads NBT data compressed with gzip or zlib, or
+// uncompressed, detecting the compression from its first bytes.
+func ReadCompressed(r io.Reader) (string, Compound, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", nil, err
+	}
+	switch {
+	case header[0] == 0x1F && header[1] == 0x8B:
+		return ReadGzip(br)
+	case header[0] == 0x78 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0:
+		return ReadZlib(br)
+	}
+	return Read(br)
+}
@@ -0,0 +1,418 @@
+package nbt
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// This file converts Go values from and to tags, the way encoding/json
+// does. The fields of the structs are named after their "nbt" tag:
+//
+//	Seed    int64  `nbt:"RandomSeed"`
+//	Raining bool   `nbt:"raining,omitempty"`
+//	Ignored string `nbt:"-"`
+//
+// The Go types are converted as follows: bool, int8 and uint8 to Byte;
+// int16 and uint16 to Short; int, int32 and uint32 to Int; int64 and
+// uint64 to Long; float32 to Float; float64 to Double; string to String;
+// []byte, []int32 and []int64 to the arrays; the other slices and arrays
+// to Lists; structs and maps with string keys to Compounds. The values
+// implementing Tag are kept as they are.
+
+var tagInterface = reflect.TypeOf((*Tag)(nil)).Elem()
+
+// Marshal returns the given value, which must be a struct or a
+// map, as the named root of the NBT data.
+func Marshal(name string, v interface{}) ([]byte, error) {
+	root, err := MarshalCompound(v)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err = Write(buf, name, root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal reads the NBT data into the value pointed by v,
+// and returns the name of the root.
+func Unmarshal(data []byte, v interface{}) (string, error) {
+	name, root, err := Read(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return name, UnmarshalTag(root, v)
+}
+
+// MarshalCompound converts the given struct or map to a compound.
+func MarshalCompound(v interface{}) (Compound, error) {
+	tag, err := MarshalTag(v)
+	if err != nil {
+		return nil, err
+	}
+	compound, ok := tag.(Compound)
+	if !ok {
+		return nil, fmt.Errorf("nbt: cannot marshal %T as a compound", v)
+	}
+	return compound, nil
+}
+
+// MarshalTag converts the given value to a tag.
+func MarshalTag(v interface{}) (Tag, error) {
+	if v == nil {
+		return nil, fmt.Errorf("nbt: cannot marshal nil")
+	}
+	return marshalValue(reflect.ValueOf(v))
+}
+
+func marshalValue(v reflect.Value) (Tag, error) {
+	nilable := v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface
+	if v.Type().Implements(tagInterface) && (!nilable || !v.IsNil()) {
+		return v.Interface().(Tag), nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, fmt.Errorf("nbt: cannot marshal nil %v", v.Type())
+		}
+		return marshalValue(v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			return Byte(1), nil
+		}
+		return Byte(0), nil
+	case reflect.Int8:
+		return Byte(v.Int()), nil
+	case reflect.Uint8:
+		return Byte(v.Uint()), nil
+	case reflect.Int16:
+		return Short(v.Int()), nil
+	case reflect.Uint16:
+		return Short(v.Uint()), nil
+	case reflect.Int, reflect.Int32:
+		return Int(v.Int()), nil
+	case reflect.Uint32:
+		return Int(v.Uint()), nil
+	case reflect.Int64:
+		return Long(v.Int()), nil
+	case reflect.Uint64, reflect.Uint:
+		return Long(v.Uint()), nil
+	case reflect.Float32:
+		return Float(v.Float()), nil
+	case reflect.Float64:
+		return Double(v.Float()), nil
+	case reflect.String:
+		return String(v.String()), nil
+	case reflect.Slice, reflect.Array:
+		return marshalList(v)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("nbt: cannot marshal map with %v keys", v.Type().Key())
+		}
+		compound := make(Compound, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			tag, err := marshalValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			compound[iter.Key().String()] = tag
+		}
+		return compound, nil
+	case reflect.Struct:
+		compound := make(Compound)
+		for _, field := range cachedFields(v.Type()) {
+			value := v.FieldByIndex(field.index)
+			if field.omitEmpty && isEmptyValue(value) {
+				continue
+			}
+			if (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && value.IsNil() {
+				continue
+			}
+			tag, err := marshalValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("%v (field %v)", err, field.name)
+			}
+			compound[field.name] = tag
+		}
+		return compound, nil
+	}
+	return nil, fmt.Errorf("nbt: cannot marshal %v", v.Type())
+}
+
+func marshalList(v reflect.Value) (Tag, error) {
+	switch v.Type().Elem().Kind() {
+	case reflect.Uint8:
+		data := make(ByteArray, v.Len())
+		reflect.Copy(reflect.ValueOf([]byte(data)), v)
+		return data, nil
+	case reflect.Int32:
+		data := make(IntArray, v.Len())
+		for i := range data {
+			data[i] = int32(v.Index(i).Int())
+		}
+		return data, nil
+	case reflect.Int64:
+		data := make(LongArray, v.Len())
+		for i := range data {
+			data[i] = v.Index(i).Int()
+		}
+		return data, nil
+	}
+	list := NewList(TagEnd)
+	for i := 0; i < v.Len(); i++ {
+		tag, err := marshalValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			list.ElementType = tag.Type()
+		} else if tag.Type() != list.ElementType {
+			return nil, fmt.Errorf("nbt: %v in a list of %v", tag.Type(), list.ElementType)
+		}
+		list.Elements = append(list.Elements, tag)
+	}
+	return list, nil
+}
+
+// UnmarshalTag stores the given tag in the value pointed by v.
+// The numbers are converted if the types differ; the tags
+// which do not match any field are ignored.
+func UnmarshalTag(tag Tag, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("nbt: cannot unmarshal into %T", v)
+	}
+	return unmarshalValue(tag, rv.Elem())
+}
+
+func unmarshalValue(tag Tag, v reflect.Value) error {
+	if tag == nil {
+		return nil
+	}
+	// the value stores a tag as it is
+	if v.Kind() == reflect.Interface && tagInterface.Implements(v.Type()) {
+		v.Set(reflect.ValueOf(tag))
+		return nil
+	}
+	if reflect.TypeOf(tag).AssignableTo(v.Type()) {
+		v.Set(reflect.ValueOf(tag))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return unmarshalValue(tag, v.Elem())
+	case reflect.Bool:
+		n, ok := integer(tag)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		v.SetBool(n != 0)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := integer(tag)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := integer(tag)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		v.SetUint(uint64(n))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch t := tag.(type) {
+		case Float:
+			v.SetFloat(float64(t))
+		case Double:
+			v.SetFloat(float64(t))
+		default:
+			n, ok := integer(tag)
+			if !ok {
+				return mismatch(tag, v)
+			}
+			v.SetFloat(float64(n))
+		}
+		return nil
+	case reflect.String:
+		s, ok := tag.(String)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		v.SetString(string(s))
+		return nil
+	case reflect.Slice, reflect.Array:
+		return unmarshalList(tag, v)
+	case reflect.Map:
+		compound, ok := tag.(Compound)
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			return mismatch(tag, v)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(compound)))
+		}
+		for name, element := range compound {
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := unmarshalValue(element, value); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(name).Convert(v.Type().Key()), value)
+		}
+		return nil
+	case reflect.Struct:
+		compound, ok := tag.(Compound)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		fields := cachedFields(v.Type())
+		for name, element := range compound {
+			field := findField(fields, name)
+			if field == nil {
+				continue
+			}
+			if err := unmarshalValue(element, v.FieldByIndex(field.index)); err != nil {
+				return fmt.Errorf("%v (field %v)", err, field.name)
+			}
+		}
+		return nil
+	}
+	return mismatch(tag, v)
+}
+
+func unmarshalList(tag Tag, v reflect.Value) error {
+	var elements []Tag
+	switch t := tag.(type) {
+	case ByteArray:
+		elements = make([]Tag, len(t))
+		for i, b := range t {
+			elements[i] = Byte(b)
+		}
+	case IntArray:
+		elements = make([]Tag, len(t))
+		for i, n := range t {
+			elements[i] = Int(n)
+		}
+	case LongArray:
+		elements = make([]Tag, len(t))
+		for i, n := range t {
+			elements[i] = Long(n)
+		}
+	case *List:
+		elements = t.Elements
+	default:
+		return mismatch(tag, v)
+	}
+
+	if v.Kind() == reflect.Array {
+		if len(elements) != v.Len() {
+			return fmt.Errorf("nbt: cannot unmarshal %v elements into %v", len(elements), v.Type())
+		}
+	} else {
+		v.Set(reflect.MakeSlice(v.Type(), len(elements), len(elements)))
+	}
+	for i, element := range elements {
+		if err := unmarshalValue(element, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// integer returns the value of the given integer tag.
+func integer(tag Tag) (int64, bool) {
+	switch t := tag.(type) {
+	case Byte:
+		return int64(t), true
+	case Short:
+		return int64(t), true
+	case Int:
+		return int64(t), true
+	case Long:
+		return int64(t), true
+	}
+	return 0, false
+}
+
+func mismatch(tag Tag, v reflect.Value) error {
+	return fmt.Errorf("nbt: cannot unmarshal %v into %v", tag.Type(), v.Type())
+}
+
+// isEmptyValue returns true if the given value is the
+// zero value of its type, or an empty collection.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		return v.Len() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0 && !math.Signbit(v.Float())
+	}
+	return v.IsZero()
+}
+
+// field struct describes a field of a struct, as named in the tags.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// fieldCache contains the fields of the structs already (un)marshaled.
+var fieldCache sync.Map // reflect.Type => []field
+
+// cachedFields returns the fields of the given struct type.
+func cachedFields(t reflect.Type) []field {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]field)
+	}
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		name, options := f.Name, ""
+		if tag, ok := f.Tag.Lookup("nbt"); ok {
+			if tag == "-" {
+				continue
+			}
+			if comma := strings.IndexByte(tag, ','); comma >= 0 {
+				tag, options = tag[:comma], tag[comma+1:]
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		fields = append(fields, field{name, f.Index, options == "omitempty"})
+	}
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+// findField returns the field of the given name, ignoring the
+// case if no field has exactly this name.
+func findField(fields []field, name string) *field {
+	for i := range fields {
+		if fields[i].name == name {
+			return &fields[i]
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, name) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
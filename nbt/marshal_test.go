@@ -0,0 +1,137 @@
+package nbt
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testPlayer struct {
+	Name      string             `nbt:"name"`
+	Health    float32            `nbt:"Health"`
+	Pos       []float64          `nbt:"Pos"`
+	OnGround  bool               `nbt:"OnGround"`
+	Level     int32              `nbt:"XpLevel"`
+	Seed      int64              `nbt:"seed,omitempty"`
+	Inventory []testItem         `nbt:"Inventory"`
+	Data      []byte             `nbt:"data"`
+	Extra     Compound           `nbt:"extra,omitempty"`
+	Scores    map[string]int16   `nbt:"scores"`
+	Ignored   string             `nbt:"-"`
+	Spawn     *testPosition      `nbt:"Spawn"`
+	Raw       Tag                `nbt:"raw"`
+	Unsigned  uint16             `nbt:"unsigned"`
+	Ints      []int32            `nbt:"ints"`
+	Positions map[string][3]int8 `nbt:"positions"`
+	private   int
+}
+
+type testItem struct {
+	ID    string `nbt:"id"`
+	Count int8
+	Slot  byte
+}
+
+type testPosition struct {
+	X, Y, Z int32
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	player := testPlayer{
+		Name:      "Steve",
+		Health:    20,
+		Pos:       []float64{0.5, 64, -0.5},
+		OnGround:  true,
+		Level:     7,
+		Inventory: []testItem{{"minecraft:stone", 64, 0}, {"minecraft:dirt", 1, 8}},
+		Data:      []byte{1, 2, 3},
+		Scores:    map[string]int16{"kills": 3},
+		Ignored:   "ignored",
+		Spawn:     &testPosition{1, 2, 3},
+		Raw:       NewList(TagInt, Int(4)),
+		Unsigned:  65535,
+		Ints:      []int32{-1},
+		Positions: map[string][3]int8{"home": {1, 2, 3}},
+		private:   4,
+	}
+	data, err := Marshal("player", player)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var read testPlayer
+	name, err := Unmarshal(data, &read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "player" {
+		t.Errorf("Expected name %q, got %q", "player", name)
+	}
+	player.Ignored, player.private = "", 0
+	if !reflect.DeepEqual(player, read) {
+		t.Errorf("Expected %+v, got %+v", player, read)
+	}
+}
+
+func TestMarshalTypes(t *testing.T) {
+	compound, err := MarshalCompound(testPlayer{Name: "Alex", OnGround: true, Data: []byte{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]TagType{
+		"name":      TagString,
+		"Health":    TagFloat,
+		"Pos":       TagList,
+		"OnGround":  TagByte,
+		"XpLevel":   TagInt,
+		"Inventory": TagList,
+		"data":      TagByteArray,
+		"scores":    TagCompound,
+		"unsigned":  TagShort,
+		"ints":      TagIntArray,
+		"positions": TagCompound,
+	}
+	for name, tagType := range expected {
+		if tag, ok := compound[name]; !ok || tag.Type() != tagType {
+			t.Errorf("%v: expected %v, got %v", name, tagType, tag)
+		}
+	}
+	for _, name := range []string{"seed", "extra", "Ignored", "private", "Spawn", "raw"} {
+		if _, ok := compound[name]; ok {
+			t.Errorf("%v should not have been marshaled", name)
+		}
+	}
+}
+
+func TestUnmarshalConversions(t *testing.T) {
+	var v struct {
+		Count   int64
+		Ratio   float64
+		Enabled bool
+		Name    string
+	}
+	root := Compound{"count": Byte(5), "Ratio": Int(2), "Enabled": Short(1), "Unknown": String("x")}
+	if err := UnmarshalTag(root, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Count != 5 || v.Ratio != 2 || !v.Enabled {
+		t.Errorf("Unexpected values %+v", v)
+	}
+	if err := UnmarshalTag(Compound{"Name": Int(1)}, &v); err == nil {
+		t.Error("Expected an error when unmarshaling an Int into a string")
+	}
+	if err := UnmarshalTag(root, v); err == nil {
+		t.Error("Expected an error when unmarshaling into a non-pointer")
+	}
+}
+
+func TestMarshalInvalid(t *testing.T) {
+	if _, err := MarshalCompound(5); err == nil {
+		t.Error("Expected an error when marshaling an int as a compound")
+	}
+	if _, err := MarshalTag(map[int]string{1: "a"}); err == nil {
+		t.Error("Expected an error when marshaling a map with int keys")
+	}
+	if _, err := MarshalTag([]interface{}{1, "a"}); err == nil {
+		t.Error("Expected an error when marshaling a list of mixed types")
+	}
+}
@@ -0,0 +1,373 @@
+// Package nbt reads and writes the Named Binary Tag format, used by
+// Minecraft to store the worlds, the players and the items.
+package nbt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+const (
+	// MaxDepth is the maximal number of nested lists and compounds.
+	MaxDepth = 512
+	// the number of elements allocated at once when reading arrays and
+	// lists, so that a forged length does not allocate a huge buffer
+	allocationStep = 4096
+)
+
+// ByteOrder is the byte order of the binary format.
+var ByteOrder = binary.BigEndian
+
+// Read reads a named compound, the root of the NBT data, from the given reader.
+func Read(r io.Reader) (string, Compound, error) {
+	d := &decoder{r: r}
+	t, err := d.readType()
+	if err != nil {
+		return "", nil, err
+	}
+	if t != TagCompound {
+		return "", nil, fmt.Errorf("nbt: the root tag is a %v, not a compound", t)
+	}
+	name, err := d.readString()
+	if err != nil {
+		return "", nil, err
+	}
+	tag, err := d.readPayload(TagCompound, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, tag.(Compound), nil
+}
+
+// Write writes the given compound as the named root of the NBT data.
+// The tags of the compounds are written in the order of their names.
+func Write(w io.Writer, name string, root Compound) error {
+	bw := bufio.NewWriter(w)
+	e := &encoder{w: bw}
+	e.writeByte(byte(TagCompound))
+	e.writeString(name)
+	e.writePayload(root, 0)
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+// decoder reads the tags.
+type decoder struct {
+	r   io.Reader
+	buf [8]byte
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if _, err := io.ReadFull(d.r, d.buf[:n]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return d.buf[:n], nil
+}
+
+func (d *decoder) readType() (TagType, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return 0, err
+	}
+	if t := TagType(b[0]); t <= TagLongArray {
+		return t, nil
+	}
+	return 0, fmt.Errorf("nbt: invalid tag type %v", b[0])
+}
+
+func (d *decoder) readLength() (int, error) {
+	b, err := d.read(4)
+	if err != nil {
+		return 0, err
+	}
+	length := int32(ByteOrder.Uint32(b))
+	if length < 0 {
+		return 0, fmt.Errorf("nbt: negative length %v", length)
+	}
+	return int(length), nil
+}
+
+func (d *decoder) readString() (string, error) {
+	b, err := d.read(2)
+	if err != nil {
+		return "", err
+	}
+	data := make([]byte, ByteOrder.Uint16(b))
+	if _, err = io.ReadFull(d.r, data); err != nil {
+		return "", io.ErrUnexpectedEOF
+	}
+	return string(data), nil
+}
+
+// readPayload reads the value of a tag of the given type.
+func (d *decoder) readPayload(t TagType, depth int) (Tag, error) {
+	switch t {
+	case TagByte:
+		b, err := d.read(1)
+		if err != nil {
+			return nil, err
+		}
+		return Byte(b[0]), nil
+	case TagShort:
+		b, err := d.read(2)
+		if err != nil {
+			return nil, err
+		}
+		return Short(ByteOrder.Uint16(b)), nil
+	case TagInt:
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return Int(ByteOrder.Uint32(b)), nil
+	case TagLong:
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return Long(ByteOrder.Uint64(b)), nil
+	case TagFloat:
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return Float(math.Float32frombits(ByteOrder.Uint32(b))), nil
+	case TagDouble:
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return Double(math.Float64frombits(ByteOrder.Uint64(b))), nil
+	case TagByteArray:
+		length, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, 0, minInt(length, allocationStep))
+		for len(data) < length {
+			n := minInt(length-len(data), allocationStep)
+			data = append(data, make([]byte, n)...)
+			if _, err = io.ReadFull(d.r, data[len(data)-n:]); err != nil {
+				return nil, io.ErrUnexpectedEOF
+			}
+		}
+		return ByteArray(data), nil
+	case TagString:
+		s, err := d.readString()
+		return String(s), err
+	case TagIntArray:
+		length, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		data := make([]int32, 0, minInt(length, allocationStep))
+		for i := 0; i < length; i++ {
+			b, err := d.read(4)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, int32(ByteOrder.Uint32(b)))
+		}
+		return IntArray(data), nil
+	case TagLongArray:
+		length, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		data := make([]int64, 0, minInt(length, allocationStep))
+		for i := 0; i < length; i++ {
+			b, err := d.read(8)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, int64(ByteOrder.Uint64(b)))
+		}
+		return LongArray(data), nil
+	case TagList:
+		if depth >= MaxDepth {
+			return nil, fmt.Errorf("nbt: more than %v nested tags", MaxDepth)
+		}
+		elementType, err := d.readType()
+		if err != nil {
+			return nil, err
+		}
+		length, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		if elementType == TagEnd && length > 0 {
+			return nil, fmt.Errorf("nbt: list of %v elements of type %v", length, TagEnd)
+		}
+		list := &List{elementType, make([]Tag, 0, minInt(length, allocationStep))}
+		for i := 0; i < length; i++ {
+			tag, err := d.readPayload(elementType, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list.Elements = append(list.Elements, tag)
+		}
+		return list, nil
+	case TagCompound:
+		if depth >= MaxDepth {
+			return nil, fmt.Errorf("nbt: more than %v nested tags", MaxDepth)
+		}
+		compound := make(Compound)
+		for {
+			t, err := d.readType()
+			if err != nil {
+				return nil, err
+			}
+			if t == TagEnd {
+				return compound, nil
+			}
+			name, err := d.readString()
+			if err != nil {
+				return nil, err
+			}
+			if compound[name], err = d.readPayload(t, depth+1); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, fmt.Errorf("nbt: unexpected tag type %v", t)
+}
+
+// encoder writes the tags. The first error is kept,
+// and stops the writing.
+type encoder struct {
+	w   *bufio.Writer
+	buf [8]byte
+	err error
+}
+
+func (e *encoder) write(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+func (e *encoder) writeByte(b byte) {
+	if e.err == nil {
+		e.err = e.w.WriteByte(b)
+	}
+}
+
+func (e *encoder) writeUint16(v uint16) {
+	ByteOrder.PutUint16(e.buf[:], v)
+	e.write(e.buf[:2])
+}
+
+func (e *encoder) writeUint32(v uint32) {
+	ByteOrder.PutUint32(e.buf[:], v)
+	e.write(e.buf[:4])
+}
+
+func (e *encoder) writeUint64(v uint64) {
+	ByteOrder.PutUint64(e.buf[:], v)
+	e.write(e.buf[:8])
+}
+
+func (e *encoder) writeString(s string) {
+	if len(s) > math.MaxUint16 {
+		e.fail(fmt.Errorf("nbt: string is too long (%v bytes)", len(s)))
+		return
+	}
+	e.writeUint16(uint16(len(s)))
+	e.write([]byte(s))
+}
+
+func (e *encoder) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// writePayload writes the value of the given tag.
+func (e *encoder) writePayload(tag Tag, depth int) {
+	switch v := tag.(type) {
+	case Byte:
+		e.writeByte(byte(v))
+	case Short:
+		e.writeUint16(uint16(v))
+	case Int:
+		e.writeUint32(uint32(v))
+	case Long:
+		e.writeUint64(uint64(v))
+	case Float:
+		e.writeUint32(math.Float32bits(float32(v)))
+	case Double:
+		e.writeUint64(math.Float64bits(float64(v)))
+	case ByteArray:
+		e.writeUint32(uint32(len(v)))
+		e.write(v)
+	case String:
+		e.writeString(string(v))
+	case IntArray:
+		e.writeUint32(uint32(len(v)))
+		for _, i := range v {
+			e.writeUint32(uint32(i))
+		}
+	case LongArray:
+		e.writeUint32(uint32(len(v)))
+		for _, l := range v {
+			e.writeUint64(uint64(l))
+		}
+	case *List:
+		if v == nil {
+			e.fail(fmt.Errorf("nbt: nil list"))
+			return
+		}
+		if depth >= MaxDepth {
+			e.fail(fmt.Errorf("nbt: more than %v nested tags", MaxDepth))
+			return
+		}
+		e.writeByte(byte(v.ElementType))
+		e.writeUint32(uint32(len(v.Elements)))
+		for _, element := range v.Elements {
+			if element.Type() != v.ElementType {
+				e.fail(fmt.Errorf("nbt: %v in a list of %v", element.Type(), v.ElementType))
+				return
+			}
+			e.writePayload(element, depth+1)
+		}
+	case Compound:
+		if depth >= MaxDepth {
+			e.fail(fmt.Errorf("nbt: more than %v nested tags", MaxDepth))
+			return
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			element := v[name]
+			if element == nil {
+				e.fail(fmt.Errorf("nbt: tag %q is nil", name))
+				return
+			}
+			e.writeByte(byte(element.Type()))
+			e.writeString(name)
+			e.writePayload(element, depth+1)
+		}
+		e.writeByte(byte(TagEnd))
+	default:
+		e.fail(fmt.Errorf("nbt: cannot write %T", tag))
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
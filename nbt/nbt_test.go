@@ -0,0 +1,162 @@
+package nbt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// the "hello world" example of the specification
+var helloWorld = []byte{
+	0x0A, 0x00, 0x0B, 'h', 'e', 'l', 'l', 'o', ' ', 'w', 'o', 'r', 'l', 'd',
+	0x08, 0x00, 0x04, 'n', 'a', 'm', 'e', 0x00, 0x09, 'B', 'a', 'n', 'a', 'n', 'r', 'a', 'm', 'a',
+	0x00,
+}
+
+func TestReadHelloWorld(t *testing.T) {
+	name, root, err := Read(bytes.NewReader(helloWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "hello world" {
+		t.Errorf("Expected name %q, got %q", "hello world", name)
+	}
+	if root.GetString("name") != "Bananrama" {
+		t.Errorf("Expected %q, got %q", "Bananrama", root.GetString("name"))
+	}
+}
+
+func TestWriteHelloWorld(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := Write(buf, "hello world", Compound{"name": String("Bananrama")}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), helloWorld) {
+		t.Errorf("Expected %x, got %x", helloWorld, buf.Bytes())
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	root := Compound{
+		"byte":      Byte(-3),
+		"short":     Short(-300),
+		"int":       Int(123456789),
+		"long":      Long(-1 << 40),
+		"float":     Float(0.5),
+		"double":    Double(-1.25),
+		"bytes":     ByteArray{1, 2, 3},
+		"string":    String("héllo"),
+		"ints":      IntArray{-1, 0, 1},
+		"longs":     LongArray{1 << 62, -1},
+		"empty":     NewList(TagEnd),
+		"list":      NewList(TagShort, Short(1), Short(2)),
+		"compounds": NewList(TagCompound, Compound{"a": Byte(1)}, Compound{}),
+		"nested":    Compound{"inner": Compound{"value": String("x")}},
+	}
+	buf := new(bytes.Buffer)
+	if err := Write(buf, "root", root); err != nil {
+		t.Fatal(err)
+	}
+	name, read, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "root" {
+		t.Errorf("Expected name %q, got %q", "root", name)
+	}
+	if !reflect.DeepEqual(root, read) {
+		t.Errorf("Expected %v, got %v", root, read)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	cases := map[string][]byte{
+		"empty":           {},
+		"not a compound":  {0x01, 0x00, 0x00, 0x05},
+		"truncated":       helloWorld[:len(helloWorld)-1],
+		"invalid type":    {0x0A, 0x00, 0x00, 0x0D, 0x00, 0x00},
+		"negative length": {0x0A, 0x00, 0x00, 0x07, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF},
+		"huge array":      {0x0A, 0x00, 0x00, 0x0B, 0x00, 0x00, 0x7F, 0xFF, 0xFF, 0xFF},
+		"list of ends":    {0x0A, 0x00, 0x00, 0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+	}
+	for name, data := range cases {
+		if _, _, err := Read(bytes.NewReader(data)); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
+
+func TestReadTooDeep(t *testing.T) {
+	buf := new(bytes.Buffer)
+	buf.Write([]byte{0x0A, 0x00, 0x00})
+	for i := 0; i <= MaxDepth; i++ {
+		buf.Write([]byte{0x0A, 0x00, 0x00})
+	}
+	if _, _, err := Read(buf); err == nil {
+		t.Error("Expected an error for too many nested compounds")
+	}
+}
+
+func TestWriteInvalidList(t *testing.T) {
+	list := NewList(TagInt, Int(1))
+	list.Elements = append(list.Elements, Short(2))
+	if err := Write(new(bytes.Buffer), "", Compound{"list": list}); err == nil {
+		t.Error("Expected an error for a list of mixed types")
+	}
+}
+
+func TestCompressed(t *testing.T) {
+	root := Compound{"Data": Compound{"LevelName": String("world")}}
+	writers := map[string]func(*bytes.Buffer) error{
+		"gzip": func(buf *bytes.Buffer) error { return WriteGzip(buf, "", root) },
+		"zlib": func(buf *bytes.Buffer) error { return WriteZlib(buf, "", root) },
+		"none": func(buf *bytes.Buffer) error { return Write(buf, "", root) },
+	}
+	for compression, write := range writers {
+		buf := new(bytes.Buffer)
+		if err := write(buf); err != nil {
+			t.Fatal(compression, err)
+		}
+		_, read, err := ReadCompressed(buf)
+		if err != nil {
+			t.Fatal(compression, err)
+		}
+		if !reflect.DeepEqual(root, read) {
+			t.Errorf("%v: expected %v, got %v", compression, root, read)
+		}
+	}
+}
+
+// FuzzRead checks that the decoder does not panic, and that what it
+// reads is written and read back identically.
+func FuzzRead(f *testing.F) {
+	f.Add(helloWorld)
+	buf := new(bytes.Buffer)
+	Write(buf, "root", Compound{
+		"list":  NewList(TagCompound, Compound{"a": LongArray{1}}),
+		"ints":  IntArray{1, 2},
+		"float": Float(1.5),
+	})
+	f.Add(buf.Bytes())
+	f.Fuzz(func(t *testing.T, data []byte) {
+		name, root, err := Read(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		first := new(bytes.Buffer)
+		if err = Write(first, name, root); err != nil {
+			t.Fatal(err)
+		}
+		name, root, err = Read(bytes.NewReader(first.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		second := new(bytes.Buffer)
+		if err = Write(second, name, root); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			t.Errorf("Written data differs: %x, then %x", first.Bytes(), second.Bytes())
+		}
+	})
+}
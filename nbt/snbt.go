@@ -0,0 +1,136 @@
+package nbt
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// This file formats the tags as SNBT (stringified NBT), the text
+// format of the commands, for debugging:
+//
+//	{Name:"Steve",Pos:[0.5d,64.0d,0.5d],Health:20.0f,Inventory:[]}
+
+// the names which can be written without quotes
+var unquotedName = regexp.MustCompile(`^[A-Za-z0-9._+-]+$`)
+
+// SNBT returns the given tag formatted as SNBT. The tags
+// of the compounds are sorted by name.
+func SNBT(tag Tag) string {
+	builder := new(strings.Builder)
+	writeSNBT(builder, tag)
+	return builder.String()
+}
+
+// String returns the compound formatted as SNBT.
+func (c Compound) String() string {
+	return SNBT(c)
+}
+
+// String returns the list formatted as SNBT.
+func (l *List) String() string {
+	return SNBT(l)
+}
+
+func writeSNBT(b *strings.Builder, tag Tag) {
+	switch v := tag.(type) {
+	case Byte:
+		b.WriteString(strconv.Itoa(int(v)))
+		b.WriteByte('b')
+	case Short:
+		b.WriteString(strconv.Itoa(int(v)))
+		b.WriteByte('s')
+	case Int:
+		b.WriteString(strconv.Itoa(int(v)))
+	case Long:
+		b.WriteString(strconv.FormatInt(int64(v), 10))
+		b.WriteByte('L')
+	case Float:
+		b.WriteString(formatFloat(float64(v), 32))
+		b.WriteByte('f')
+	case Double:
+		b.WriteString(formatFloat(float64(v), 64))
+		b.WriteByte('d')
+	case String:
+		b.WriteString(quote(string(v)))
+	case ByteArray:
+		b.WriteString("[B;")
+		for i, e := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(int(int8(e))))
+			b.WriteByte('b')
+		}
+		b.WriteByte(']')
+	case IntArray:
+		b.WriteString("[I;")
+		for i, e := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(int(e)))
+		}
+		b.WriteByte(']')
+	case LongArray:
+		b.WriteString("[L;")
+		for i, e := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.FormatInt(e, 10))
+			b.WriteByte('L')
+		}
+		b.WriteByte(']')
+	case *List:
+		b.WriteByte('[')
+		if v != nil {
+			for i, e := range v.Elements {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				writeSNBT(b, e)
+			}
+		}
+		b.WriteByte(']')
+	case Compound:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		b.WriteByte('{')
+		for i, name := range names {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if unquotedName.MatchString(name) {
+				b.WriteString(name)
+			} else {
+				b.WriteString(quote(name))
+			}
+			b.WriteByte(':')
+			writeSNBT(b, v[name])
+		}
+		b.WriteByte('}')
+	default:
+		b.WriteString("null")
+	}
+}
+
+// formatFloat formats the given number so that it is read
+// back as a decimal number ("1.0" rather than "1").
+func formatFloat(f float64, bits int) string {
+	s := strconv.FormatFloat(f, 'g', -1, bits)
+	if !strings.ContainsAny(s, ".eEIN") {
+		s += ".0"
+	}
+	return s
+}
+
+// quote returns the given string in double quotes, escaping
+// the quotes and the backslashes.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,27 @@
+package nbt
+
+import "testing"
+
+func TestSNBT(t *testing.T) {
+	cases := map[string]Tag{
+		`1b`:                       Byte(1),
+		`-2s`:                      Short(-2),
+		`3`:                        Int(3),
+		`4L`:                       Long(4),
+		`0.5f`:                     Float(0.5),
+		`1.0d`:                     Double(1),
+		`"say \"hi\" \\o/"`:        String(`say "hi" \o/`),
+		`[B;1b,-1b]`:               ByteArray{1, 0xFF},
+		`[I;1,2]`:                  IntArray{1, 2},
+		`[L;3L]`:                   LongArray{3},
+		`[]`:                       NewList(TagEnd),
+		`[1s,2s]`:                  NewList(TagShort, Short(1), Short(2)),
+		`{}`:                       Compound{},
+		`{a:{b:"c"},"a b":1b,z:1}`: Compound{"z": Int(1), "a": Compound{"b": String("c")}, "a b": Byte(1)},
+	}
+	for expected, tag := range cases {
+		if s := SNBT(tag); s != expected {
+			t.Errorf("Expected %v, got %v", expected, s)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package nbt
+
+import "fmt"
+
+// TagType is the type of a tag, written before its value.
+type TagType byte
+
+const (
+	TagEnd TagType = iota
+	TagByte
+	TagShort
+	TagInt
+	TagLong
+	TagFloat
+	TagDouble
+	TagByteArray
+	TagString
+	TagList
+	TagCompound
+	TagIntArray
+	TagLongArray
+)
+
+var tagNames = [...]string{
+	"TAG_End", "TAG_Byte", "TAG_Short", "TAG_Int", "TAG_Long", "TAG_Float", "TAG_Double",
+	"TAG_Byte_Array", "TAG_String", "TAG_List", "TAG_Compound", "TAG_Int_Array", "TAG_Long_Array",
+}
+
+func (t TagType) String() string {
+	if int(t) < len(tagNames) {
+		return tagNames[t]
+	}
+	return fmt.Sprintf("TAG_Unknown(%d)", byte(t))
+}
+
+// Tag is a value of the tree.
+type Tag interface {
+	// Type returns the type of the tag.
+	Type() TagType
+}
+
+type (
+	Byte      int8
+	Short     int16
+	Int       int32
+	Long      int64
+	Float     float32
+	Double    float64
+	ByteArray []byte
+	String    string
+	IntArray  []int32
+	LongArray []int64
+
+	// List is a list of unnamed tags of the same type.
+	List struct {
+		ElementType TagType
+		Elements    []Tag
+	}
+
+	// Compound contains named tags of any type.
+	Compound map[string]Tag
+)
+
+func (Byte) Type() TagType      { return TagByte }
+func (Short) Type() TagType     { return TagShort }
+func (Int) Type() TagType       { return TagInt }
+func (Long) Type() TagType      { return TagLong }
+func (Float) Type() TagType     { return TagFloat }
+func (Double) Type() TagType    { return TagDouble }
+func (ByteArray) Type() TagType { return TagByteArray }
+func (String) Type() TagType    { return TagString }
+func (*List) Type() TagType     { return TagList }
+func (Compound) Type() TagType  { return TagCompound }
+func (IntArray) Type() TagType  { return TagIntArray }
+func (LongArray) Type() TagType { return TagLongArray }
+
+// NewList creates a list of the given type, containing the given elements.
+func NewList(elementType TagType, elements ...Tag) *List {
+	if elements == nil {
+		elements = make([]Tag, 0)
+	}
+	return &List{elementType, elements}
+}
+
+// Add appends the given tag to the list. Panics if its type is
+// not the type of the list.
+func (l *List) Add(tag Tag) {
+	if tag.Type() != l.ElementType {
+		panic(fmt.Sprintf("cannot add %v to a list of %v", tag.Type(), l.ElementType))
+	}
+	l.Elements = append(l.Elements, tag)
+}
+
+// Len returns the number of elements of the list.
+func (l *List) Len() int {
+	return len(l.Elements)
+}
+
+// The following getters return the zero value of the type if the tag
+// is missing, or if it is of another type.
+
+// GetByte returns the byte of the given name.
+func (c Compound) GetByte(name string) int8 {
+	v, _ := c[name].(Byte)
+	return int8(v)
+}
+
+// GetShort returns the short of the given name.
+func (c Compound) GetShort(name string) int16 {
+	v, _ := c[name].(Short)
+	return int16(v)
+}
+
+// GetInt returns the int of the given name.
+func (c Compound) GetInt(name string) int32 {
+	v, _ := c[name].(Int)
+	return int32(v)
+}
+
+// GetLong returns the long of the given name.
+func (c Compound) GetLong(name string) int64 {
+	v, _ := c[name].(Long)
+	return int64(v)
+}
+
+// GetFloat returns the float of the given name.
+func (c Compound) GetFloat(name string) float32 {
+	v, _ := c[name].(Float)
+	return float32(v)
+}
+
+// GetDouble returns the double of the given name.
+func (c Compound) GetDouble(name string) float64 {
+	v, _ := c[name].(Double)
+	return float64(v)
+}
+
+// GetByteArray returns the byte array of the given name.
+func (c Compound) GetByteArray(name string) []byte {
+	v, _ := c[name].(ByteArray)
+	return []byte(v)
+}
+
+// GetString returns the string of the given name.
+func (c Compound) GetString(name string) string {
+	v, _ := c[name].(String)
+	return string(v)
+}
+
+// GetList returns the list of the given name, or nil.
+func (c Compound) GetList(name string) *List {
+	v, _ := c[name].(*List)
+	return v
+}
+
+// GetCompound returns the compound of the given name, or nil.
+func (c Compound) GetCompound(name string) Compound {
+	v, _ := c[name].(Compound)
+	return v
+}
+
+// GetIntArray returns the int array of the given name.
+func (c Compound) GetIntArray(name string) []int32 {
+	v, _ := c[name].(IntArray)
+	return []int32(v)
+}
+
+// GetLongArray returns the long array of the given name.
+func (c Compound) GetLongArray(name string) []int64 {
+	v, _ := c[name].(LongArray)
+	return []int64(v)
+}
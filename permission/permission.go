@@ -1,7 +1,10 @@
 package permission
 
 const (
-	BanPermission  = "ban"  // allows to ban players
-	BasePermission = "base" // all the basic permissions (essentially basic commands)
-	StopServer     = "stop" // allows to stop the server
+	BanPermission  = "ban"         // allows to ban players
+	BasePermission = "base"        // all the basic permissions (essentially basic commands)
+	ChunkStats     = "chunks"      // allows to see the activity of the chunk pools
+	StopServer     = "stop"        // allows to stop the server
+	ChangeWorld    = "world"       // allows to move players to another world
+	WorldBorder    = "worldborder" // allows to change the border of the worlds
 )
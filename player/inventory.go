@@ -0,0 +1,117 @@
+package player
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/nbt"
+	"sync"
+)
+
+const (
+	// InventorySize is the number of slots of the inventory window of the players.
+	InventorySize = 46
+	// HotbarSize is the number of slots of the hotbar.
+	HotbarSize = 9
+	// OffhandSlot is the index, in the window, of the slot of the offhand.
+	OffhandSlot = 45
+	// the index, in the window, of the first slot of the hotbar
+	hotbarStart = 36
+)
+
+// ItemStack struct represents a stack of items.
+type ItemStack struct {
+	Material material.Material
+	Count    byte
+	Damage   int16        // the durability, or the variant of the material
+	Tag      nbt.Compound // nil if none
+}
+
+// Inventory struct represents the inventory of a player, indexed as its window:
+// 0 is the crafting output, 1-4 the crafting grid, 5-8 the armor,
+// 9-35 the main inventory, 36-44 the hotbar and 45 the offhand.
+type Inventory struct {
+	slots    [InventorySize]*ItemStack // nil if the slot is empty
+	heldSlot int                       // the selected slot of the hotbar
+	lock     sync.Mutex
+}
+
+// NewInventory creates an empty inventory.
+func NewInventory() *Inventory {
+	return &Inventory{}
+}
+
+// GetSlot returns the stack in the given slot, or nil if it is
+// empty or does not exist.
+func (inventory *Inventory) GetSlot(slot int) *ItemStack {
+	defer inventory.lock.Unlock()
+	inventory.lock.Lock()
+	if slot < 0 || slot >= InventorySize {
+		return nil
+	}
+	return inventory.slots[slot]
+}
+
+// SetSlot sets the stack of the given slot (nil to empty it).
+// Returns false if the slot does not exist.
+func (inventory *Inventory) SetSlot(slot int, stack *ItemStack) bool {
+	defer inventory.lock.Unlock()
+	inventory.lock.Lock()
+	if slot < 0 || slot >= InventorySize {
+		return false
+	}
+	if stack != nil && stack.Count == 0 {
+		stack = nil
+	}
+	inventory.slots[slot] = stack
+	return true
+}
+
+// GetHeldSlot returns the selected slot of the hotbar (from 0 to 8).
+func (inventory *Inventory) GetHeldSlot() int {
+	defer inventory.lock.Unlock()
+	inventory.lock.Lock()
+	return inventory.heldSlot
+}
+
+// SetHeldSlot selects the given slot of the hotbar. Returns false
+// if it does not exist.
+func (inventory *Inventory) SetHeldSlot(slot int) bool {
+	defer inventory.lock.Unlock()
+	inventory.lock.Lock()
+	if slot < 0 || slot >= HotbarSize {
+		return false
+	}
+	inventory.heldSlot = slot
+	return true
+}
+
+// HeldItemSlot returns the index, in the window, of the held item.
+func (inventory *Inventory) HeldItemSlot() int {
+	return hotbarStart + inventory.GetHeldSlot()
+}
+
+// GetHeldItem returns the stack held in the main hand, or nil if none.
+func (inventory *Inventory) GetHeldItem() *ItemStack {
+	return inventory.GetSlot(inventory.HeldItemSlot())
+}
+
+// RemoveItem removes the given number of items from the stack of the
+// given slot. Returns false if it does not contain as many.
+func (inventory *Inventory) RemoveItem(slot int, count byte) bool {
+	defer inventory.lock.Unlock()
+	inventory.lock.Lock()
+	if slot < 0 || slot >= InventorySize {
+		return false
+	}
+	stack := inventory.slots[slot]
+	if stack == nil || stack.Count < count {
+		return false
+	}
+	if stack.Count == count {
+		inventory.slots[slot] = nil
+	} else {
+		removed := *stack
+		removed.Count -= count
+		inventory.slots[slot] = &removed
+	}
+	return true
+}
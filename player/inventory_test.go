@@ -0,0 +1,27 @@
+package player
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"testing"
+)
+
+func TestInventoryHeldItem(t *testing.T) {
+	inventory := NewInventory()
+	if inventory.SetHeldSlot(HotbarSize) {
+		t.Error("The slot after the hotbar should not be selectable")
+	}
+	inventory.SetHeldSlot(2)
+	inventory.SetSlot(38, &ItemStack{Material: material.Stone, Count: 2})
+	if held := inventory.GetHeldItem(); held == nil || held.Material != material.Stone {
+		t.Fatal("The held item should be the stone of the slot 38, got", held)
+	}
+	if !inventory.RemoveItem(38, 1) || inventory.GetHeldItem().Count != 1 {
+		t.Error("One stone should remain")
+	}
+	if inventory.RemoveItem(38, 2) {
+		t.Error("Cannot remove more items than the stack contains")
+	}
+	if !inventory.RemoveItem(38, 1) || inventory.GetHeldItem() != nil {
+		t.Error("The slot should be emptied")
+	}
+}
@@ -0,0 +1,100 @@
+package player
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"sync"
+)
+
+// IPBanList struct contains the IP addresses which have been banned.
+// The addresses are normalized, so that all the forms of an address
+// match its ban.
+type IPBanList struct {
+	ips  map[string]string // normalized ip => reason
+	lock sync.Mutex
+}
+
+type IPBanEntry struct {
+	IP     string `json:"ip"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func NewIPBanList() *IPBanList {
+	return &IPBanList{
+		ips: make(map[string]string),
+	}
+}
+
+// LoadFile loads the IP ban list from the given file.
+func (list *IPBanList) LoadFile(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	bans := make([]IPBanEntry, 0)
+	if err = json.Unmarshal(content, &bans); err != nil {
+		return err
+	}
+
+	list.lock.Lock()
+	for _, entry := range bans {
+		list.ips[normalizeIP(entry.IP)] = entry.Reason
+	}
+	list.lock.Unlock()
+	return nil
+}
+
+// SaveFile saves the IP ban list in the given file.
+func (list *IPBanList) SaveFile(path string) error {
+	list.lock.Lock()
+	entries := make([]IPBanEntry, 0, len(list.ips))
+	for ip, reason := range list.ips {
+		entries = append(entries, IPBanEntry{IP: ip, Reason: reason})
+	}
+	list.lock.Unlock()
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// AddIP adds the given IP to the list.
+func (list *IPBanList) AddIP(ip, reason string) {
+	list.lock.Lock()
+	list.ips[normalizeIP(ip)] = reason
+	list.lock.Unlock()
+}
+
+// RemoveIP removes the given IP from the list.
+func (list *IPBanList) RemoveIP(ip string) {
+	list.lock.Lock()
+	delete(list.ips, normalizeIP(ip))
+	list.lock.Unlock()
+}
+
+// IsBanned returns true if the given IP has been banned
+// with the reason of its ban. Otherwise, returns false and
+// an empty string.
+func (list *IPBanList) IsBanned(ip string) (bool, string) {
+	defer list.lock.Unlock()
+	list.lock.Lock()
+	reason, ok := list.ips[normalizeIP(ip)]
+	return ok, reason
+}
+
+// normalizeIP returns the canonical form of the given IP: the IPv4
+// addresses mapped to IPv6 are written as IPv4, and the IPv6 ones
+// are compressed in lower case. The invalid IPs are left as they are.
+func normalizeIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.String()
+	}
+	return parsed.String()
+}
@@ -0,0 +1,25 @@
+package player
+
+import "testing"
+
+func TestIPBanListNormalization(t *testing.T) {
+	list := NewIPBanList()
+	list.AddIP("::ffff:192.168.1.2", "mapped")
+	list.AddIP("2001:DB8:0:0:0:0:0:1", "v6")
+	if banned, reason := list.IsBanned("192.168.1.2"); !banned || reason != "mapped" {
+		t.Error("The IPv4 address should be banned by its mapped form")
+	}
+	if banned, _ := list.IsBanned("::ffff:c0a8:102"); !banned {
+		t.Error("All the forms of the address should be banned")
+	}
+	if banned, reason := list.IsBanned("2001:db8::1"); !banned || reason != "v6" {
+		t.Error("The compressed IPv6 address should be banned")
+	}
+	list.RemoveIP("192.168.1.2")
+	if banned, _ := list.IsBanned("::ffff:192.168.1.2"); banned {
+		t.Error("The address should have been unbanned")
+	}
+	if normalizeIP("not an ip") != "not an ip" {
+		t.Error("The invalid IPs should be left as they are")
+	}
+}
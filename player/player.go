@@ -3,6 +3,8 @@ package player
 import (
 	"github.com/olsdavis/goelan/world"
 	"github.com/olsdavis/goelan/util"
+	"net"
+	"sync"
 )
 
 type PlayerProfile struct {
@@ -10,6 +12,7 @@ type PlayerProfile struct {
 	UUID       string     `json:"id"`
 	Name       string     `json:"name"`
 	Properties []Property `json:"properties"`
+	Address    net.Addr   `json:"-"` // the real address of the player (forwarded by the proxy, if any)
 }
 
 type Property struct {
@@ -18,13 +21,102 @@ type Property struct {
 	Signature string `json:"signature"`
 }
 
+const (
+	// the health and the food of the players when they spawn
+	MaxHealth         = 20
+	MaxFood           = 20
+	DefaultSaturation = 5
+)
+
 type Player struct {
 	// key => the permission; value => true if the player has the permission
 	Permissions map[string]bool
 	Profile     PlayerProfile
 	Settings    *ClientSettings
-	Location    *world.Location
-	GameMode    GameMode
+	// the location and the health are locked once the player has joined,
+	// since the routines of the other players and the ticks read them: use
+	// the methods below
+	Location   *world.Location
+	GameMode   GameMode
+	Inventory  *Inventory
+	Health     float32 // the player is dead at 0
+	Food       int32
+	Saturation float32
+	lock       sync.Mutex
+}
+
+// GetLocation returns a copy of the location of the player.
+func (player *Player) GetLocation() world.Location {
+	defer player.lock.Unlock()
+	player.lock.Lock()
+	return *player.Location
+}
+
+// SetLocation sets the location of the player, which may be in another world.
+func (player *Player) SetLocation(location *world.Location) {
+	defer player.lock.Unlock()
+	player.lock.Lock()
+	player.Location = location
+}
+
+// Move sets the coordinates of the player in its world.
+func (player *Player) Move(x, y, z float32) {
+	defer player.lock.Unlock()
+	player.lock.Lock()
+	player.Location.X, player.Location.Y, player.Location.Z = x, y, z
+}
+
+// Look sets the rotation of the player.
+func (player *Player) Look(yaw, pitch float32) {
+	defer player.lock.Unlock()
+	player.lock.Lock()
+	player.Location.Yaw, player.Location.Pitch = yaw, pitch
+}
+
+// GetHealth returns the health, the food and the saturation of the player.
+func (player *Player) GetHealth() (float32, int32, float32) {
+	defer player.lock.Unlock()
+	player.lock.Lock()
+	return player.Health, player.Food, player.Saturation
+}
+
+// Hurt removes the given amount of health from the player. Returns false
+// if the player was already dead.
+func (player *Player) Hurt(amount float32) bool {
+	defer player.lock.Unlock()
+	player.lock.Lock()
+	if player.Health <= 0 {
+		return false
+	}
+	player.Health -= amount
+	if player.Health < 0 {
+		player.Health = 0
+	}
+	return true
+}
+
+// Revive restores the health, the food and the saturation of the dead
+// player. Returns false if the player is not dead.
+func (player *Player) Revive() bool {
+	defer player.lock.Unlock()
+	player.lock.Lock()
+	if player.Health > 0 {
+		return false
+	}
+	player.Health, player.Food, player.Saturation = MaxHealth, MaxFood, DefaultSaturation
+	return true
+}
+
+// IsDead returns true if the player has no health left.
+func (player *Player) IsDead() bool {
+	defer player.lock.Unlock()
+	player.lock.Lock()
+	return player.Health <= 0
+}
+
+// CanTakeDamage returns true if the gamemode of the player lets it be hurt.
+func (player *Player) CanTakeDamage() bool {
+	return player.GameMode == SurvivalMode || player.GameMode == AdventureMode
 }
 
 // HasPermission returns true if the player has the given permission.
@@ -0,0 +1,42 @@
+package player
+
+import (
+	"github.com/olsdavis/goelan/world"
+	"testing"
+)
+
+func TestDamageWhileMoving(t *testing.T) {
+	w := world.NewWorld("test")
+	pl := &Player{Location: world.NewLocation(60, 64, 0, w), Health: MaxHealth}
+
+	// the player moves and respawns in its reading routine, while
+	// the ticks hurt it according to its location
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			pl.Move(float32(i), 64, 0)
+			pl.Look(float32(i), 0)
+			pl.Revive()
+		}
+		pl.SetLocation(world.NewLocation(0, 64, 0, w))
+	}()
+	for i := 0; i < 1000; i++ {
+		if location := pl.GetLocation(); location.World == w {
+			pl.Hurt(0.01)
+		}
+	}
+	<-done
+	if location := pl.GetLocation(); location.X != 0 {
+		t.Error("The player should be at the last location set, got", location.X)
+	}
+	if !pl.Hurt(MaxHealth) || !pl.IsDead() || pl.Hurt(1) {
+		t.Error("A dead player should not be hurt")
+	}
+	if !pl.Revive() || pl.Revive() {
+		t.Error("Only a dead player should be revived")
+	}
+	if health, food, _ := pl.GetHealth(); health != MaxHealth || food != MaxFood {
+		t.Error("A revived player should have all its health and food, got", health, food)
+	}
+}
@@ -0,0 +1,53 @@
+package protocol
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+)
+
+const (
+	// MaxUncompressedLength is the maximal length of a packet once
+	// it has been decompressed (2^21 bytes).
+	MaxUncompressedLength = 2097152
+)
+
+var (
+	BadlyCompressedError = errors.New("badly compressed packet")
+)
+
+// Compress compresses the given data with zlib.
+func Compress(data []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	writer := zlib.NewWriter(buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses the given zlib data, which must
+// be exactly size bytes long once decompressed.
+func Decompress(data []byte, size int) ([]byte, error) {
+	if size > MaxUncompressedLength {
+		return nil, BadlyCompressedError
+	}
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	ret := make([]byte, size)
+	if _, err = io.ReadFull(reader, ret); err != nil {
+		return nil, err
+	}
+	// the declared size must match the real size
+	if n, _ := reader.Read(make([]byte, 1)); n != 0 {
+		return nil, BadlyCompressedError
+	}
+	return ret, nil
+}
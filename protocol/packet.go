@@ -0,0 +1,113 @@
+package protocol
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConnectionState represents the state of a connection (handshake, login or play).
+type ConnectionState int
+
+const (
+	HandshakeState ConnectionState = iota
+	LoginState
+	PlayState
+)
+
+// Direction represents the direction in which a packet is sent.
+type Direction int
+
+const (
+	Serverbound Direction = iota // sent by the client to the server
+	Clientbound                  // sent by the server to the client
+)
+
+// Packet is implemented by all the typed packets. Encode and Decode must
+// be symmetric: decoding what has been encoded gives back the same packet.
+// Packets whose layout depends on the protocol version can read it with
+// Response.ProtocolVersion and RawPacket.Version.
+type Packet interface {
+	// Encode writes the packet's fields to the given response.
+	Encode(r *Response)
+
+	// Decode reads the packet's fields from the given raw packet.
+	Decode(r *RawPacket) error
+}
+
+type packetKey struct {
+	state     ConnectionState
+	direction Direction
+	id        uint64
+}
+
+type packetTypeKey struct {
+	state     ConnectionState
+	direction Direction
+	t         reflect.Type
+}
+
+var (
+	packetFactories = make(map[packetKey]func() Packet)
+	packetIds       = make(map[packetTypeKey]uint64)
+)
+
+// RegisterPacket associates the packet created by the given factory to the
+// given state, direction and ID (of the latest version). The factory must
+// return a pointer.
+func RegisterPacket(state ConnectionState, direction Direction, id uint64, factory func() Packet) {
+	packetFactories[packetKey{state, direction, id}] = factory
+	packetIds[packetTypeKey{state, direction, reflect.TypeOf(factory())}] = id
+}
+
+// NewPacket creates an empty packet of the type registered for the given
+// state, direction and ID (of the latest version). Returns false if no packet
+// has been registered.
+func NewPacket(state ConnectionState, direction Direction, id uint64) (Packet, bool) {
+	factory, ok := packetFactories[packetKey{state, direction, id}]
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// PacketID returns the ID of the given packet in the given state and direction,
+// in the latest version. Returns false if the packet has not been registered.
+func PacketID(state ConnectionState, direction Direction, packet Packet) (uint64, bool) {
+	id, ok := packetIds[packetTypeKey{state, direction, reflect.TypeOf(packet)}]
+	return id, ok
+}
+
+// Marshal encodes the given packet to a RawPacket, with the ID it has
+// in the given protocol version, state and direction.
+func Marshal(version uint32, state ConnectionState, direction Direction, packet Packet) (*RawPacket, error) {
+	latest, ok := PacketID(state, direction, packet)
+	if !ok {
+		return nil, fmt.Errorf("packet %T is not registered in state %v", packet, state)
+	}
+	id, ok := ToVersionID(version, state, direction, latest)
+	if !ok {
+		return nil, fmt.Errorf("packet %T does not exist in protocol version %v", packet, version)
+	}
+	response := NewVersionedResponse(version)
+	packet.Encode(response)
+	return response.ToRawPacket(id), nil
+}
+
+// Unmarshal decodes the given RawPacket, which's ID is the one of the given
+// protocol version, to the packet registered for it in the given state and
+// direction.
+func Unmarshal(version uint32, state ConnectionState, direction Direction, raw *RawPacket) (Packet, error) {
+	latest, ok := ToLatestID(version, state, direction, raw.ID)
+	if !ok {
+		return nil, fmt.Errorf("no packet registered for ID %#x in state %v of version %v", raw.ID, state, version)
+	}
+	packet, ok := NewPacket(state, direction, latest)
+	if !ok {
+		return nil, fmt.Errorf("no packet registered for ID %#x in state %v", raw.ID, state)
+	}
+	raw.Version = version
+	if err := packet.Decode(raw); err != nil {
+		return nil, err
+	}
+	return packet, nil
+}
@@ -4,10 +4,16 @@
 package protocol
 
 import (
-	"github.com/olsdavis/goelan/world"
+	"encoding/json"
+	"github.com/olsdavis/goelan/player"
 	"github.com/olsdavis/goelan/util"
+	"github.com/olsdavis/goelan/world"
+	"unicode/utf8"
 )
 
+// the maximal length of the chat messages sent by the clients, in characters
+const maxChatLength = 256
+
 type (
 	PositionAndLookPacket struct {
 		world.Location
@@ -26,4 +32,740 @@ type (
 		PlayerUUID util.UUID
 		world.Location3f
 	}
+
+	JoinGamePacket struct {
+		EntityID         int32
+		GameMode         uint8
+		Dimension        int32
+		Difficulty       uint8
+		MaxPlayers       uint8
+		LevelType        string
+		ReducedDebugInfo bool
+	}
+
+	// RespawnPacket moves the player to another dimension, or
+	// respawns it. The client unloads all its chunks.
+	RespawnPacket struct {
+		Dimension  int32
+		Difficulty uint8
+		GameMode   uint8
+		LevelType  string
+	}
+
+	// SpawnPositionPacket sets the position the compass points to.
+	SpawnPositionPacket struct {
+		X, Y, Z int32
+	}
+
+	// TimeUpdatePacket sets the time of the world. The client does not
+	// advance the time of the day if it is negative.
+	TimeUpdatePacket struct {
+		WorldAge  int64
+		TimeOfDay int64
+	}
+
+	// ChangeGameStatePacket changes the weather, among other states.
+	ChangeGameStatePacket struct {
+		Reason uint8
+		Value  float32
+	}
+
+	// UpdateHealthPacket sets the health and the food of the player.
+	UpdateHealthPacket struct {
+		Health     float32 // the player is dead at 0
+		Food       int32
+		Saturation float32
+	}
+
+	// CombatEventPacket is only sent when the player dies: it shows
+	// the death screen (the other events are not used by the clients).
+	CombatEventPacket struct {
+		Event    int32
+		PlayerID int32 // the entity ID of the dead player
+		KillerID int32 // -1 if none
+		Message  ChatComponent
+	}
+
+	// DisconnectPacket is used in both login and play states.
+	DisconnectPacket struct {
+		Reason ChatComponent
+	}
+
+	ChatPacket struct {
+		Message  ChatComponent
+		Position MessageMode
+	}
+
+	// KeepAlivePacket is used in both directions. Its ID is
+	// written as a Varint before 1.12.2.
+	KeepAlivePacket struct {
+		ID int64
+	}
+
+	PlayerListItemPacket struct {
+		Action  int32
+		Players []PlayerListEntry
+	}
+
+	// PlayerListEntry represents a player in the PlayerListItemPacket.
+	// The fields that are written depend on packet's action.
+	PlayerListEntry struct {
+		UUID        util.UUID
+		Name        string            // add player
+		Properties  []player.Property // add player
+		GameMode    int32             // add player, update gamemode
+		Ping        int32             // add player, update latency
+		DisplayName *ChatComponent    // add player, update display name (nil if none)
+	}
+
+	/* Serverbound */
+
+	TeleportConfirmPacket struct {
+		TeleportID int32
+	}
+
+	IncomingChatPacket struct {
+		Message string
+	}
+
+	ClientStatusPacket struct {
+		ActionID int32
+	}
+
+	ClientSettingsPacket struct {
+		Locale             string
+		ViewDistance       byte
+		ChatMode           int32
+		ChatColors         bool
+		DisplayedSkinParts uint8
+		MainHand           int32
+	}
+
+	PluginMessagePacket struct {
+		Channel string
+		Data    []byte
+	}
+
+	IncomingPositionAndLookPacket struct {
+		X, Y, Z    float64
+		Yaw, Pitch float32
+		OnGround   bool
+	}
+
+	IncomingPositionPacket struct {
+		X, Y, Z  float64
+		OnGround bool
+	}
+
+	IncomingLookPacket struct {
+		Yaw, Pitch float32
+		OnGround   bool
+	}
+
+	AnimationPacket struct {
+		Hand int32
+	}
+
+	ClickWindowPacket struct {
+		WindowID     uint8
+		Slot         int16
+		Button       int8
+		ActionNumber int16
+		Mode         int32
+		ClickedItem  []byte // raw slot data
+	}
+
+	CloseWindowPacket struct {
+		WindowID uint8
+	}
 )
+
+func init() {
+	RegisterPacket(PlayState, Clientbound, OutgoingChatPacketId, func() Packet { return &ChatPacket{} })
+	RegisterPacket(PlayState, Clientbound, KickPlayerPacketId, func() Packet { return &DisconnectPacket{} })
+	RegisterPacket(PlayState, Clientbound, KeepAliveOutgoingPacketId, func() Packet { return &KeepAlivePacket{} })
+	RegisterPacket(PlayState, Clientbound, JoinGamePacketId, func() Packet { return &JoinGamePacket{} })
+	RegisterPacket(PlayState, Clientbound, PlayerAbilitiesPacketId, func() Packet { return &PlayerAbilitiesPacket{} })
+	RegisterPacket(PlayState, Clientbound, PlayerListItemPacketId, func() Packet { return &PlayerListItemPacket{} })
+	RegisterPacket(PlayState, Clientbound, OutgoingPlayerPositionAndLookPacketId, func() Packet { return &PositionAndLookPacket{} })
+	RegisterPacket(PlayState, Clientbound, SpawnPositionPacketId, func() Packet { return &SpawnPositionPacket{} })
+	RegisterPacket(PlayState, Clientbound, RespawnPacketId, func() Packet { return &RespawnPacket{} })
+	RegisterPacket(PlayState, Clientbound, TimeUpdatePacketId, func() Packet { return &TimeUpdatePacket{} })
+	RegisterPacket(PlayState, Clientbound, ChangeGameStatePacketId, func() Packet { return &ChangeGameStatePacket{} })
+	RegisterPacket(PlayState, Clientbound, UpdateHealthPacketId, func() Packet { return &UpdateHealthPacket{} })
+	RegisterPacket(PlayState, Clientbound, CombatEventPacketId, func() Packet { return &CombatEventPacket{} })
+
+	RegisterPacket(PlayState, Serverbound, TeleportConfirmPacketId, func() Packet { return &TeleportConfirmPacket{} })
+	RegisterPacket(PlayState, Serverbound, IncomingChatPacketId, func() Packet { return &IncomingChatPacket{} })
+	RegisterPacket(PlayState, Serverbound, ClientStatusPacketId, func() Packet { return &ClientStatusPacket{} })
+	RegisterPacket(PlayState, Serverbound, ClientSettingsPacketId, func() Packet { return &ClientSettingsPacket{} })
+	RegisterPacket(PlayState, Serverbound, PluginMessagePacketId, func() Packet { return &PluginMessagePacket{} })
+	RegisterPacket(PlayState, Serverbound, KeepAliveIncomingPacketId, func() Packet { return &KeepAlivePacket{} })
+	RegisterPacket(PlayState, Serverbound, IncomingPlayerPositionAndLookPacketId, func() Packet { return &IncomingPositionAndLookPacket{} })
+	RegisterPacket(PlayState, Serverbound, IncomingPlayerPositionPacketId, func() Packet { return &IncomingPositionPacket{} })
+	RegisterPacket(PlayState, Serverbound, IncomingPlayerLookPacketId, func() Packet { return &IncomingLookPacket{} })
+	RegisterPacket(PlayState, Serverbound, IncomingAnimationPacketId, func() Packet { return &AnimationPacket{} })
+	RegisterPacket(PlayState, Serverbound, ClickWindowPacketId, func() Packet { return &ClickWindowPacket{} })
+	RegisterPacket(PlayState, Serverbound, CloseWindowPacketId, func() Packet { return &CloseWindowPacket{} })
+}
+
+func (p *SpawnPositionPacket) Encode(r *Response) {
+	r.WritePosition(p.X, p.Y, p.Z)
+}
+
+func (p *SpawnPositionPacket) Decode(r *RawPacket) (err error) {
+	p.X, p.Y, p.Z, err = r.ReadPosition()
+	return
+}
+
+func (p *TimeUpdatePacket) Encode(r *Response) {
+	r.WriteLong(p.WorldAge)
+	r.WriteLong(p.TimeOfDay)
+}
+
+func (p *TimeUpdatePacket) Decode(r *RawPacket) (err error) {
+	if p.WorldAge, err = r.ReadLong(); err != nil {
+		return
+	}
+	p.TimeOfDay, err = r.ReadLong()
+	return
+}
+
+func (p *ChangeGameStatePacket) Encode(r *Response) {
+	r.WriteUnsignedByte(p.Reason)
+	r.WriteFloat(p.Value)
+}
+
+func (p *ChangeGameStatePacket) Decode(r *RawPacket) (err error) {
+	if p.Reason, err = r.ReadUnsignedByte(); err != nil {
+		return
+	}
+	p.Value, err = r.ReadFloat()
+	return
+}
+
+func (p *UpdateHealthPacket) Encode(r *Response) {
+	r.WriteFloat(p.Health)
+	r.WriteVarint(p.Food)
+	r.WriteFloat(p.Saturation)
+}
+
+func (p *UpdateHealthPacket) Decode(r *RawPacket) (err error) {
+	if p.Health, err = r.ReadFloat(); err != nil {
+		return
+	}
+	if p.Food, err = r.ReadVarint(); err != nil {
+		return
+	}
+	p.Saturation, err = r.ReadFloat()
+	return
+}
+
+func (p *CombatEventPacket) Encode(r *Response) {
+	r.WriteVarint(p.Event)
+	r.WriteVarint(p.PlayerID)
+	r.WriteInt(int(p.KillerID))
+	r.WriteJSON(p.Message)
+}
+
+func (p *CombatEventPacket) Decode(r *RawPacket) (err error) {
+	if p.Event, err = r.ReadVarint(); err != nil {
+		return
+	}
+	if p.Event != CombatEventEntityDead {
+		return NewProtocolError("unsupported combat event %v", p.Event)
+	}
+	if p.PlayerID, err = r.ReadVarint(); err != nil {
+		return
+	}
+	if p.KillerID, err = r.ReadInt(); err != nil {
+		return
+	}
+	message, err := r.ReadByteArray()
+	if err != nil {
+		return
+	}
+	return json.Unmarshal(message, &p.Message)
+}
+
+func (p *PositionAndLookPacket) Encode(r *Response) {
+	r.WriteDouble(float64(p.X))
+	r.WriteDouble(float64(p.Y))
+	r.WriteDouble(float64(p.Z))
+	r.WriteFloat(p.Yaw)
+	r.WriteFloat(p.Pitch)
+	r.WriteByte(p.Flags)
+	r.WriteVarint(p.TeleportID)
+}
+
+func (p *PositionAndLookPacket) Decode(r *RawPacket) error {
+	x, err := r.ReadDouble()
+	if err != nil {
+		return err
+	}
+	y, err := r.ReadDouble()
+	if err != nil {
+		return err
+	}
+	z, err := r.ReadDouble()
+	if err != nil {
+		return err
+	}
+	p.X, p.Y, p.Z = float32(x), float32(y), float32(z)
+	if p.Yaw, err = r.ReadFloat(); err != nil {
+		return err
+	}
+	if p.Pitch, err = r.ReadFloat(); err != nil {
+		return err
+	}
+	flags, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	p.Flags = int8(flags)
+	p.TeleportID, err = r.ReadVarint()
+	return err
+}
+
+func (p *PlayerAbilitiesPacket) Encode(r *Response) {
+	r.WriteByte(p.Flags)
+	r.WriteFloat(p.FlyingSpeed)
+	r.WriteFloat(p.FovModifier)
+}
+
+func (p *PlayerAbilitiesPacket) Decode(r *RawPacket) error {
+	flags, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	p.Flags = int8(flags)
+	if p.FlyingSpeed, err = r.ReadFloat(); err != nil {
+		return err
+	}
+	p.FovModifier, err = r.ReadFloat()
+	return err
+}
+
+func (p *JoinGamePacket) Encode(r *Response) {
+	r.WriteInt(int(p.EntityID))
+	r.WriteUnsignedByte(p.GameMode)
+	r.WriteInt(int(p.Dimension))
+	r.WriteUnsignedByte(p.Difficulty)
+	r.WriteUnsignedByte(p.MaxPlayers)
+	r.WriteString(p.LevelType)
+	r.WriteBoolean(p.ReducedDebugInfo)
+}
+
+func (p *JoinGamePacket) Decode(r *RawPacket) (err error) {
+	if p.EntityID, err = r.ReadInt(); err != nil {
+		return
+	}
+	if p.GameMode, err = r.ReadUnsignedByte(); err != nil {
+		return
+	}
+	if p.Dimension, err = r.ReadInt(); err != nil {
+		return
+	}
+	if p.Difficulty, err = r.ReadUnsignedByte(); err != nil {
+		return
+	}
+	if p.MaxPlayers, err = r.ReadUnsignedByte(); err != nil {
+		return
+	}
+	if p.LevelType, err = r.ReadStringMax(16); err != nil {
+		return
+	}
+	p.ReducedDebugInfo, err = r.ReadBoolean()
+	return
+}
+
+func (p *RespawnPacket) Encode(r *Response) {
+	r.WriteInt(int(p.Dimension))
+	r.WriteUnsignedByte(p.Difficulty)
+	r.WriteUnsignedByte(p.GameMode)
+	r.WriteString(p.LevelType)
+}
+
+func (p *RespawnPacket) Decode(r *RawPacket) (err error) {
+	if p.Dimension, err = r.ReadInt(); err != nil {
+		return
+	}
+	if p.Difficulty, err = r.ReadUnsignedByte(); err != nil {
+		return
+	}
+	if p.GameMode, err = r.ReadUnsignedByte(); err != nil {
+		return
+	}
+	p.LevelType, err = r.ReadStringMax(16)
+	return
+}
+
+func (p *DisconnectPacket) Encode(r *Response) {
+	r.WriteJSON(p.Reason)
+}
+
+func (p *DisconnectPacket) Decode(r *RawPacket) error {
+	reason, err := r.ReadByteArray()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(reason, &p.Reason)
+}
+
+func (p *ChatPacket) Encode(r *Response) {
+	r.WriteJSON(p.Message)
+	r.WriteUnsignedByte(byte(p.Position))
+}
+
+func (p *ChatPacket) Decode(r *RawPacket) error {
+	message, err := r.ReadByteArray()
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(message, &p.Message); err != nil {
+		return err
+	}
+	position, err := r.ReadUnsignedByte()
+	p.Position = MessageMode(position)
+	return err
+}
+
+func (p *KeepAlivePacket) Encode(r *Response) {
+	if r.ProtocolVersion() < Protocol1_12_2 {
+		r.WriteVarint(int32(p.ID))
+	} else {
+		r.WriteLong(p.ID)
+	}
+}
+
+func (p *KeepAlivePacket) Decode(r *RawPacket) (err error) {
+	if r.Version < Protocol1_12_2 {
+		id, err := r.ReadVarint()
+		p.ID = int64(id)
+		return err
+	}
+	p.ID, err = r.ReadLong()
+	return
+}
+
+func (p *PlayerListItemPacket) Encode(r *Response) {
+	r.WriteVarint(p.Action)
+	r.WriteVarint(int32(len(p.Players)))
+	for _, entry := range p.Players {
+		r.WriteUUID(entry.UUID)
+		switch p.Action {
+		case PlayerListItemActionAddPlayer:
+			r.WriteString(entry.Name)
+			r.WriteVarint(int32(len(entry.Properties)))
+			for _, property := range entry.Properties {
+				r.WriteString(property.Name)
+				r.WriteString(property.Value)
+				if property.Signature == "" {
+					r.WriteBoolean(false)
+				} else {
+					r.WriteBoolean(true)
+					r.WriteString(property.Signature)
+				}
+			}
+			r.WriteVarint(entry.GameMode)
+			r.WriteVarint(entry.Ping)
+			entry.writeDisplayName(r)
+		case PlayerListItemActionUpdateGamemode:
+			r.WriteVarint(entry.GameMode)
+		case PlayerListItemActionUpdateLatency:
+			r.WriteVarint(entry.Ping)
+		case PlayerListItemActionUpdateDisplayName:
+			entry.writeDisplayName(r)
+		}
+	}
+}
+
+func (p *PlayerListItemPacket) Decode(r *RawPacket) (err error) {
+	if p.Action, err = r.ReadVarint(); err != nil {
+		return
+	}
+	count, err := r.ReadVarint()
+	if err != nil {
+		return
+	}
+	if count < 0 {
+		return NewProtocolError("negative player count %v", count)
+	}
+	p.Players = make([]PlayerListEntry, 0, util.Min(int(count), 64))
+	for i := int32(0); i < count; i++ {
+		var entry PlayerListEntry
+		if entry.UUID, err = r.ReadUUID(); err != nil {
+			return
+		}
+		switch p.Action {
+		case PlayerListItemActionAddPlayer:
+			if entry.Name, err = r.ReadStringMax(16); err != nil {
+				return
+			}
+			if entry.Properties, err = readProperties(r); err != nil {
+				return
+			}
+			if entry.GameMode, err = r.ReadVarint(); err != nil {
+				return
+			}
+			if entry.Ping, err = r.ReadVarint(); err != nil {
+				return
+			}
+			err = entry.readDisplayName(r)
+		case PlayerListItemActionUpdateGamemode:
+			entry.GameMode, err = r.ReadVarint()
+		case PlayerListItemActionUpdateLatency:
+			entry.Ping, err = r.ReadVarint()
+		case PlayerListItemActionUpdateDisplayName:
+			err = entry.readDisplayName(r)
+		}
+		if err != nil {
+			return
+		}
+		p.Players = append(p.Players, entry)
+	}
+	return nil
+}
+
+// readProperties reads the properties of a player (nil if there is none).
+func readProperties(r *RawPacket) ([]player.Property, error) {
+	count, err := r.ReadVarint()
+	if err != nil || count <= 0 {
+		return nil, err
+	}
+	properties := make([]player.Property, 0, util.Min(int(count), 16))
+	for i := int32(0); i < count; i++ {
+		var property player.Property
+		if property.Name, err = r.ReadString(); err != nil {
+			return nil, err
+		}
+		if property.Value, err = r.ReadString(); err != nil {
+			return nil, err
+		}
+		signed, err := r.ReadBoolean()
+		if err != nil {
+			return nil, err
+		}
+		if signed {
+			if property.Signature, err = r.ReadString(); err != nil {
+				return nil, err
+			}
+		}
+		properties = append(properties, property)
+	}
+	return properties, nil
+}
+
+func (entry *PlayerListEntry) writeDisplayName(r *Response) {
+	r.WriteBoolean(entry.DisplayName != nil)
+	if entry.DisplayName != nil {
+		r.WriteJSON(*entry.DisplayName)
+	}
+}
+
+func (entry *PlayerListEntry) readDisplayName(r *RawPacket) error {
+	if has, err := r.ReadBoolean(); err != nil || !has {
+		return err
+	}
+	displayName, err := r.ReadByteArray()
+	if err != nil {
+		return err
+	}
+	entry.DisplayName = &ChatComponent{}
+	return json.Unmarshal(displayName, entry.DisplayName)
+}
+
+func (p *TeleportConfirmPacket) Encode(r *Response) {
+	r.WriteVarint(p.TeleportID)
+}
+
+func (p *TeleportConfirmPacket) Decode(r *RawPacket) (err error) {
+	p.TeleportID, err = r.ReadVarint()
+	return
+}
+
+func (p *IncomingChatPacket) Encode(r *Response) {
+	r.WriteString(p.Message)
+}
+
+func (p *IncomingChatPacket) Decode(r *RawPacket) (err error) {
+	// the limit is in characters, which take up to 4 bytes each
+	if p.Message, err = r.ReadStringMax(maxChatLength * utf8.UTFMax); err != nil {
+		return
+	}
+	if length := utf8.RuneCountInString(p.Message); length > maxChatLength {
+		return NewProtocolError("chat message is too long (%v characters)", length)
+	}
+	return
+}
+
+func (p *ClientStatusPacket) Encode(r *Response) {
+	r.WriteVarint(p.ActionID)
+}
+
+func (p *ClientStatusPacket) Decode(r *RawPacket) (err error) {
+	p.ActionID, err = r.ReadVarint()
+	return
+}
+
+func (p *ClientSettingsPacket) Encode(r *Response) {
+	r.WriteString(p.Locale)
+	r.WriteUnsignedByte(p.ViewDistance)
+	r.WriteVarint(p.ChatMode)
+	r.WriteBoolean(p.ChatColors)
+	r.WriteUnsignedByte(p.DisplayedSkinParts)
+	r.WriteVarint(p.MainHand)
+}
+
+func (p *ClientSettingsPacket) Decode(r *RawPacket) (err error) {
+	if p.Locale, err = r.ReadStringMax(16); err != nil {
+		return
+	}
+	if p.ViewDistance, err = r.ReadByte(); err != nil {
+		return
+	}
+	if p.ChatMode, err = r.ReadVarint(); err != nil {
+		return
+	}
+	if p.ChatColors, err = r.ReadBoolean(); err != nil {
+		return
+	}
+	if p.DisplayedSkinParts, err = r.ReadUnsignedByte(); err != nil {
+		return
+	}
+	p.MainHand, err = r.ReadVarint()
+	return
+}
+
+func (p *PluginMessagePacket) Encode(r *Response) {
+	r.WriteString(p.Channel)
+	r.WriteRaw(p.Data)
+}
+
+func (p *PluginMessagePacket) Decode(r *RawPacket) (err error) {
+	if p.Channel, err = r.ReadStringMax(20); err != nil {
+		return
+	}
+	p.Data = r.ReadRemaining()
+	return
+}
+
+func (p *IncomingPositionAndLookPacket) Encode(r *Response) {
+	r.WriteDouble(p.X)
+	r.WriteDouble(p.Y)
+	r.WriteDouble(p.Z)
+	r.WriteFloat(p.Yaw)
+	r.WriteFloat(p.Pitch)
+	r.WriteBoolean(p.OnGround)
+}
+
+func (p *IncomingPositionAndLookPacket) Decode(r *RawPacket) (err error) {
+	if p.X, err = r.ReadDouble(); err != nil {
+		return
+	}
+	if p.Y, err = r.ReadDouble(); err != nil {
+		return
+	}
+	if p.Z, err = r.ReadDouble(); err != nil {
+		return
+	}
+	if p.Yaw, err = r.ReadFloat(); err != nil {
+		return
+	}
+	if p.Pitch, err = r.ReadFloat(); err != nil {
+		return
+	}
+	p.OnGround, err = r.ReadBoolean()
+	return
+}
+
+func (p *IncomingPositionPacket) Encode(r *Response) {
+	r.WriteDouble(p.X)
+	r.WriteDouble(p.Y)
+	r.WriteDouble(p.Z)
+	r.WriteBoolean(p.OnGround)
+}
+
+func (p *IncomingPositionPacket) Decode(r *RawPacket) (err error) {
+	if p.X, err = r.ReadDouble(); err != nil {
+		return
+	}
+	if p.Y, err = r.ReadDouble(); err != nil {
+		return
+	}
+	if p.Z, err = r.ReadDouble(); err != nil {
+		return
+	}
+	p.OnGround, err = r.ReadBoolean()
+	return
+}
+
+func (p *IncomingLookPacket) Encode(r *Response) {
+	r.WriteFloat(p.Yaw)
+	r.WriteFloat(p.Pitch)
+	r.WriteBoolean(p.OnGround)
+}
+
+func (p *IncomingLookPacket) Decode(r *RawPacket) (err error) {
+	if p.Yaw, err = r.ReadFloat(); err != nil {
+		return
+	}
+	if p.Pitch, err = r.ReadFloat(); err != nil {
+		return
+	}
+	p.OnGround, err = r.ReadBoolean()
+	return
+}
+
+func (p *AnimationPacket) Encode(r *Response) {
+	r.WriteVarint(p.Hand)
+}
+
+func (p *AnimationPacket) Decode(r *RawPacket) (err error) {
+	p.Hand, err = r.ReadVarint()
+	return
+}
+
+func (p *ClickWindowPacket) Encode(r *Response) {
+	r.WriteUnsignedByte(p.WindowID)
+	r.WriteUnsignedShort(uint16(p.Slot))
+	r.WriteByte(p.Button)
+	r.WriteUnsignedShort(uint16(p.ActionNumber))
+	r.WriteVarint(p.Mode)
+	r.WriteRaw(p.ClickedItem)
+}
+
+func (p *ClickWindowPacket) Decode(r *RawPacket) (err error) {
+	if p.WindowID, err = r.ReadUnsignedByte(); err != nil {
+		return
+	}
+	slot, err := r.ReadUnsignedShort()
+	if err != nil {
+		return
+	}
+	p.Slot = int16(slot)
+	button, err := r.ReadByte()
+	if err != nil {
+		return
+	}
+	p.Button = int8(button)
+	action, err := r.ReadUnsignedShort()
+	if err != nil {
+		return
+	}
+	p.ActionNumber = int16(action)
+	if p.Mode, err = r.ReadVarint(); err != nil {
+		return
+	}
+	p.ClickedItem = r.ReadRemaining()
+	return
+}
+
+func (p *CloseWindowPacket) Encode(r *Response) {
+	r.WriteUnsignedByte(p.WindowID)
+}
+
+func (p *CloseWindowPacket) Decode(r *RawPacket) (err error) {
+	p.WindowID, err = r.ReadUnsignedByte()
+	return
+}
@@ -0,0 +1,314 @@
+// This file contains the packets which change the blocks of the world,
+// and the ones describing what the players hold.
+
+package protocol
+
+import (
+	"bytes"
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/nbt"
+	"github.com/olsdavis/goelan/player"
+	"github.com/olsdavis/goelan/world"
+	"io"
+)
+
+const (
+	// the statuses of the Player Digging packet
+	DiggingStarted   = 0
+	DiggingCancelled = 1
+	DiggingFinished  = 2
+
+	// the faces of the blocks, as sent by the clients
+	FaceBottom = 0 // -Y
+	FaceTop    = 1 // +Y
+	FaceNorth  = 2 // -Z
+	FaceSouth  = 3 // +Z
+	FaceWest   = 4 // -X
+	FaceEast   = 5 // +X
+
+	// the ID of the empty slots
+	emptySlotID = -1
+	// the ID of the window of the players' inventory
+	InventoryWindowID = 0
+)
+
+type (
+	// BlockChangePacket changes a block of a loaded chunk.
+	BlockChangePacket struct {
+		X, Y, Z int32
+		State   world.BlockState
+	}
+
+	// MultiBlockChangePacket changes several blocks of a loaded chunk.
+	MultiBlockChangePacket struct {
+		ChunkX, ChunkZ int32
+		Records        []BlockChangeRecord
+	}
+
+	// BlockChangeRecord is a block changed by the MultiBlockChangePacket.
+	BlockChangeRecord struct {
+		X, Y, Z uint8 // relative to the chunk
+		State   world.BlockState
+	}
+
+	// SetSlotPacket sets a slot of a window opened by the client.
+	SetSlotPacket struct {
+		WindowID int8
+		Slot     int16
+		Item     *player.ItemStack // nil if the slot is empty
+	}
+
+	/* Serverbound */
+
+	PlayerDiggingPacket struct {
+		Status  int32
+		X, Y, Z int32
+		Face    int8
+	}
+
+	HeldItemChangePacket struct {
+		Slot int16
+	}
+
+	// CreativeInventoryActionPacket sets a slot of the inventory of a
+	// player in creative mode (-1 to drop the item).
+	CreativeInventoryActionPacket struct {
+		Slot int16
+		Item *player.ItemStack // nil if the slot is emptied
+	}
+
+	PlayerBlockPlacementPacket struct {
+		X, Y, Z                   int32 // the clicked block
+		Face                      int32
+		Hand                      int32
+		CursorX, CursorY, CursorZ float32 // the position of the cursor on the face
+	}
+)
+
+func init() {
+	RegisterPacket(PlayState, Clientbound, BlockChangePacketId, func() Packet { return &BlockChangePacket{} })
+	RegisterPacket(PlayState, Clientbound, MultiBlockChangePacketId, func() Packet { return &MultiBlockChangePacket{} })
+	RegisterPacket(PlayState, Clientbound, SetSlotPacketId, func() Packet { return &SetSlotPacket{} })
+	RegisterPacket(PlayState, Serverbound, PlayerDiggingPacketId, func() Packet { return &PlayerDiggingPacket{} })
+	RegisterPacket(PlayState, Serverbound, HeldItemChangePacketId, func() Packet { return &HeldItemChangePacket{} })
+	RegisterPacket(PlayState, Serverbound, CreativeInventoryActionPacketId, func() Packet { return &CreativeInventoryActionPacket{} })
+	RegisterPacket(PlayState, Serverbound, PlayerBlockPlacementPacketId, func() Packet { return &PlayerBlockPlacementPacket{} })
+}
+
+// FaceOffset returns the offset of the block next to the given face.
+func FaceOffset(face int32) (x, y, z int32, ok bool) {
+	switch face {
+	case FaceBottom:
+		return 0, -1, 0, true
+	case FaceTop:
+		return 0, 1, 0, true
+	case FaceNorth:
+		return 0, 0, -1, true
+	case FaceSouth:
+		return 0, 0, 1, true
+	case FaceWest:
+		return -1, 0, 0, true
+	case FaceEast:
+		return 1, 0, 0, true
+	}
+	return 0, 0, 0, false
+}
+
+func (p *BlockChangePacket) Encode(r *Response) {
+	r.WritePosition(p.X, p.Y, p.Z)
+	r.WriteVarint(int32(p.State))
+}
+
+func (p *BlockChangePacket) Decode(r *RawPacket) (err error) {
+	if p.X, p.Y, p.Z, err = r.ReadPosition(); err != nil {
+		return
+	}
+	state, err := r.ReadVarint()
+	p.State = world.BlockState(state)
+	return
+}
+
+func (p *MultiBlockChangePacket) Encode(r *Response) {
+	r.WriteInt(int(p.ChunkX))
+	r.WriteInt(int(p.ChunkZ))
+	r.WriteVarint(int32(len(p.Records)))
+	for _, record := range p.Records {
+		r.WriteUnsignedByte(record.X<<4 | record.Z&0x0F)
+		r.WriteUnsignedByte(record.Y)
+		r.WriteVarint(int32(record.State))
+	}
+}
+
+func (p *MultiBlockChangePacket) Decode(r *RawPacket) (err error) {
+	if p.ChunkX, err = r.ReadInt(); err != nil {
+		return
+	}
+	if p.ChunkZ, err = r.ReadInt(); err != nil {
+		return
+	}
+	count, err := r.ReadVarint()
+	if err != nil {
+		return
+	}
+	// a chunk has 65536 blocks
+	if count < 0 || count > 1<<16 {
+		return NewProtocolError("invalid record count %v", count)
+	}
+	p.Records = make([]BlockChangeRecord, count)
+	for i := range p.Records {
+		var xz, y byte
+		var state int32
+		if xz, err = r.ReadUnsignedByte(); err != nil {
+			return
+		}
+		if y, err = r.ReadUnsignedByte(); err != nil {
+			return
+		}
+		if state, err = r.ReadVarint(); err != nil {
+			return
+		}
+		p.Records[i] = BlockChangeRecord{xz >> 4, y, xz & 0x0F, world.BlockState(state)}
+	}
+	return
+}
+
+func (p *SetSlotPacket) Encode(r *Response) {
+	r.WriteByte(p.WindowID)
+	r.WriteUnsignedShort(uint16(p.Slot))
+	writeSlot(r, p.Item)
+}
+
+func (p *SetSlotPacket) Decode(r *RawPacket) error {
+	window, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	p.WindowID = int8(window)
+	slot, err := r.ReadUnsignedShort()
+	if err != nil {
+		return err
+	}
+	p.Slot = int16(slot)
+	p.Item, err = readSlot(r)
+	return err
+}
+
+func (p *PlayerDiggingPacket) Encode(r *Response) {
+	r.WriteVarint(p.Status)
+	r.WritePosition(p.X, p.Y, p.Z)
+	r.WriteByte(p.Face)
+}
+
+func (p *PlayerDiggingPacket) Decode(r *RawPacket) (err error) {
+	if p.Status, err = r.ReadVarint(); err != nil {
+		return
+	}
+	if p.X, p.Y, p.Z, err = r.ReadPosition(); err != nil {
+		return
+	}
+	face, err := r.ReadByte()
+	p.Face = int8(face)
+	return
+}
+
+func (p *HeldItemChangePacket) Encode(r *Response) {
+	r.WriteUnsignedShort(uint16(p.Slot))
+}
+
+func (p *HeldItemChangePacket) Decode(r *RawPacket) error {
+	slot, err := r.ReadUnsignedShort()
+	p.Slot = int16(slot)
+	return err
+}
+
+func (p *CreativeInventoryActionPacket) Encode(r *Response) {
+	r.WriteUnsignedShort(uint16(p.Slot))
+	writeSlot(r, p.Item)
+}
+
+func (p *CreativeInventoryActionPacket) Decode(r *RawPacket) error {
+	slot, err := r.ReadUnsignedShort()
+	if err != nil {
+		return err
+	}
+	p.Slot = int16(slot)
+	p.Item, err = readSlot(r)
+	return err
+}
+
+func (p *PlayerBlockPlacementPacket) Encode(r *Response) {
+	r.WritePosition(p.X, p.Y, p.Z)
+	r.WriteVarint(p.Face)
+	r.WriteVarint(p.Hand)
+	r.WriteFloat(p.CursorX)
+	r.WriteFloat(p.CursorY)
+	r.WriteFloat(p.CursorZ)
+}
+
+func (p *PlayerBlockPlacementPacket) Decode(r *RawPacket) (err error) {
+	if p.X, p.Y, p.Z, err = r.ReadPosition(); err != nil {
+		return
+	}
+	if p.Face, err = r.ReadVarint(); err != nil {
+		return
+	}
+	if p.Hand, err = r.ReadVarint(); err != nil {
+		return
+	}
+	if p.CursorX, err = r.ReadFloat(); err != nil {
+		return
+	}
+	if p.CursorY, err = r.ReadFloat(); err != nil {
+		return
+	}
+	p.CursorZ, err = r.ReadFloat()
+	return
+}
+
+// writeSlot writes the given stack (nil if the slot is empty).
+func writeSlot(r *Response, stack *player.ItemStack) {
+	if stack == nil {
+		r.WriteUnsignedShort(uint16(emptySlotID & 0xFFFF))
+		return
+	}
+	r.WriteUnsignedShort(uint16(stack.Material.ID))
+	r.WriteUnsignedByte(stack.Count)
+	r.WriteUnsignedShort(uint16(stack.Damage))
+	if stack.Tag == nil {
+		r.WriteUnsignedByte(byte(nbt.TagEnd))
+		return
+	}
+	buf := new(bytes.Buffer)
+	nbt.Write(buf, "", stack.Tag)
+	r.WriteRaw(buf.Bytes())
+}
+
+// readSlot reads a stack written by writeSlot.
+func readSlot(r *RawPacket) (*player.ItemStack, error) {
+	id, err := r.ReadUnsignedShort()
+	if err != nil || int16(id) == emptySlotID {
+		return nil, err
+	}
+	if !material.Exists(int(id)) {
+		return nil, NewProtocolError("unknown item %v", id)
+	}
+	stack := &player.ItemStack{Material: material.GetById(int(id))}
+	if stack.Count, err = r.ReadUnsignedByte(); err != nil {
+		return nil, err
+	}
+	damage, err := r.ReadUnsignedShort()
+	if err != nil {
+		return nil, err
+	}
+	stack.Damage = int16(damage)
+	t, err := r.ReadByte()
+	if err != nil || nbt.TagType(t) == nbt.TagEnd {
+		return stack, err
+	}
+	// puts the type of the tag back
+	reader := io.MultiReader(bytes.NewReader([]byte{t}), r.Data)
+	if _, stack.Tag, err = nbt.Read(reader); err != nil {
+		return nil, NewProtocolError("invalid item tag: %v", err)
+	}
+	return stack, nil
+}
@@ -0,0 +1,99 @@
+package protocol
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/nbt"
+	"github.com/olsdavis/goelan/player"
+	"github.com/olsdavis/goelan/world"
+	"reflect"
+	"testing"
+)
+
+// serverboundRoundTrip is roundTrip, for the packets sent by the clients.
+func serverboundRoundTrip(t *testing.T, packet Packet) {
+	raw, err := Marshal(LatestProtocolVersion, PlayState, Serverbound, packet)
+	if err != nil {
+		t.Fatal("Could not marshal packet:", err)
+	}
+	decoded, err := Unmarshal(LatestProtocolVersion, PlayState, Serverbound, raw)
+	if err != nil {
+		t.Fatalf("Could not unmarshal %T: %v", packet, err)
+	}
+	if !reflect.DeepEqual(packet, decoded) {
+		t.Errorf("Round trip of %T failed.\nExpected: %+v\nGot: %+v", packet, packet, decoded)
+	}
+	if remaining := len(raw.Data.Buf) - raw.Data.read; remaining != 0 {
+		t.Errorf("%T has %v unread bytes.", packet, remaining)
+	}
+}
+
+func TestBlockChangeRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &BlockChangePacket{
+		X: -1000, Y: 64, Z: 33554431,
+		State: world.NewBlockState(material.Wool, 14),
+	})
+	roundTrip(t, PlayState, &MultiBlockChangePacket{
+		ChunkX: -3,
+		ChunkZ: 7,
+		Records: []BlockChangeRecord{
+			{X: 0, Y: 0, Z: 15, State: world.Air},
+			{X: 15, Y: 255, Z: 3, State: world.NewBlockState(material.Stone, 0)},
+		},
+	})
+}
+
+func TestPlayerDiggingRoundTrip(t *testing.T) {
+	serverboundRoundTrip(t, &PlayerDiggingPacket{
+		Status: DiggingFinished,
+		X:      12, Y: 3, Z: -40,
+		Face: FaceEast,
+	})
+}
+
+func TestBlockPlacementRoundTrip(t *testing.T) {
+	serverboundRoundTrip(t, &PlayerBlockPlacementPacket{
+		X: -7, Y: 70, Z: 2,
+		Face:    FaceTop,
+		Hand:    1,
+		CursorX: 0.5, CursorY: 1, CursorZ: 0.25,
+	})
+	serverboundRoundTrip(t, &HeldItemChangePacket{Slot: 8})
+}
+
+func TestSetSlotRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &SetSlotPacket{WindowID: InventoryWindowID, Slot: 45})
+	roundTrip(t, PlayState, &SetSlotPacket{
+		WindowID: InventoryWindowID,
+		Slot:     36,
+		Item:     &player.ItemStack{Material: material.Stone, Count: 63},
+	})
+}
+
+func TestCreativeInventoryActionRoundTrip(t *testing.T) {
+	serverboundRoundTrip(t, &CreativeInventoryActionPacket{Slot: -1})
+	serverboundRoundTrip(t, &CreativeInventoryActionPacket{
+		Slot: 36,
+		Item: &player.ItemStack{Material: material.Stone, Count: 64, Damage: 3},
+	})
+	serverboundRoundTrip(t, &CreativeInventoryActionPacket{
+		Slot: 40,
+		Item: &player.ItemStack{
+			Material: material.DiamondPickaxe,
+			Count:    1,
+			Damage:   12,
+			Tag: nbt.Compound{
+				"ench": nbt.NewList(nbt.TagCompound, nbt.Compound{"id": nbt.Short(32), "lvl": nbt.Short(5)}),
+			},
+		},
+	})
+}
+
+func TestFaceOffset(t *testing.T) {
+	x, y, z, ok := FaceOffset(FaceNorth)
+	if !ok || x != 0 || y != 0 || z != -1 {
+		t.Error("Wrong offset of the north face:", x, y, z)
+	}
+	if _, _, _, ok := FaceOffset(6); ok {
+		t.Error("The face 6 should be invalid")
+	}
+}
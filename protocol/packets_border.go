@@ -0,0 +1,112 @@
+// This file contains the World Border packet, which sets the border
+// of the world rendered by the clients.
+
+package protocol
+
+const (
+	// the actions of the World Border packet
+	WorldBorderSetSize          = 0
+	WorldBorderLerpSize         = 1
+	WorldBorderSetCenter        = 2
+	WorldBorderInitialize       = 3
+	WorldBorderSetWarningTime   = 4
+	WorldBorderSetWarningBlocks = 5
+)
+
+// WorldBorderPacket changes the border of the world; only the fields
+// of its action are sent.
+type WorldBorderPacket struct {
+	Action                 int32
+	X, Z                   float64 // the center
+	OldDiameter            float64 // the diameter, when it is set at once
+	NewDiameter            float64
+	Speed                  int64 // the time of the resizing, in milliseconds
+	PortalTeleportBoundary int32
+	WarningTime            int32 // in seconds
+	WarningBlocks          int32
+}
+
+func init() {
+	RegisterPacket(PlayState, Clientbound, WorldBorderPacketId, func() Packet { return &WorldBorderPacket{} })
+}
+
+func (p *WorldBorderPacket) Encode(r *Response) {
+	r.WriteVarint(p.Action)
+	switch p.Action {
+	case WorldBorderSetSize:
+		r.WriteDouble(p.OldDiameter)
+	case WorldBorderLerpSize:
+		r.WriteDouble(p.OldDiameter)
+		r.WriteDouble(p.NewDiameter)
+		r.WriteVarlong(p.Speed)
+	case WorldBorderSetCenter:
+		r.WriteDouble(p.X)
+		r.WriteDouble(p.Z)
+	case WorldBorderInitialize:
+		r.WriteDouble(p.X)
+		r.WriteDouble(p.Z)
+		r.WriteDouble(p.OldDiameter)
+		r.WriteDouble(p.NewDiameter)
+		r.WriteVarlong(p.Speed)
+		r.WriteVarint(p.PortalTeleportBoundary)
+		r.WriteVarint(p.WarningTime)
+		r.WriteVarint(p.WarningBlocks)
+	case WorldBorderSetWarningTime:
+		r.WriteVarint(p.WarningTime)
+	case WorldBorderSetWarningBlocks:
+		r.WriteVarint(p.WarningBlocks)
+	}
+}
+
+func (p *WorldBorderPacket) Decode(r *RawPacket) (err error) {
+	if p.Action, err = r.ReadVarint(); err != nil {
+		return
+	}
+	switch p.Action {
+	case WorldBorderSetSize:
+		p.OldDiameter, err = r.ReadDouble()
+	case WorldBorderLerpSize:
+		if p.OldDiameter, err = r.ReadDouble(); err != nil {
+			return
+		}
+		if p.NewDiameter, err = r.ReadDouble(); err != nil {
+			return
+		}
+		p.Speed, err = r.ReadVarlong()
+	case WorldBorderSetCenter:
+		if p.X, err = r.ReadDouble(); err != nil {
+			return
+		}
+		p.Z, err = r.ReadDouble()
+	case WorldBorderInitialize:
+		if p.X, err = r.ReadDouble(); err != nil {
+			return
+		}
+		if p.Z, err = r.ReadDouble(); err != nil {
+			return
+		}
+		if p.OldDiameter, err = r.ReadDouble(); err != nil {
+			return
+		}
+		if p.NewDiameter, err = r.ReadDouble(); err != nil {
+			return
+		}
+		if p.Speed, err = r.ReadVarlong(); err != nil {
+			return
+		}
+		if p.PortalTeleportBoundary, err = r.ReadVarint(); err != nil {
+			return
+		}
+		if p.WarningTime, err = r.ReadVarint(); err != nil {
+			return
+		}
+		p.WarningBlocks, err = r.ReadVarint()
+	case WorldBorderSetWarningTime:
+		p.WarningTime, err = r.ReadVarint()
+	case WorldBorderSetWarningBlocks:
+		p.WarningBlocks, err = r.ReadVarint()
+	default:
+		err = NewProtocolError("unknown world border action %v", p.Action)
+	}
+	return
+}
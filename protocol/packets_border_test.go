@@ -0,0 +1,33 @@
+package protocol
+
+import "testing"
+
+func TestWorldBorderRoundTrip(t *testing.T) {
+	packets := []*WorldBorderPacket{
+		{Action: WorldBorderSetSize, OldDiameter: 1000},
+		{Action: WorldBorderLerpSize, OldDiameter: 1000, NewDiameter: 200, Speed: 60000},
+		{Action: WorldBorderSetCenter, X: -128.5, Z: 256},
+		{
+			Action:                 WorldBorderInitialize,
+			X:                      10,
+			Z:                      -10,
+			OldDiameter:            500,
+			NewDiameter:            600,
+			Speed:                  1 << 40,
+			PortalTeleportBoundary: 29999984,
+			WarningTime:            15,
+			WarningBlocks:          5,
+		},
+		{Action: WorldBorderSetWarningTime, WarningTime: 30},
+		{Action: WorldBorderSetWarningBlocks, WarningBlocks: 10},
+	}
+	for _, packet := range packets {
+		roundTrip(t, PlayState, packet)
+	}
+}
+
+func TestWorldBorderUnknownAction(t *testing.T) {
+	if err := (&WorldBorderPacket{}).Decode(NewRawPacket(WorldBorderPacketId, Varint(6), nil)); err == nil {
+		t.Error("The unknown actions should be rejected")
+	}
+}
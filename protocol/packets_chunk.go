@@ -0,0 +1,277 @@
+// This file contains the packets which send the chunks.
+
+package protocol
+
+import (
+	"github.com/olsdavis/goelan/world"
+	"github.com/olsdavis/goelan/world/val"
+	"io"
+)
+
+const (
+	// the minimal number of bits per block of a section
+	MinBitsPerBlock = 4
+	// sections using more bits per block have no palette,
+	// and use the global palette (id << 4 | metadata)
+	MaxPaletteBitsPerBlock = 8
+	GlobalBitsPerBlock     = 13
+)
+
+type (
+	// ChunkDataPacket sends a chunk column, or some sections of it.
+	ChunkDataPacket struct {
+		Chunk     *world.Chunk
+		FullChunk bool // if true, the whole column is sent (with the biomes)
+		SkyLight  bool // if true, the sky light is sent (overworld only)
+	}
+
+	// UnloadChunkPacket tells the client to forget a chunk column.
+	UnloadChunkPacket struct {
+		X, Z int32
+	}
+)
+
+func init() {
+	// the decoded chunks are expected to come from the overworld
+	RegisterPacket(PlayState, Clientbound, ChunkDataPacketId, func() Packet { return &ChunkDataPacket{SkyLight: true} })
+	RegisterPacket(PlayState, Clientbound, UnloadChunkPacketId, func() Packet { return &UnloadChunkPacket{} })
+}
+
+func (p *ChunkDataPacket) Encode(r *Response) {
+	// the chunk may be modified by the other routines meanwhile
+	defer p.Chunk.RUnlock()
+	p.Chunk.RLock()
+	data := NewVersionedResponse(r.ProtocolVersion())
+	var mask uint32
+	for i, section := range p.Chunk.Sections {
+		if section == nil || section.IsEmpty() {
+			continue
+		}
+		mask |= 1 << uint(i)
+		writeSection(data, section, p.SkyLight)
+	}
+	if p.FullChunk {
+		data.WriteRaw(p.Chunk.Biomes[:])
+	}
+
+	r.WriteInt(int(p.Chunk.X))
+	r.WriteInt(int(p.Chunk.Z))
+	r.WriteBoolean(p.FullChunk)
+	r.WriteUVarint(mask)
+	r.WriteByteArray(data.data.Bytes())
+	// no block entities
+	r.WriteUVarint(0)
+}
+
+func (p *ChunkDataPacket) Decode(r *RawPacket) (err error) {
+	var x, z int32
+	if x, err = r.ReadInt(); err != nil {
+		return
+	}
+	if z, err = r.ReadInt(); err != nil {
+		return
+	}
+	p.Chunk = world.NewChunk(x, z)
+	if p.FullChunk, err = r.ReadBoolean(); err != nil {
+		return
+	}
+	var mask uint32
+	if mask, err = r.ReadUnsignedVarint(); err != nil {
+		return
+	}
+	var data []byte
+	if data, err = r.ReadByteArrayMax(MaxUncompressedLength); err != nil {
+		return
+	}
+	sections := NewRawPacket(0, data, nil)
+	defer sections.Release()
+	for i := range p.Chunk.Sections {
+		if mask&(1<<uint(i)) == 0 {
+			continue
+		}
+		if p.Chunk.Sections[i], err = readSection(sections, p.SkyLight); err != nil {
+			return
+		}
+	}
+	if p.FullChunk {
+		if _, err = io.ReadFull(sections.Data, p.Chunk.Biomes[:]); err != nil {
+			return
+		}
+	}
+	var blockEntities uint32
+	if blockEntities, err = r.ReadUnsignedVarint(); err == nil && blockEntities > 0 {
+		// the block entities are ignored
+		r.ReadRemaining()
+	}
+	return
+}
+
+func (p *UnloadChunkPacket) Encode(r *Response) {
+	r.WriteInt(int(p.X))
+	r.WriteInt(int(p.Z))
+}
+
+func (p *UnloadChunkPacket) Decode(r *RawPacket) (err error) {
+	if p.X, err = r.ReadInt(); err != nil {
+		return
+	}
+	p.Z, err = r.ReadInt()
+	return
+}
+
+// writeSection writes the given section: its blocks, indexed in a palette
+// if they are not too diverse, and then its light.
+func writeSection(r *Response, section *world.Section, skyLight bool) {
+	palette, indexes := sectionPalette(section)
+	bits := paletteBits(len(palette))
+	values := make([]uint64, val.SectionVolume)
+	if bits == GlobalBitsPerBlock {
+		for i := range values {
+			values[i] = uint64(section.GetBlockStateAt(i))
+		}
+		// the palette is not sent
+		palette = nil
+	} else {
+		for i := range values {
+			values[i] = uint64(indexes[section.GetBlockStateAt(i)])
+		}
+	}
+
+	r.WriteUnsignedByte(uint8(bits))
+	r.WriteUVarint(uint32(len(palette)))
+	for _, state := range palette {
+		r.WriteUVarint(uint32(state))
+	}
+	longs := packBits(values, bits)
+	r.WriteUVarint(uint32(len(longs)))
+	for _, l := range longs {
+		r.WriteLong(int64(l))
+	}
+	r.WriteRaw(section.BlockLight[:])
+	if skyLight {
+		r.WriteRaw(section.SkyLight[:])
+	}
+}
+
+// readSection reads a section written by writeSection.
+func readSection(r *RawPacket, skyLight bool) (*world.Section, error) {
+	bits, err := r.ReadUnsignedByte()
+	if err != nil {
+		return nil, err
+	}
+	if bits == 0 || bits > 64 {
+		return nil, NewProtocolError("invalid bits per block %v", bits)
+	}
+	paletteLength, err := r.ReadUnsignedVarint()
+	if err != nil {
+		return nil, err
+	}
+	if paletteLength > 1<<MaxPaletteBitsPerBlock {
+		return nil, NewProtocolError("palette is too long (%v)", paletteLength)
+	}
+	palette := make([]world.BlockState, paletteLength)
+	for i := range palette {
+		state, err := r.ReadUnsignedVarint()
+		if err != nil {
+			return nil, err
+		}
+		palette[i] = world.BlockState(state)
+	}
+	length, err := r.ReadUnsignedVarint()
+	if err != nil {
+		return nil, err
+	}
+	if int(length) != val.SectionVolume*int(bits)/64 {
+		return nil, NewProtocolError("invalid data array length %v", length)
+	}
+	longs := make([]uint64, length)
+	for i := range longs {
+		l, err := r.ReadLong()
+		if err != nil {
+			return nil, err
+		}
+		longs[i] = uint64(l)
+	}
+
+	section := world.NewSection()
+	for i, value := range unpackBits(longs, int(bits), val.SectionVolume) {
+		state := world.BlockState(value)
+		if len(palette) > 0 {
+			if value >= uint64(len(palette)) {
+				return nil, NewProtocolError("invalid palette index %v", value)
+			}
+			state = palette[value]
+		}
+		section.SetBlockState(i&0x0F, i>>8, (i>>4)&0x0F, state)
+	}
+	if _, err = io.ReadFull(r.Data, section.BlockLight[:]); err != nil {
+		return nil, err
+	}
+	if skyLight {
+		if _, err = io.ReadFull(r.Data, section.SkyLight[:]); err != nil {
+			return nil, err
+		}
+	}
+	return section, nil
+}
+
+// sectionPalette returns the different states of the section's blocks, in the order
+// of their first appearance, and the index of each state in the palette.
+func sectionPalette(section *world.Section) ([]world.BlockState, map[world.BlockState]int) {
+	palette := make([]world.BlockState, 0)
+	indexes := make(map[world.BlockState]int)
+	for i := 0; i < val.SectionVolume; i++ {
+		state := section.GetBlockStateAt(i)
+		if _, ok := indexes[state]; !ok {
+			indexes[state] = len(palette)
+			palette = append(palette, state)
+		}
+	}
+	return palette, indexes
+}
+
+// paletteBits returns the number of bits per block required for a palette
+// of the given length.
+func paletteBits(length int) int {
+	bits := MinBitsPerBlock
+	for 1<<uint(bits) < length {
+		bits++
+	}
+	if bits > MaxPaletteBitsPerBlock {
+		return GlobalBitsPerBlock
+	}
+	return bits
+}
+
+// packBits packs the given values in longs, using the given number of bits
+// per value. A value may overlap two longs.
+func packBits(values []uint64, bits int) []uint64 {
+	longs := make([]uint64, (len(values)*bits+63)/64)
+	mask := uint64(1)<<uint(bits) - 1
+	for i, value := range values {
+		value &= mask
+		bitIndex := i * bits
+		index, offset := bitIndex/64, uint(bitIndex%64)
+		longs[index] |= value << offset
+		if int(offset)+bits > 64 {
+			longs[index+1] |= value >> (64 - offset)
+		}
+	}
+	return longs
+}
+
+// unpackBits unpacks count values packed by packBits.
+func unpackBits(longs []uint64, bits, count int) []uint64 {
+	values := make([]uint64, count)
+	mask := uint64(1)<<uint(bits) - 1
+	for i := range values {
+		bitIndex := i * bits
+		index, offset := bitIndex/64, uint(bitIndex%64)
+		value := longs[index] >> offset
+		if int(offset)+bits > 64 {
+			value |= longs[index+1] << (64 - offset)
+		}
+		values[i] = value & mask
+	}
+	return values
+}
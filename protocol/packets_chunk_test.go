@@ -0,0 +1,107 @@
+package protocol
+
+import (
+	"bytes"
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/world"
+	"reflect"
+	"testing"
+)
+
+func TestChunkDataGoldenBytes(t *testing.T) {
+	chunk := world.NewChunk(1, -2)
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 16; y++ {
+			for z := 0; z < 16; z++ {
+				chunk.SetBlock(x, y, z, material.Stone, 0)
+			}
+		}
+	}
+
+	expected := new(bytes.Buffer)
+	expected.Write([]byte{0x00, 0x00, 0x00, 0x01, 0xFF, 0xFF, 0xFF, 0xFE}) // x and z
+	expected.Write([]byte{0x01, 0x01})                                     // full chunk, first section only
+	expected.Write([]byte{0x85, 0x32})                                     // 6405 bytes of data
+	expected.Write([]byte{0x04, 0x01, 0x10})                               // 4 bits per block, palette: stone
+	expected.Write([]byte{0x80, 0x02})                                     // 256 longs
+	expected.Write(make([]byte, 256*8))                                    // every block is the first of the palette
+	expected.Write(make([]byte, 2048))                                     // no block light
+	expected.Write(bytes.Repeat([]byte{0xFF}, 2048))                       // full sky light
+	expected.Write(bytes.Repeat([]byte{world.PlainsBiome}, 256))
+	expected.Write([]byte{0x00}) // no block entities
+
+	raw, err := Marshal(LatestProtocolVersion, PlayState, Clientbound, &ChunkDataPacket{Chunk: chunk, FullChunk: true, SkyLight: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.ID != ChunkDataPacketId {
+		t.Errorf("Chunk Data ID should be %#x, currently: %#x", ChunkDataPacketId, raw.ID)
+	}
+	if !bytes.Equal(expected.Bytes(), raw.Data.Buf) {
+		t.Errorf("Chunk Data payload differs from the expected one.\nExpected: %x\nGot: %x", expected.Bytes(), raw.Data.Buf)
+	}
+}
+
+func TestPackBits(t *testing.T) {
+	if longs := packBits([]uint64{1, 2, 3}, 4); !reflect.DeepEqual(longs, []uint64{0x321}) {
+		t.Errorf("Packed values should be [0x321], currently: %#x", longs)
+	}
+	// the fifth value overlaps the two longs
+	values := []uint64{0, 0, 0, 0, 0x1FFF}
+	longs := packBits(values, GlobalBitsPerBlock)
+	if !reflect.DeepEqual(longs, []uint64{0xFFF0000000000000, 0x1}) {
+		t.Errorf("Packed values should be [0xfff0000000000000 0x1], currently: %#x", longs)
+	}
+	if unpacked := unpackBits(longs, GlobalBitsPerBlock, len(values)); !reflect.DeepEqual(unpacked, values) {
+		t.Errorf("Unpacked values should be %v, currently: %v", values, unpacked)
+	}
+}
+
+func TestPaletteBits(t *testing.T) {
+	for length, bits := range map[int]int{1: 4, 16: 4, 17: 5, 256: 8, 257: GlobalBitsPerBlock} {
+		if b := paletteBits(length); b != bits {
+			t.Errorf("A palette of %v states should use %v bits, currently: %v", length, bits, b)
+		}
+	}
+}
+
+func TestChunkDataRoundTrip(t *testing.T) {
+	chunk := world.NewChunk(-3, 7)
+	chunk.SetBlock(1, 0, 0, material.Stone, 0)
+	chunk.SetBlock(15, 255, 15, material.Dirt, 0)
+	chunk.Sections[0].BlockLight.Set(5, 14)
+	chunk.SetBiome(3, 4, 4)
+	// more than 256 states: uses the global palette
+	for i := 0; i < 300; i++ {
+		chunk.SetBlockState(i%16, 32+i/256, (i/16)%16, world.BlockState(i+1))
+	}
+	roundTrip(t, PlayState, &ChunkDataPacket{Chunk: chunk, FullChunk: true, SkyLight: true})
+}
+
+// TestChunkDataConcurrentChanges encodes a chunk while its blocks, and
+// thus its light and the one of its neighbours, change (run with -race).
+func TestChunkDataConcurrentChanges(t *testing.T) {
+	w := world.NewWorld("test")
+	for x := int32(-1); x <= 1; x++ {
+		w.SetChunk(world.NewChunk(x, 0))
+	}
+	chunk := w.GetLoadedChunk(0, 0)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := int32(0); i < 64; i++ {
+			w.SetBlockState(i%16, 64, 8, world.NewBlockState(material.Torch, 0))
+			w.SetBlockState(i%16, 65, 8, world.NewBlockState(material.Stone, 0))
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if _, err := Marshal(LatestProtocolVersion, PlayState, Clientbound, &ChunkDataPacket{Chunk: chunk, FullChunk: true, SkyLight: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
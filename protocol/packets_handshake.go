@@ -0,0 +1,74 @@
+// This file contains the packets of the handshake state
+// (including the server list ping).
+
+package protocol
+
+import "encoding/json"
+
+type (
+	HandshakePacket struct {
+		ProtocolVersion uint32
+		ServerAddress   string // not limited to MaxServerAddressLength, because proxies may forward data in it
+		ServerPort      uint16
+		NextState       uint32
+	}
+
+	StatusResponsePacket struct {
+		Status ServerListPing
+	}
+
+	// PingPacket is sent by the client, and sent back by the server
+	// with the same payload (pong).
+	PingPacket struct {
+		Payload int64
+	}
+)
+
+func init() {
+	RegisterPacket(HandshakeState, Serverbound, HandshakePacketId, func() Packet { return &HandshakePacket{} })
+	RegisterPacket(HandshakeState, Serverbound, PingPacketId, func() Packet { return &PingPacket{} })
+	RegisterPacket(HandshakeState, Clientbound, HandshakePacketId, func() Packet { return &StatusResponsePacket{} })
+	RegisterPacket(HandshakeState, Clientbound, PingPacketId, func() Packet { return &PingPacket{} })
+}
+
+func (p *HandshakePacket) Encode(r *Response) {
+	r.WriteUVarint(p.ProtocolVersion)
+	r.WriteString(p.ServerAddress)
+	r.WriteUnsignedShort(p.ServerPort)
+	r.WriteUVarint(p.NextState)
+}
+
+func (p *HandshakePacket) Decode(r *RawPacket) (err error) {
+	if p.ProtocolVersion, err = r.ReadUnsignedVarint(); err != nil {
+		return
+	}
+	if p.ServerAddress, err = r.ReadString(); err != nil {
+		return
+	}
+	if p.ServerPort, err = r.ReadUnsignedShort(); err != nil {
+		return
+	}
+	p.NextState, err = r.ReadUnsignedVarint()
+	return
+}
+
+func (p *StatusResponsePacket) Encode(r *Response) {
+	r.WriteJSON(p.Status)
+}
+
+func (p *StatusResponsePacket) Decode(r *RawPacket) error {
+	status, err := r.ReadByteArray()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(status, &p.Status)
+}
+
+func (p *PingPacket) Encode(r *Response) {
+	r.WriteLong(p.Payload)
+}
+
+func (p *PingPacket) Decode(r *RawPacket) (err error) {
+	p.Payload, err = r.ReadLong()
+	return
+}
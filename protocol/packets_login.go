@@ -0,0 +1,99 @@
+// This file contains the packets of the login state.
+
+package protocol
+
+type (
+	LoginStartPacket struct {
+		Username string
+	}
+
+	EncryptionRequestPacket struct {
+		ServerID    string
+		PublicKey   []byte
+		VerifyToken []byte
+	}
+
+	EncryptionResponsePacket struct {
+		SharedSecret []byte
+		VerifyToken  []byte
+	}
+
+	LoginSuccessPacket struct {
+		UUID     string // with hyphens
+		Username string
+	}
+
+	SetCompressionPacket struct {
+		Threshold int32
+	}
+)
+
+func init() {
+	RegisterPacket(LoginState, Serverbound, LoginStartPacketId, func() Packet { return &LoginStartPacket{} })
+	RegisterPacket(LoginState, Serverbound, EncryptionResponsePacketId, func() Packet { return &EncryptionResponsePacket{} })
+	RegisterPacket(LoginState, Clientbound, LoginStateDisconnectPacketId, func() Packet { return &DisconnectPacket{} })
+	RegisterPacket(LoginState, Clientbound, EncryptionRequestPacketId, func() Packet { return &EncryptionRequestPacket{} })
+	RegisterPacket(LoginState, Clientbound, LoginSuccessPacketId, func() Packet { return &LoginSuccessPacket{} })
+	RegisterPacket(LoginState, Clientbound, SetCompressionPacketId, func() Packet { return &SetCompressionPacket{} })
+}
+
+func (p *LoginStartPacket) Encode(r *Response) {
+	r.WriteString(p.Username)
+}
+
+func (p *LoginStartPacket) Decode(r *RawPacket) (err error) {
+	p.Username, err = r.ReadStringMax(16)
+	return
+}
+
+func (p *EncryptionRequestPacket) Encode(r *Response) {
+	r.WriteString(p.ServerID)
+	r.WriteByteArray(p.PublicKey)
+	r.WriteByteArray(p.VerifyToken)
+}
+
+func (p *EncryptionRequestPacket) Decode(r *RawPacket) (err error) {
+	if p.ServerID, err = r.ReadStringMax(20); err != nil {
+		return
+	}
+	if p.PublicKey, err = r.ReadByteArray(); err != nil {
+		return
+	}
+	p.VerifyToken, err = r.ReadByteArray()
+	return
+}
+
+func (p *EncryptionResponsePacket) Encode(r *Response) {
+	r.WriteByteArray(p.SharedSecret)
+	r.WriteByteArray(p.VerifyToken)
+}
+
+func (p *EncryptionResponsePacket) Decode(r *RawPacket) (err error) {
+	if p.SharedSecret, err = r.ReadByteArray(); err != nil {
+		return
+	}
+	p.VerifyToken, err = r.ReadByteArray()
+	return
+}
+
+func (p *LoginSuccessPacket) Encode(r *Response) {
+	r.WriteString(p.UUID)
+	r.WriteString(p.Username)
+}
+
+func (p *LoginSuccessPacket) Decode(r *RawPacket) (err error) {
+	if p.UUID, err = r.ReadStringMax(36); err != nil {
+		return
+	}
+	p.Username, err = r.ReadStringMax(16)
+	return
+}
+
+func (p *SetCompressionPacket) Encode(r *Response) {
+	r.WriteVarint(p.Threshold)
+}
+
+func (p *SetCompressionPacket) Decode(r *RawPacket) (err error) {
+	p.Threshold, err = r.ReadVarint()
+	return
+}
@@ -0,0 +1,193 @@
+package protocol
+
+import (
+	"github.com/olsdavis/goelan/player"
+	"github.com/olsdavis/goelan/util"
+	"github.com/olsdavis/goelan/world"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// roundTrip encodes the given packet, decodes it, and checks that
+// the decoded packet equals the original one.
+func roundTrip(t *testing.T, state ConnectionState, packet Packet) {
+	raw, err := Marshal(LatestProtocolVersion, state, Clientbound, packet)
+	if err != nil {
+		t.Fatal("Could not marshal packet:", err)
+	}
+	decoded, err := Unmarshal(LatestProtocolVersion, state, Clientbound, raw)
+	if err != nil {
+		t.Fatalf("Could not unmarshal %T: %v", packet, err)
+	}
+	if !reflect.DeepEqual(packet, decoded) {
+		t.Errorf("Round trip of %T failed.\nExpected: %+v\nGot: %+v", packet, packet, decoded)
+	}
+	if remaining := len(raw.Data.Buf) - raw.Data.read; remaining != 0 {
+		t.Errorf("%T has %v unread bytes.", packet, remaining)
+	}
+}
+
+func TestJoinGameRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &JoinGamePacket{
+		EntityID:         42,
+		GameMode:         1,
+		Dimension:        -1,
+		Difficulty:       2,
+		MaxPlayers:       20,
+		LevelType:        "flat",
+		ReducedDebugInfo: true,
+	})
+}
+
+func TestRespawnRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &RespawnPacket{
+		Dimension:  1,
+		Difficulty: 3,
+		GameMode:   2,
+		LevelType:  "default",
+	})
+}
+
+func TestPositionAndLookRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &PositionAndLookPacket{
+		Location: world.Location{
+			Orientation: world.Orientation{Yaw: 90, Pitch: -12.5},
+			Location3f:  world.Location3f{X: -128.5, Y: 80, Z: 3000.25},
+		},
+		Flags:      0x1F,
+		TeleportID: 300,
+	})
+}
+
+func TestPlayerAbilitiesRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &PlayerAbilitiesPacket{
+		Flags:       0x0D,
+		FlyingSpeed: 0.05,
+		FovModifier: 0.1,
+	})
+}
+
+func TestPlayerListItemRoundTrip(t *testing.T) {
+	uuid := util.UUID{MostSig: 0x069a79f444e94726, LeastSig: -0x5a41035 << 32}
+	roundTrip(t, PlayState, &PlayerListItemPacket{
+		Action: PlayerListItemActionAddPlayer,
+		Players: []PlayerListEntry{
+			{
+				UUID: uuid,
+				Name: "Notch",
+				Properties: []player.Property{
+					{Name: "textures", Value: "ewogICJ0aW1lc3RhbXAiIDog", Signature: "c2lnbmF0dXJl"},
+					{Name: "unsigned", Value: "value"},
+				},
+				GameMode:    int32(player.CreativeMode),
+				Ping:        120,
+				DisplayName: &ChatComponent{Text: "Notch"},
+			},
+			{
+				UUID: util.UUID{MostSig: 1, LeastSig: 2},
+				Name: "jeb_",
+			},
+		},
+	})
+	roundTrip(t, PlayState, &PlayerListItemPacket{
+		Action:  PlayerListItemActionUpdateLatency,
+		Players: []PlayerListEntry{{UUID: uuid, Ping: 250}},
+	})
+	roundTrip(t, PlayState, &PlayerListItemPacket{
+		Action:  PlayerListItemActionRemovePlayer,
+		Players: []PlayerListEntry{{UUID: uuid}},
+	})
+}
+
+func TestChatRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &ChatPacket{
+		Message:  ChatComponent{Text: "Hello, world!"},
+		Position: ActionBarMode,
+	})
+}
+
+func TestKeepAliveRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &KeepAlivePacket{ID: 0x7FFFFFFFFF})
+}
+
+func TestDisconnectRoundTrip(t *testing.T) {
+	roundTrip(t, LoginState, &DisconnectPacket{Reason: ChatComponent{Text: "You are banned."}})
+	roundTrip(t, PlayState, &DisconnectPacket{Reason: ChatComponent{Text: "Server closed."}})
+}
+
+func TestSpawnPositionRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &SpawnPositionPacket{X: -33554432, Y: 255, Z: 33554431})
+	roundTrip(t, PlayState, &SpawnPositionPacket{X: 18357644, Y: 831, Z: -20882616})
+}
+
+func TestTimeUpdateRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &TimeUpdatePacket{WorldAge: 1 << 40, TimeOfDay: -6000})
+	roundTrip(t, PlayState, &ChangeGameStatePacket{Reason: ChangeGameStateRainStrength, Value: 0.25})
+}
+
+func TestUpdateHealthRoundTrip(t *testing.T) {
+	roundTrip(t, PlayState, &UpdateHealthPacket{Health: 12.5, Food: 20, Saturation: 5})
+	roundTrip(t, PlayState, &CombatEventPacket{
+		Event:    CombatEventEntityDead,
+		PlayerID: 42,
+		KillerID: -1,
+		Message:  ChatComponent{Text: "Notch died."},
+	})
+}
+
+func TestPositionEncoding(t *testing.T) {
+	r := NewResponse()
+	r.WritePosition(18357644, 831, -20882616)
+	// x (26 bits), y (12 bits), then z (26 bits)
+	expected := []byte{0x46, 0x07, 0x63, 0x0C, 0xFE, 0xC1, 0x5B, 0x48}
+	if !reflect.DeepEqual(r.data.Bytes(), expected) {
+		t.Errorf("Expected %x, got %x", expected, r.data.Bytes())
+	}
+}
+
+func TestIncomingChatLength(t *testing.T) {
+	// the limit is in characters, not in bytes
+	message := strings.Repeat("é", maxChatLength)
+	raw, _ := Marshal(LatestProtocolVersion, PlayState, Serverbound, &IncomingChatPacket{Message: message})
+	if decoded, err := Unmarshal(LatestProtocolVersion, PlayState, Serverbound, raw); err != nil {
+		t.Error("A message of", maxChatLength, "characters should be accepted:", err)
+	} else if decoded.(*IncomingChatPacket).Message != message {
+		t.Error("Unexpected message", decoded.(*IncomingChatPacket).Message)
+	}
+
+	raw, _ = Marshal(LatestProtocolVersion, PlayState, Serverbound, &IncomingChatPacket{Message: message + "a"})
+	if _, err := Unmarshal(LatestProtocolVersion, PlayState, Serverbound, raw); err == nil {
+		t.Error("A message longer than", maxChatLength, "characters should be rejected")
+	}
+}
+
+func TestTruncatedPackets(t *testing.T) {
+	packets := []Packet{
+		&JoinGamePacket{LevelType: "default"},
+		&RespawnPacket{LevelType: "flat"},
+		&PositionAndLookPacket{},
+		&PlayerListItemPacket{Players: []PlayerListEntry{{Name: "Notch"}}},
+		&ChatPacket{Message: ChatComponent{Text: "Hello"}},
+		&KeepAlivePacket{},
+	}
+	for _, packet := range packets {
+		raw, _ := Marshal(LatestProtocolVersion, PlayState, Clientbound, packet)
+		data := raw.Data.Buf
+		for i := 0; i < len(data); i++ {
+			truncated := NewRawPacket(raw.ID, data[:i], nil)
+			if _, err := Unmarshal(LatestProtocolVersion, PlayState, Clientbound, truncated); err == nil {
+				t.Errorf("Decoding %T truncated to %v bytes should fail.", packet, i)
+			}
+		}
+	}
+}
+
+func TestDisconnectIDs(t *testing.T) {
+	if id, _ := PacketID(LoginState, Clientbound, &DisconnectPacket{}); id != LoginStateDisconnectPacketId {
+		t.Error("Login disconnect packet ID should be", LoginStateDisconnectPacketId, "currently", id)
+	}
+	if id, _ := PacketID(PlayState, Clientbound, &DisconnectPacket{}); id != KickPlayerPacketId {
+		t.Error("Play disconnect packet ID should be", KickPlayerPacketId, "currently", id)
+	}
+}
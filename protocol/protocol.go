@@ -3,16 +3,16 @@ package protocol
 import (
 	"encoding/binary"
 	"errors"
-	"github.com/olsdavis/goelan/log"
+	"fmt"
+	"io"
 	"sync"
 	"math"
-	"github.com/olsdavis/goelan/world"
-	"reflect"
 	"github.com/olsdavis/goelan/util"
 )
 
 const (
-	MaxByteArrayLength = 32767
+	MaxByteArrayLength     = 32767
+	MaxServerAddressLength = 255
 )
 
 var (
@@ -20,7 +20,8 @@ var (
 	emptyArray []byte
 	// ByteReader
 
-	ReadAllError = errors.New("reached the end of this reader's buffer")
+	ReadAllError      = errors.New("reached the end of this reader's buffer")
+	VarintTooBigError = NewProtocolError("varint is too big")
 	readerPool   = sync.Pool{New: func() interface{} {
 		return &ByteReader{
 			emptyArray,
@@ -31,12 +32,24 @@ var (
 
 type Callback func()
 
-type Packet struct {
-	ID uint64
+// ProtocolError is returned when a client sends data which
+// does not respect the protocol.
+type ProtocolError struct {
+	Reason string
+}
+
+// NewProtocolError creates a new ProtocolError with the given formatted reason.
+func NewProtocolError(format string, args ...interface{}) error {
+	return &ProtocolError{fmt.Sprintf(format, args...)}
+}
+
+func (err *ProtocolError) Error() string {
+	return "protocol error: " + err.Reason
 }
 
 type RawPacket struct {
-	Packet
+	ID       uint64
+	Version  uint32 // the protocol version in which the packet is encoded
 	Data     *ByteReader
 	Callback Callback // the callback is a function called when the packet is sent
 }
@@ -47,155 +60,155 @@ func NewRawPacket(id uint64, data []byte, callback Callback) *RawPacket {
 	b := readerPool.Get().(*ByteReader)
 	b.SetData(data)
 	return &RawPacket{
-		Packet{id},
+		id,
+		LatestProtocolVersion,
 		b,
 		callback,
 	}
 }
 
 // ReadByte reads a byte and returns it.
-func (r *RawPacket) ReadByte() byte {
-	b, err := r.Data.ReadByte()
-	if err != nil {
-		panic(err)
-	}
-	return b
+func (r *RawPacket) ReadByte() (byte, error) {
+	return r.Data.ReadByte()
 }
 
 // ReadUnsignedByte reads an unsigned byte and returns it.
-func (r *RawPacket) ReadUnsignedByte() byte {
-	var ubyte uint8
-	err := binary.Read(r.Data, ByteOrder, &ubyte)
-	if err != nil {
-		log.Error("Could not read unsigned short:", err)
-	}
-	return ubyte
+func (r *RawPacket) ReadUnsignedByte() (uint8, error) {
+	return r.Data.ReadByte()
 }
 
 // ReadBoolean reads a boolean and returns it.
-func (r *RawPacket) ReadBoolean() bool {
-	return r.ReadByte() == 1
+func (r *RawPacket) ReadBoolean() (bool, error) {
+	b, err := r.ReadByte()
+	return b == 1, err
 }
 
 // ReadUnsignedShort reads an unsigned short and returns it.
-func (r *RawPacket) ReadUnsignedShort() uint16 {
+func (r *RawPacket) ReadUnsignedShort() (uint16, error) {
 	var short uint16
 	err := binary.Read(r.Data, ByteOrder, &short)
-	if err != nil {
-		log.Error("Could not read unsigned short:", err)
-	}
-	return short
+	return short, err
 }
 
 // ReadVarint reads a Varint and returns it.
-func (r *RawPacket) ReadVarint() int32 {
-	i, err := binary.ReadVarint(r.Data)
-	if err != nil {
-		log.Error("Could not read varint:", err)
-	}
-	return int32(i)
+func (r *RawPacket) ReadVarint() (int32, error) {
+	// Varints are written as the two's complement of the integer
+	i, err := r.ReadUnsignedVarint()
+	return int32(i), err
 }
 
 // ReadUnsignedVarint reads an unsigned Varint and returns it.
-func (r *RawPacket) ReadUnsignedVarint() uint32 {
+func (r *RawPacket) ReadUnsignedVarint() (uint32, error) {
 	i, err := binary.ReadUvarint(r.Data)
 	if err != nil {
-		log.Error("Could not read unsigned varint:", err)
+		return 0, err
+	}
+	if i > math.MaxUint32 {
+		return 0, VarintTooBigError
 	}
-	return uint32(i)
+	return uint32(i), nil
+}
+
+// ReadVarlong reads a Varlong and returns it.
+func (r *RawPacket) ReadVarlong() (int64, error) {
+	// Varlongs are written as the two's complement of the long
+	l, err := binary.ReadUvarint(r.Data)
+	return int64(l), err
+}
+
+// ReadInt reads an int32 and returns it.
+func (r *RawPacket) ReadInt() (int32, error) {
+	var i int32
+	err := binary.Read(r.Data, ByteOrder, &i)
+	return i, err
 }
 
 // ReadFloat reads a float32 and returns it.
-func (r *RawPacket) ReadFloat() float32 {
-	buf := make([]byte, 4)
-	r.Data.Read(buf)
-	return math.Float32frombits(ByteOrder.Uint32(buf))
+func (r *RawPacket) ReadFloat() (float32, error) {
+	var f float32
+	err := binary.Read(r.Data, ByteOrder, &f)
+	return f, err
 }
 
 // ReadDouble reads a float64 and returns it.
-func (r *RawPacket) ReadDouble() float64 {
-	buf := make([]byte, 8)
-	r.Data.Read(buf)
-	return math.Float64frombits(ByteOrder.Uint64(buf))
+func (r *RawPacket) ReadDouble() (float64, error) {
+	var d float64
+	err := binary.Read(r.Data, ByteOrder, &d)
+	return d, err
 }
 
 // ReadLong reads an int64 and returns it.
-func (r *RawPacket) ReadLong() int64 {
+func (r *RawPacket) ReadLong() (int64, error) {
 	var long int64
 	err := binary.Read(r.Data, ByteOrder, &long)
-	if err != nil {
-		log.Error("Could not read long:", err)
+	return long, err
+}
+
+// ReadPosition reads block coordinates written by Response.WritePosition.
+func (r *RawPacket) ReadPosition() (x, y, z int32, err error) {
+	var long int64
+	if long, err = r.ReadLong(); err != nil {
+		return
 	}
-	return long
+	// the shifts extend the signs
+	x = int32(long >> 38)
+	y = int32(long << 26 >> 52)
+	z = int32(long << 38 >> 38)
+	return
 }
 
 // ReadByteArrayMax reads a byte array which's length
 // cannot exceed max.
-func (r *RawPacket) ReadByteArrayMax(max uint32) []byte {
-	size := r.ReadUnsignedVarint()
+func (r *RawPacket) ReadByteArrayMax(max uint32) ([]byte, error) {
+	size, err := r.ReadUnsignedVarint()
+	if err != nil {
+		return nil, err
+	}
 	if size > max {
-		// TODO: Kick client
-		return []byte{0}
+		return nil, NewProtocolError("array length %v exceeds the maximum of %v", size, max)
 	}
 	buf := make([]byte, size)
-	r.Data.Read(buf)
-	return buf
+	if _, err = io.ReadFull(r.Data, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
 }
 
 // ReadByteArray reads a byte array which's length cannot
 // exceed MaxByteArrayLength.
-func (r *RawPacket) ReadByteArray() []byte {
+func (r *RawPacket) ReadByteArray() ([]byte, error) {
 	return r.ReadByteArrayMax(MaxByteArrayLength)
 }
 
 // ReadStringMax reads a string which's length cannot exceed max.
-func (r *RawPacket) ReadStringMax(max uint32) string {
-	return string(r.ReadByteArrayMax(max))
+func (r *RawPacket) ReadStringMax(max uint32) (string, error) {
+	b, err := r.ReadByteArrayMax(max)
+	return string(b), err
 }
 
 // ReadString reads a string which's length cannot exceed MaxByteArrayLength.
-func (r *RawPacket) ReadString() string {
-	return string(r.ReadByteArray())
-}
-
-// ReadStructure reads the data as the given structure,
-// and sets its pointer's value.
-func (r *RawPacket) ReadStructure(impl *interface{}) {
-	s := *impl
-	switch s.(type) {
-	case int8:
-		*impl = r.ReadByte()
-	case uint8:
-		*impl = r.ReadUnsignedByte()
-	case bool:
-		*impl = r.ReadBoolean()
-	case int:
-	case int32:
-		*impl = r.ReadVarint()
-	case int64:
-		//TODO: implement
-		panic("unimplemented: varlong")
-	case uint32:
-		*impl = r.ReadUnsignedVarint()
-	case float32:
-		*impl = r.ReadFloat()
-	case float64:
-		*impl = r.ReadDouble()
-	case string:
-		*impl = r.ReadString()
-	case []byte:
-		*impl = r.ReadByteArray()
-	case world.Location:
-		//TODO: implement
-		panic("unimplemented: world.Location")
-	case util.UUID:
-		//TODO: implement
-		panic("unimplemented: util.UUID")
-	default:
-		t := reflect.ValueOf(impl)
-		if t.CanInterface() {
-		}
+func (r *RawPacket) ReadString() (string, error) {
+	b, err := r.ReadByteArray()
+	return string(b), err
+}
+
+// ReadUUID reads the most and then the least significant
+// bits of a UUID and returns it.
+func (r *RawPacket) ReadUUID() (util.UUID, error) {
+	var uuid util.UUID
+	var err error
+	if uuid.MostSig, err = r.ReadLong(); err != nil {
+		return uuid, err
 	}
+	uuid.LeastSig, err = r.ReadLong()
+	return uuid, err
+}
+
+// ReadRemaining reads all the bytes that have not been read yet.
+func (r *RawPacket) ReadRemaining() []byte {
+	buf := make([]byte, len(r.Data.Buf)-r.Data.read)
+	r.Data.Read(buf)
+	return buf
 }
 
 // Release releases RawPacket's data and puts it back to the pool.
@@ -219,6 +232,7 @@ const (
 	EncryptionRequestPacketId    = 0x01
 	EncryptionResponsePacketId   = 0x01
 	LoginSuccessPacketId         = 0x02
+	SetCompressionPacketId       = 0x03
 	// Play state
 	TeleportConfirmPacketId               = 0x00
 	IncomingChatPacketId                  = 0x02
@@ -230,16 +244,33 @@ const (
 	CloseWindowPacketId                   = 0x08
 	PluginMessagePacketId                 = 0x09
 	KeepAliveIncomingPacketId             = 0x0B
+	BlockChangePacketId                   = 0x0B
+	IncomingPlayerPositionPacketId        = 0x0D
 	IncomingPlayerPositionAndLookPacketId = 0x0E
 	OutgoingChatPacketId                  = 0x0F
+	IncomingPlayerLookPacketId            = 0x0F
+	MultiBlockChangePacketId              = 0x10
+	PlayerDiggingPacketId                 = 0x14
+	SetSlotPacketId                       = 0x16
+	HeldItemChangePacketId                = 0x1A
 	KickPlayerPacketId                    = 0x1A
+	CreativeInventoryActionPacketId       = 0x1B
 	IncomingAnimationPacketId             = 0x1D
+	UnloadChunkPacketId                   = 0x1D
+	ChangeGameStatePacketId               = 0x1E
 	KeepAliveOutgoingPacketId             = 0x1F
+	PlayerBlockPlacementPacketId          = 0x1F
 	ChunkDataPacketId                     = 0x20
 	JoinGamePacketId                      = 0x23
 	PlayerAbilitiesPacketId               = 0x2C
+	CombatEventPacketId                   = 0x2D
 	PlayerListItemPacketId                = 0x2E
 	OutgoingPlayerPositionAndLookPacketId = 0x2F
+	RespawnPacketId                       = 0x35
+	WorldBorderPacketId                   = 0x38
+	UpdateHealthPacketId                  = 0x41
+	SpawnPositionPacketId                 = 0x46
+	TimeUpdatePacketId                    = 0x47
 
 	/*** PACKET CONSTS ***/
 	HandshakeStatusNextState = 1
@@ -250,4 +281,15 @@ const (
 	PlayerListItemActionUpdateLatency     = 2
 	PlayerListItemActionUpdateDisplayName = 3
 	PlayerListItemActionRemovePlayer      = 4
+
+	// the reasons of the Change Game State packet
+	ChangeGameStateBeginRain       = 1
+	ChangeGameStateEndRain         = 2
+	ChangeGameStateRainStrength    = 7 // the value is the strength, from 0 to 1
+	ChangeGameStateThunderStrength = 8 // the value is the strength, from 0 to 1
+
+	// the action of the Client Status packet sent by the dead players
+	ClientStatusRespawn = 0
+	// the event of the Combat Event packet showing the death screen
+	CombatEventEntityDead = 2
 )
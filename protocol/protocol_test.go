@@ -1 +1,95 @@
 package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	data := bytes.Repeat([]byte("Goelan"), 100)
+	compressed, err := Compress(data)
+	if err != nil {
+		t.Fatal("Could not compress data:", err)
+	}
+	if len(compressed) >= len(data) {
+		t.Error("Compressed data should be smaller than", len(data), "bytes. Currently", len(compressed))
+	}
+	decompressed, err := Decompress(compressed, len(data))
+	if err != nil {
+		t.Fatal("Could not decompress data:", err)
+	}
+	if !bytes.Equal(data, decompressed) {
+		t.Error("Decompressed data differs from the original data.")
+	}
+}
+
+func TestDecompressWrongLength(t *testing.T) {
+	compressed, _ := Compress([]byte("Goelan"))
+	if _, err := Decompress(compressed, 3); err == nil {
+		t.Error("Decompress should fail when the declared length is smaller than the real one.")
+	}
+	if _, err := Decompress(compressed, 10); err == nil {
+		t.Error("Decompress should fail when the declared length is greater than the real one.")
+	}
+	if _, err := Decompress(compressed, MaxUncompressedLength+1); err == nil {
+		t.Error("Decompress should fail when the declared length exceeds MaxUncompressedLength.")
+	}
+}
+
+func TestReadVarintTooBig(t *testing.T) {
+	if _, err := NewRawPacket(0, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0x7F}, nil).ReadVarint(); err == nil {
+		t.Error("ReadVarint() should fail on varints which do not fit in 32 bits.")
+	}
+}
+
+func TestReadByteArrayMax(t *testing.T) {
+	if _, err := NewRawPacket(0, []byte{0x05, 'a', 'b'}, nil).ReadByteArrayMax(4); err == nil {
+		t.Error("ReadByteArrayMax() should fail when the length exceeds the maximum.")
+	}
+	if _, err := NewRawPacket(0, []byte{0x03, 'a', 'b'}, nil).ReadByteArrayMax(4); err == nil {
+		t.Error("ReadByteArrayMax() should fail when the packet is too short.")
+	}
+	if b, err := NewRawPacket(0, []byte{0x02, 'a', 'b'}, nil).ReadByteArrayMax(4); err != nil || string(b) != "ab" {
+		t.Error("ReadByteArrayMax() should read \"ab\". Currently returns", b, err)
+	}
+}
+
+func TestVarint(t *testing.T) {
+	values := map[int32][]byte{
+		0:           {0x00},
+		1:           {0x01},
+		127:         {0x7F},
+		128:         {0x80, 0x01},
+		300:         {0xAC, 0x02},
+		2147483647:  {0xFF, 0xFF, 0xFF, 0xFF, 0x07},
+		-1:          {0xFF, 0xFF, 0xFF, 0xFF, 0x0F},
+		-2147483648: {0x80, 0x80, 0x80, 0x80, 0x08},
+	}
+	for value, expected := range values {
+		if encoded := Varint(value); !bytes.Equal(encoded, expected) {
+			t.Errorf("Varint(%v) should be %x. Currently returns %x", value, expected, encoded)
+		}
+		if decoded, _ := NewRawPacket(0, expected, nil).ReadVarint(); decoded != value {
+			t.Errorf("ReadVarint() of %x should be %v. Currently returns %v", expected, value, decoded)
+		}
+	}
+}
+
+func TestVarlong(t *testing.T) {
+	values := map[int64][]byte{
+		0:                    {0x00},
+		300:                  {0xAC, 0x02},
+		2147483648:           {0x80, 0x80, 0x80, 0x80, 0x08},
+		9223372036854775807:  {0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F},
+		-1:                   {0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01},
+		-9223372036854775808: {0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01},
+	}
+	for value, expected := range values {
+		if encoded := Varlong(value); !bytes.Equal(encoded, expected) {
+			t.Errorf("Varlong(%v) should be %x. Currently returns %x", value, expected, encoded)
+		}
+		if decoded, _ := NewRawPacket(0, expected, nil).ReadVarlong(); decoded != value {
+			t.Errorf("ReadVarlong() of %x should be %v. Currently returns %v", expected, value, decoded)
+		}
+	}
+}
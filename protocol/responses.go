@@ -6,9 +6,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/json"
-	"reflect"
 	"github.com/olsdavis/goelan/util"
-	"github.com/olsdavis/goelan/world"
 )
 
 type ChatComponent struct {
@@ -16,12 +14,23 @@ type ChatComponent struct {
 }
 
 type Response struct {
-	data *bytes.Buffer
+	data    *bytes.Buffer
+	version uint32
 }
 
-// NewResponse creates a new response.
+// NewResponse creates a new response for the latest protocol version.
 func NewResponse() *Response {
-	return &Response{data: new(bytes.Buffer)}
+	return NewVersionedResponse(LatestProtocolVersion)
+}
+
+// NewVersionedResponse creates a new response for the given protocol version.
+func NewVersionedResponse(version uint32) *Response {
+	return &Response{data: new(bytes.Buffer), version: version}
+}
+
+// ProtocolVersion returns the protocol version in which the response is written.
+func (r *Response) ProtocolVersion() uint32 {
+	return r.version
 }
 
 // WriteBoolean writes the given boolean to the current response.
@@ -45,6 +54,12 @@ func (r *Response) WriteUnsignedByte(b uint8) *Response {
 	return r
 }
 
+// WriteUnsignedShort writes the given unsigned short to the current response.
+func (r *Response) WriteUnsignedShort(s uint16) *Response {
+	binary.Write(r.data, ByteOrder, s)
+	return r
+}
+
 // WriteUVarint writes the given UVarint to the current response.
 func (r *Response) WriteUVarint(i uint32) *Response {
 	_, err := r.data.Write(Uvarint(i))
@@ -63,6 +78,15 @@ func (r *Response) WriteVarint(i int32) *Response {
 	return r
 }
 
+// WriteVarlong writes the given Varlong to the current response.
+func (r *Response) WriteVarlong(l int64) *Response {
+	_, err := r.data.Write(Varlong(l))
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
 // WriteInt writes the given integer to the current response.
 func (r *Response) WriteInt(i int) *Response {
 	binary.Write(r.data, ByteOrder, int32(i))
@@ -87,11 +111,17 @@ func (r *Response) WriteDouble(d float64) *Response {
 	return r
 }
 
+// WritePosition writes the given block coordinates packed in a long:
+// x (26 bits), y (12 bits), then z (26 bits).
+func (r *Response) WritePosition(x, y, z int32) *Response {
+	return r.WriteLong(int64(x&0x3FFFFFF)<<38 | int64(y&0xFFF)<<26 | int64(z&0x3FFFFFF))
+}
+
 // WriteUUID writes the most and then the least significant
 // bits of the given UUID.
-func (r *Response) WriteUUID(uuid util.UUID) {
+func (r *Response) WriteUUID(uuid util.UUID) *Response {
 	r.WriteLong(uuid.MostSig)
-	r.WriteLong(uuid.LeastSig)
+	return r.WriteLong(uuid.LeastSig)
 }
 
 // WriteByteArray writes the given byte array to the current response.
@@ -116,69 +146,18 @@ func (r *Response) WriteJSON(obj interface{}) *Response {
 	return r.WriteByteArray(j)
 }
 
-// WriteStructure explores the field of the given interface, and, if
-// their serialization is supported, writes them to the response;
-// otherwise, tries to explore the field as an interface too--if
-// it fails, skips the field.
-func (r *Response) WriteStructure(object interface{}) *Response {
-	switch object.(type) {
-	case int8:
-		r.WriteByte(object.(int8))
-	case uint8:
-		r.WriteUnsignedByte(object.(byte))
-	case bool:
-		r.WriteBoolean(object.(bool))
-	case int:
-		r.WriteInt(object.(int))
-	case int32:
-		r.WriteVarint(object.(int32))
-	case int64:
-		r.WriteLong(object.(int64))
-	case uint32:
-		r.WriteUVarint(object.(uint32))
-	case float32:
-		r.WriteFloat(object.(float32))
-	case float64:
-		r.WriteDouble(object.(float64))
-	case string:
-		r.WriteString(object.(string))
-	case []byte:
-		r.WriteByteArray(object.([]byte))
-	case world.Location:
-		//TODO: fix
-		loc := object.(world.Location)
-		r.WriteDouble(float64(loc.X))
-		r.WriteDouble(float64(loc.Y))
-		r.WriteDouble(float64(loc.Z))
-		r.WriteFloat(loc.Yaw)
-		r.WriteFloat(loc.Pitch)
-	case util.UUID:
-		r.WriteUUID(object.(util.UUID))
-	default:
-		t := reflect.ValueOf(object)
-		if t.CanInterface() {
-			for i := 0; i < t.NumField(); i++ {
-				f := t.Field(i)
-				if f.Kind() == reflect.Ptr {
-					if f.IsNil() {
-						continue
-					}
-					f = f.Elem()
-				}
-				// ignore nil values
-				if f.Kind() == reflect.Struct && reflect.Zero(f.Type()) == f {
-					continue
-				}
-				r.WriteStructure(f.Interface())
-			}
-		}
-	}
+// WriteRaw writes the given bytes as they are (without their length)
+// to the current response.
+func (r *Response) WriteRaw(b []byte) *Response {
+	r.data.Write(b)
 	return r
 }
 
 // ToRawPacket creates a raw packet from the written bytes and the provided id.
 func (r *Response) ToRawPacket(id uint64) *RawPacket {
-	return NewRawPacket(id, r.data.Bytes(), nil)
+	raw := NewRawPacket(id, r.data.Bytes(), nil)
+	raw.Version = r.version
+	return raw
 }
 
 // Clear clears the data from the response's buffer.
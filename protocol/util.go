@@ -2,7 +2,6 @@ package protocol
 
 import (
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"github.com/olsdavis/goelan/util"
 )
@@ -13,14 +12,17 @@ func Uvarint(n uint32) []byte {
 	return buf[:l]
 }
 
-var oneVarintBuf []byte = []byte{1}
-
+// Varint encodes the given integer as a Varint: negative
+// integers are written as their two's complement.
 func Varint(n int32) []byte {
-	if n == 1 {  // TODO: fix this
-		return oneVarintBuf
-	}
-	buf := make([]byte, 5)
-	l := binary.PutVarint(buf, int64(n))
+	return Uvarint(uint32(n))
+}
+
+// Varlong encodes the given long as a Varlong: negative
+// longs are written as their two's complement.
+func Varlong(n int64) []byte {
+	buf := make([]byte, 10)
+	l := binary.PutUvarint(buf, uint64(n))
 	return buf[:l]
 }
 
@@ -35,7 +37,7 @@ func (br *ByteReader) SetData(data []byte) {
 
 func (br *ByteReader) ReadByte() (byte, error) {
 	if br.read >= len(br.Buf) {
-		return 0, errors.New("out of bounds")
+		return 0, ReadAllError
 	}
 
 	ret := br.Buf[br.read]
@@ -46,6 +48,9 @@ func (br *ByteReader) ReadByte() (byte, error) {
 func (br *ByteReader) Read(p []byte) (int, error) {
 	remaining := br.Buf[br.read:]
 	if len(remaining) == 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
 		return 0, ReadAllError
 	}
 	tr := util.Min(len(remaining), len(p))
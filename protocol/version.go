@@ -0,0 +1,126 @@
+package protocol
+
+const (
+	Protocol1_12   = 335
+	Protocol1_12_1 = 338
+	Protocol1_12_2 = 340
+
+	// LatestProtocolVersion is the protocol version of the most recent
+	// supported Minecraft version. The packet IDs defined in this package
+	// are the ones of this version.
+	LatestProtocolVersion = Protocol1_12_2
+)
+
+// MinecraftVersion represents a Minecraft version supported by the server.
+type MinecraftVersion struct {
+	Name     string
+	Protocol uint32
+}
+
+// idShift shifts, for the IDs comprised between From and To (inclusive),
+// the latest IDs by Offset.
+type idShift struct {
+	State     ConnectionState
+	Direction Direction
+	From, To  uint64
+	Offset    int
+}
+
+// idTable contains the differences between the packet IDs of a version
+// and the ones of the latest version.
+type idTable []idShift
+
+var (
+	// SupportedVersions contains all the versions accepted by the server,
+	// from the oldest to the latest one.
+	SupportedVersions = []MinecraftVersion{
+		{"1.12", Protocol1_12},
+		{"1.12.1", Protocol1_12_1},
+		{"1.12.2", Protocol1_12_2},
+	}
+
+	idTables = map[uint32]idTable{
+		// 1.12.1 added the Craft Recipe Response (0x2B) and the Craft Recipe
+		// Request (0x12) packets, and removed the Prepare Crafting Grid packet (0x01)
+		Protocol1_12: {
+			{PlayState, Clientbound, 0x2C, 0x4F, -1},
+			{PlayState, Serverbound, 0x01, 0x11, +1},
+		},
+		Protocol1_12_1: {},
+		Protocol1_12_2: {},
+	}
+
+	// removedIds contains, for each version, the latest IDs which
+	// do not exist in the version
+	removedIds = map[uint32][]packetKey{
+		Protocol1_12: {
+			{PlayState, Clientbound, 0x2B},
+			{PlayState, Serverbound, 0x12},
+		},
+	}
+)
+
+// GetVersion returns the supported version associated to the given protocol
+// version. Returns false if the version is not supported.
+func GetVersion(protocol uint32) (MinecraftVersion, bool) {
+	for _, version := range SupportedVersions {
+		if version.Protocol == protocol {
+			return version, true
+		}
+	}
+	return MinecraftVersion{}, false
+}
+
+// IsSupportedVersion returns true if the given protocol version is supported.
+func IsSupportedVersion(protocol uint32) bool {
+	_, ok := GetVersion(protocol)
+	return ok
+}
+
+// OldestVersion returns the oldest supported version.
+func OldestVersion() MinecraftVersion {
+	return SupportedVersions[0]
+}
+
+// LatestVersion returns the latest supported version.
+func LatestVersion() MinecraftVersion {
+	return SupportedVersions[len(SupportedVersions)-1]
+}
+
+// ToVersionID converts the given ID of the latest version to the ID the packet has
+// in the given protocol version. Returns false if the packet does not exist in that
+// version. Unsupported versions use the latest IDs.
+func ToVersionID(protocol uint32, state ConnectionState, direction Direction, id uint64) (uint64, bool) {
+	for _, removed := range removedIds[protocol] {
+		if removed == (packetKey{state, direction, id}) {
+			return 0, false
+		}
+	}
+	for _, shift := range idTables[protocol] {
+		if shift.State == state && shift.Direction == direction && id >= shift.From && id <= shift.To {
+			return uint64(int(id) + shift.Offset), true
+		}
+	}
+	return id, true
+}
+
+// ToLatestID converts the ID of a packet in the given protocol version to the ID
+// the packet has in the latest version. Returns false if the packet does not exist
+// in the latest version.
+func ToLatestID(protocol uint32, state ConnectionState, direction Direction, id uint64) (uint64, bool) {
+	for _, shift := range idTables[protocol] {
+		if shift.State != state || shift.Direction != direction {
+			continue
+		}
+		latest := uint64(int(id) - shift.Offset)
+		if latest >= shift.From && latest <= shift.To {
+			return latest, true
+		}
+		// the ID has been freed by the shift
+		if (shift.Offset > 0 && id >= shift.From && id < shift.From+uint64(shift.Offset)) ||
+			(shift.Offset < 0 && id <= shift.To && id > uint64(int(shift.To)+shift.Offset)) {
+			return 0, false
+		}
+	}
+	return id, true
+}
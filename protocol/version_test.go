@@ -0,0 +1,77 @@
+package protocol
+
+import "testing"
+
+func TestVersionIDs(t *testing.T) {
+	// the IDs of 1.12.1 and 1.12.2 are the same
+	for _, version := range []uint32{Protocol1_12_1, Protocol1_12_2} {
+		if id, _ := ToVersionID(version, PlayState, Clientbound, PlayerAbilitiesPacketId); id != PlayerAbilitiesPacketId {
+			t.Errorf("Player Abilities ID of %v should be %#x. Currently %#x", version, PlayerAbilitiesPacketId, id)
+		}
+	}
+
+	clientbound := map[uint64]uint64{
+		JoinGamePacketId:                      0x23,
+		PlayerAbilitiesPacketId:               0x2B,
+		PlayerListItemPacketId:                0x2D,
+		OutgoingPlayerPositionAndLookPacketId: 0x2E,
+		RespawnPacketId:                       0x34,
+		TimeUpdatePacketId:                    0x46,
+		ChangeGameStatePacketId:               0x1E,
+		CombatEventPacketId:                   0x2C,
+		WorldBorderPacketId:                   0x37,
+		UpdateHealthPacketId:                  0x40,
+	}
+	for latest, expected := range clientbound {
+		if id, ok := ToVersionID(Protocol1_12, PlayState, Clientbound, latest); !ok || id != expected {
+			t.Errorf("Clientbound ID %#x should be %#x in 1.12. Currently %#x", latest, expected, id)
+		}
+		if id, ok := ToLatestID(Protocol1_12, PlayState, Clientbound, expected); !ok || id != latest {
+			t.Errorf("Clientbound 1.12 ID %#x should be %#x in the latest version. Currently %#x", expected, latest, id)
+		}
+	}
+
+	serverbound := map[uint64]uint64{
+		TeleportConfirmPacketId:               0x00,
+		IncomingChatPacketId:                  0x03,
+		KeepAliveIncomingPacketId:             0x0C,
+		IncomingPlayerPositionAndLookPacketId: 0x0F,
+		IncomingAnimationPacketId:             0x1D,
+	}
+	for latest, expected := range serverbound {
+		if id, ok := ToVersionID(Protocol1_12, PlayState, Serverbound, latest); !ok || id != expected {
+			t.Errorf("Serverbound ID %#x should be %#x in 1.12. Currently %#x", latest, expected, id)
+		}
+		if id, ok := ToLatestID(Protocol1_12, PlayState, Serverbound, expected); !ok || id != latest {
+			t.Errorf("Serverbound 1.12 ID %#x should be %#x in the latest version. Currently %#x", expected, latest, id)
+		}
+	}
+
+	// Prepare Crafting Grid only exists in 1.12, Craft Recipe Response since 1.12.1
+	if _, ok := ToLatestID(Protocol1_12, PlayState, Serverbound, 0x01); ok {
+		t.Error("Serverbound 1.12 ID 0x01 should not exist in the latest version.")
+	}
+	if _, ok := ToVersionID(Protocol1_12, PlayState, Clientbound, 0x2B); ok {
+		t.Error("Clientbound ID 0x2B should not exist in 1.12.")
+	}
+}
+
+func TestKeepAliveVersions(t *testing.T) {
+	for _, version := range []uint32{Protocol1_12, Protocol1_12_1, Protocol1_12_2} {
+		raw, err := Marshal(version, PlayState, Clientbound, &KeepAlivePacket{ID: 300})
+		if err != nil {
+			t.Fatal("Could not marshal keep alive:", err)
+		}
+		expectedLength := 8
+		if version < Protocol1_12_2 {
+			expectedLength = 2 // Varint
+		}
+		if len(raw.Data.Buf) != expectedLength {
+			t.Errorf("Keep alive of %v should be %v bytes long. Currently %v", version, expectedLength, len(raw.Data.Buf))
+		}
+		decoded, err := Unmarshal(version, PlayState, Clientbound, raw)
+		if err != nil || decoded.(*KeepAlivePacket).ID != 300 {
+			t.Errorf("Could not decode the keep alive of %v: %v", version, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Packet types, as defined by the Source RCON protocol.
+const (
+	ResponseValueType = 0
+	ExecCommandType   = 2
+	AuthResponseType  = 2
+	AuthType          = 3
+)
+
+const (
+	// the minimal length of a packet: the id, the type and the two null bytes
+	minPacketLength = 10
+	// the maximal length of a packet sent by a client
+	maxPacketLength = 4096 + minPacketLength
+	// the maximal length of the body of the packets sent to the clients
+	maxResponseLength = 4096
+)
+
+var (
+	InvalidLengthError = errors.New("invalid rcon packet length")
+)
+
+// Packet struct represents an RCON packet.
+type Packet struct {
+	ID   int32
+	Type int32
+	Body string
+}
+
+// ReadPacket reads a packet from the given reader.
+func ReadPacket(r io.Reader) (*Packet, error) {
+	var length int32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return nil, err
+	}
+	if length < minPacketLength || length > maxPacketLength {
+		return nil, InvalidLengthError
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	// the body is followed by two null bytes
+	body := data[8 : length-2]
+	if i := bytes.IndexByte(body, 0); i >= 0 {
+		body = body[:i]
+	}
+	return &Packet{
+		ID:   int32(binary.LittleEndian.Uint32(data)),
+		Type: int32(binary.LittleEndian.Uint32(data[4:])),
+		Body: string(body),
+	}, nil
+}
+
+// WritePacket writes the given packet to the given writer.
+func WritePacket(w io.Writer, packet *Packet) error {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, int32(len(packet.Body)+minPacketLength))
+	binary.Write(buf, binary.LittleEndian, packet.ID)
+	binary.Write(buf, binary.LittleEndian, packet.Type)
+	buf.WriteString(packet.Body)
+	buf.Write([]byte{0, 0})
+	_, err := w.Write(buf.Bytes())
+	return err
+}
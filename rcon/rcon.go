@@ -0,0 +1,254 @@
+package rcon
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"github.com/olsdavis/goelan/log"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// the id sent back to the clients which failed to authenticate
+	authFailedID = -1
+	// the failed authentications after which the connections of an address are refused
+	maxAuthFailures = 5
+	// the failures of an address are forgotten after this time without any
+	authFailuresReset = 10 * time.Minute
+	// the delay before answering a failed authentication, to slow down brute force
+	defaultAuthFailureDelay = time.Second
+)
+
+// Command struct represents a command sent by an RCON client. The command
+// must be executed with the sender, and then Done must be called so that
+// the output is sent back to the client.
+type Command struct {
+	Line   string
+	Sender *Session
+	done   chan struct{}
+}
+
+// Done signals that the command has been executed.
+func (cmd Command) Done() {
+	close(cmd.done)
+}
+
+// Session struct represents an authenticated RCON client. It is
+// the sender of the commands it executes and collects their output.
+type Session struct {
+	output bytes.Buffer
+}
+
+// SendMessage adds the given message to the output of the current command.
+func (s *Session) SendMessage(message string) {
+	if s.output.Len() > 0 {
+		s.output.WriteByte('\n')
+	}
+	s.output.WriteString(message)
+}
+
+// IsPlayer returns false: an RCON client is not a player.
+func (s *Session) IsPlayer() bool {
+	return false
+}
+
+// HasPermission returns true: an authenticated RCON client has all the permissions.
+func (s *Session) HasPermission(perm string) bool {
+	return true
+}
+
+// authFailures struct counts the failed authentications of an address.
+type authFailures struct {
+	count int
+	last  time.Time
+}
+
+// Listener struct accepts the RCON clients.
+type Listener struct {
+	password     string
+	listener     net.Listener
+	commands     chan Command
+	failures     map[string]*authFailures // by host
+	failureDelay time.Duration
+	lock         sync.Mutex // locks failures
+}
+
+// Listen starts listening for RCON clients on the given address. The
+// clients must authenticate with the given password.
+func Listen(address, password string) (*Listener, error) {
+	if password == "" {
+		return nil, fmt.Errorf("no rcon password set")
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	return newListener(listener, password), nil
+}
+
+// newListener creates a new RCON listener using the given socket.
+func newListener(listener net.Listener, password string) *Listener {
+	return &Listener{
+		password:     password,
+		listener:     listener,
+		commands:     make(chan Command),
+		failures:     make(map[string]*authFailures),
+		failureDelay: defaultAuthFailureDelay,
+	}
+}
+
+// Commands returns the channel on which the commands sent by
+// the clients are received.
+func (l *Listener) Commands() <-chan Command {
+	return l.commands
+}
+
+// Serve accepts the clients until the listener is closed.
+func (l *Listener) Serve() {
+	log.Info("RCON listening on", l.listener.Addr())
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			log.Debug("RCON listener closed:", err)
+			return
+		}
+		if l.isRefused(conn.RemoteAddr()) {
+			log.Debug("Refusing RCON connection from", conn.RemoteAddr(), "after too many failed authentications")
+			conn.Close()
+			continue
+		}
+		go l.handle(conn)
+	}
+}
+
+// Close stops accepting clients.
+func (l *Listener) Close() error {
+	return l.listener.Close()
+}
+
+// handle handles the packets of the given client.
+func (l *Listener) handle(conn net.Conn) {
+	defer conn.Close()
+	session := &Session{}
+	authenticated := false
+	for {
+		packet, err := ReadPacket(conn)
+		if err != nil {
+			if err != io.EOF {
+				log.Debug("Could not read RCON packet from", conn.RemoteAddr(), err)
+			}
+			return
+		}
+
+		switch packet.Type {
+		case AuthType:
+			if l.isRefused(conn.RemoteAddr()) ||
+				subtle.ConstantTimeCompare([]byte(packet.Body), []byte(l.password)) != 1 {
+				log.Warn("Failed RCON authentication from", conn.RemoteAddr())
+				l.authFailed(conn.RemoteAddr())
+				time.Sleep(l.failureDelay)
+				WritePacket(conn, &Packet{ID: authFailedID, Type: AuthResponseType})
+				return
+			}
+			l.authSucceeded(conn.RemoteAddr())
+			authenticated = true
+			log.Info("RCON client authenticated from", conn.RemoteAddr())
+			err = WritePacket(conn, &Packet{ID: packet.ID, Type: AuthResponseType})
+		case ExecCommandType:
+			if !authenticated {
+				WritePacket(conn, &Packet{ID: authFailedID, Type: AuthResponseType})
+				return
+			}
+			log.Info("RCON client", conn.RemoteAddr(), "issued command:", packet.Body)
+			err = l.writeResponse(conn, packet.ID, l.execute(packet.Body, session))
+		case ResponseValueType:
+			// clients send an empty response value after a command to
+			// detect the end of a multi-packet response: mirror it
+			err = WritePacket(conn, &Packet{ID: packet.ID, Type: ResponseValueType})
+		default:
+			err = WritePacket(conn, &Packet{ID: packet.ID, Type: ResponseValueType,
+				Body: fmt.Sprintf("Unknown request %v", packet.Type)})
+		}
+
+		if err != nil {
+			log.Debug("Could not write RCON packet to", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// host returns the host of the given address, which identifies the clients.
+func host(addr net.Addr) string {
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+	return addr.String()
+}
+
+// isRefused returns true if the given address failed to authenticate
+// too many times recently.
+func (l *Listener) isRefused(addr net.Addr) bool {
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	failures, ok := l.failures[host(addr)]
+	if ok && time.Since(failures.last) > authFailuresReset {
+		delete(l.failures, host(addr))
+		return false
+	}
+	return ok && failures.count >= maxAuthFailures
+}
+
+// authFailed counts a failed authentication of the given address.
+func (l *Listener) authFailed(addr net.Addr) {
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	for host, failures := range l.failures {
+		if time.Since(failures.last) > authFailuresReset {
+			delete(l.failures, host)
+		}
+	}
+	failures, ok := l.failures[host(addr)]
+	if !ok {
+		failures = &authFailures{}
+		l.failures[host(addr)] = failures
+	}
+	failures.count++
+	failures.last = time.Now()
+}
+
+// authSucceeded forgets the failed authentications of the given address.
+func (l *Listener) authSucceeded(addr net.Addr) {
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	delete(l.failures, host(addr))
+}
+
+// execute sends the given command to be executed, and returns its output.
+func (l *Listener) execute(line string, session *Session) string {
+	session.output.Reset()
+	done := make(chan struct{})
+	l.commands <- Command{Line: line, Sender: session, done: done}
+	<-done
+	return session.output.String()
+}
+
+// writeResponse writes the given response, split in several
+// packets if it is too long.
+func (l *Listener) writeResponse(w io.Writer, id int32, response string) error {
+	for {
+		length := len(response)
+		if length > maxResponseLength {
+			length = maxResponseLength
+		}
+		if err := WritePacket(w, &Packet{ID: id, Type: ResponseValueType, Body: response[:length]}); err != nil {
+			return err
+		}
+		response = response[length:]
+		if len(response) == 0 {
+			return nil
+		}
+	}
+}
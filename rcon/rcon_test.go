@@ -0,0 +1,151 @@
+package rcon
+
+import (
+	"bytes"
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/justlog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	// discard the logs
+	log.Logger = justlog.New()
+}
+
+func TestPacketEncoding(t *testing.T) {
+	expected := []byte{
+		0x0E, 0x00, 0x00, 0x00, // length
+		0x2A, 0x00, 0x00, 0x00, // id
+		0x02, 0x00, 0x00, 0x00, // type
+		'l', 'i', 's', 't', 0x00, 0x00,
+	}
+	buf := new(bytes.Buffer)
+	WritePacket(buf, &Packet{ID: 42, Type: ExecCommandType, Body: "list"})
+	if !bytes.Equal(expected, buf.Bytes()) {
+		t.Errorf("Packet should be:\n%x\nCurrently:\n%x", expected, buf.Bytes())
+	}
+	packet, err := ReadPacket(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *packet != (Packet{ID: 42, Type: ExecCommandType, Body: "list"}) {
+		t.Errorf("Decoded packet should be the encoded one, currently: %+v", packet)
+	}
+}
+
+func TestPacketInvalidLength(t *testing.T) {
+	if _, err := ReadPacket(bytes.NewReader([]byte{0xFF, 0xFF, 0x00, 0x00})); err != InvalidLengthError {
+		t.Error("Too long packets should be rejected, got:", err)
+	}
+}
+
+// startSession starts handling a client and executes the commands it sends,
+// answering with the given output.
+func startSession(output string) net.Conn {
+	l := newListener(nil, "password")
+	l.failureDelay = 0
+	client, server := net.Pipe()
+	go l.handle(server)
+	go func() {
+		for cmd := range l.commands {
+			cmd.Sender.SendMessage(output)
+			cmd.Done()
+		}
+	}()
+	return client
+}
+
+// exchange sends the given packet and reads the response.
+func exchange(t *testing.T, conn net.Conn, packet *Packet) *Packet {
+	go WritePacket(conn, packet)
+	response, err := ReadPacket(conn)
+	if err != nil {
+		t.Fatal("Could not read response:", err)
+	}
+	return response
+}
+
+func TestSession(t *testing.T) {
+	conn := startSession("Stopping the server")
+	defer conn.Close()
+
+	if response := exchange(t, conn, &Packet{ID: 1, Type: AuthType, Body: "password"}); response.ID != 1 || response.Type != AuthResponseType {
+		t.Fatalf("Authentication should succeed, got: %+v", response)
+	}
+	response := exchange(t, conn, &Packet{ID: 2, Type: ExecCommandType, Body: "stop"})
+	if response.ID != 2 || response.Type != ResponseValueType || response.Body != "Stopping the server" {
+		t.Errorf("Command output should be sent back, got: %+v", response)
+	}
+}
+
+func TestSessionWrongPassword(t *testing.T) {
+	conn := startSession("")
+	defer conn.Close()
+
+	if response := exchange(t, conn, &Packet{ID: 1, Type: AuthType, Body: "wrong"}); response.ID != authFailedID {
+		t.Errorf("Authentication should fail, got: %+v", response)
+	}
+}
+
+func TestSessionNotAuthenticated(t *testing.T) {
+	conn := startSession("")
+	defer conn.Close()
+
+	if response := exchange(t, conn, &Packet{ID: 1, Type: ExecCommandType, Body: "stop"}); response.ID != authFailedID {
+		t.Errorf("Commands should not be executed without authentication, got: %+v", response)
+	}
+}
+
+func TestSessionMultiPacketResponse(t *testing.T) {
+	output := strings.Repeat("a", maxResponseLength) + "b"
+	conn := startSession(output)
+	defer conn.Close()
+
+	exchange(t, conn, &Packet{ID: 1, Type: AuthType, Body: "password"})
+	if response := exchange(t, conn, &Packet{ID: 2, Type: ExecCommandType, Body: "help"}); response.Body != output[:maxResponseLength] {
+		t.Errorf("First packet should contain %v bytes, currently: %v", maxResponseLength, len(response.Body))
+	}
+	if response, _ := ReadPacket(conn); response == nil || response.ID != 2 || response.Body != "b" {
+		t.Errorf("Second packet should contain the rest of the output, got: %+v", response)
+	}
+}
+
+func TestAuthFailuresThrottling(t *testing.T) {
+	socket, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := newListener(socket, "password")
+	defer l.Close()
+	l.failureDelay = 50 * time.Millisecond
+	go l.Serve()
+
+	for i := 0; i < maxAuthFailures; i++ {
+		conn, err := net.Dial("tcp", socket.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Now()
+		if response := exchange(t, conn, &Packet{ID: 1, Type: AuthType, Body: "wrong"}); response.ID != authFailedID {
+			t.Errorf("Authentication should fail, got: %+v", response)
+		}
+		if elapsed := time.Since(start); elapsed < l.failureDelay {
+			t.Error("A failed authentication should be answered after", l.failureDelay, "got", elapsed)
+		}
+		conn.Close()
+	}
+
+	// even the right password is refused now
+	conn, err := net.Dial("tcp", socket.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	go WritePacket(conn, &Packet{ID: 1, Type: AuthType, Body: "password"})
+	if response, err := ReadPacket(conn); err == nil && response.ID != authFailedID {
+		t.Errorf("The address should be refused after %v failures, got: %+v", maxAuthFailures, response)
+	}
+}
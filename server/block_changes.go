@@ -0,0 +1,91 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/world"
+	"sync"
+)
+
+// blockChanges struct accumulates the blocks changed during a tick, so that
+// they are sent at once to the players tracking their chunk: a Block Change
+// packet if only one block of a chunk changed, a Multi Block Change otherwise.
+type blockChanges struct {
+	changes map[worldChunkPos]map[uint16]world.BlockState // chunk => y << 8 | z << 4 | x => state
+	lock    sync.Mutex
+}
+
+// worldChunkPos struct represents the coordinates of a chunk of a world.
+type worldChunkPos struct {
+	world *world.World
+	pos   world.ChunkPos
+}
+
+// newBlockChanges creates an empty set of changes.
+func newBlockChanges() *blockChanges {
+	return &blockChanges{changes: make(map[worldChunkPos]map[uint16]world.BlockState)}
+}
+
+// add records the change of the block at the given coordinates of the
+// given world. Only the last change of a block is sent.
+func (b *blockChanges) add(w *world.World, x, y, z int32, state world.BlockState) {
+	defer b.lock.Unlock()
+	b.lock.Lock()
+	pos := worldChunkPos{w, world.ChunkPos{X: x >> 4, Z: z >> 4}}
+	blocks, ok := b.changes[pos]
+	if !ok {
+		blocks = make(map[uint16]world.BlockState)
+		b.changes[pos] = blocks
+	}
+	blocks[uint16(y)<<8|uint16(z&0x0F)<<4|uint16(x&0x0F)] = state
+}
+
+// packets returns the packets to send for the recorded changes, by
+// chunk, and forgets them.
+func (b *blockChanges) packets() map[worldChunkPos]protocol.Packet {
+	b.lock.Lock()
+	changes := b.changes
+	b.changes = make(map[worldChunkPos]map[uint16]world.BlockState)
+	b.lock.Unlock()
+
+	ret := make(map[worldChunkPos]protocol.Packet, len(changes))
+	for key, blocks := range changes {
+		pos := key.pos
+		records := make([]protocol.BlockChangeRecord, 0, len(blocks))
+		for index, state := range blocks {
+			records = append(records, protocol.BlockChangeRecord{
+				X:     uint8(index & 0x0F),
+				Y:     uint8(index >> 8),
+				Z:     uint8(index >> 4 & 0x0F),
+				State: state,
+			})
+		}
+		if len(records) == 1 {
+			r := records[0]
+			ret[key] = &protocol.BlockChangePacket{
+				X:     pos.X<<4 | int32(r.X),
+				Y:     int32(r.Y),
+				Z:     pos.Z<<4 | int32(r.Z),
+				State: r.State,
+			}
+		} else {
+			ret[key] = &protocol.MultiBlockChangePacket{ChunkX: pos.X, ChunkZ: pos.Z, Records: records}
+		}
+	}
+	return ret
+}
+
+// sendBlockChanges sends the blocks changed since the last tick to
+// the players who have loaded their chunk.
+func (s *Server) sendBlockChanges() {
+	packets := s.blockChanges.packets()
+	if len(packets) == 0 {
+		return
+	}
+	s.ForEachPlayerSync(func(c *Connection) {
+		for key, packet := range packets {
+			if c.Player.GetLocation().World == key.world && c.chunks.isLoaded(key.pos) {
+				c.WritePacket(packet)
+			}
+		}
+	})
+}
@@ -0,0 +1,387 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/nbt"
+	"github.com/olsdavis/goelan/player"
+	. "github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/world"
+	"github.com/olsdavis/goelan/world/val"
+	"math"
+	"strings"
+	"time"
+)
+
+// This file contains the handlers which let the players break and place
+// blocks. The server checks every action, and sends the actual state of
+// the blocks back to the client when it rejects one.
+
+const (
+	// the maximal squared distance between the eyes of a player and the center of a block it uses
+	maxReachSquared = 6 * 6
+	// the height of the eyes of a standing player
+	eyeHeight = 1.62
+	// the size of the hitbox of a player
+	playerWidth  = 0.6
+	playerHeight = 1.8
+	// the break time is computed as the one of vanilla, for a block
+	// which does not need a tool: hardness * 30 / speed ticks
+	breakTimeFactor = 30
+	// the fraction of the break time accepted, to absorb the latency
+	breakTimeTolerance = 0.7
+	// the factor of the mining speed given by Haste II, the highest level
+	// of the beacons; the effects are not tracked, so players may have it
+	maxHasteFactor = 1 + 0.2*2
+	// the mining speed of the swords on cobweb, and on the plants and the
+	// leaves (applied to every other block, not to refuse any vanilla break)
+	swordWebSpeed = 15
+	swordSpeed    = 1.5
+	// the break times are rounded up to ticks, ignoring the errors below this
+	breakTimeEpsilon = 1e-6
+	// the ID of the efficiency enchantment
+	efficiencyEnchantment = 32
+	// the hand of the Player Block Placement packet which is the offhand
+	offHand = 1
+)
+
+var (
+	// the blocks replaced by the placed blocks, instead of being placed against
+	replaceableBlocks = map[int]bool{
+		material.Air.ID:           true,
+		material.FlowingWater.ID:  true,
+		material.Water.ID:         true,
+		material.FlowingLava.ID:   true,
+		material.Lava.ID:          true,
+		material.Fire.ID:          true,
+		material.Tallgrass.ID:     true,
+		material.Deadbush.ID:      true,
+		material.Vine.ID:          true,
+		material.SnowLayer.ID:     true,
+		material.StructureVoid.ID: true,
+	}
+	// the mining speed of the tiers of the tools
+	toolSpeeds = map[string]float64{
+		"wooden":  2,
+		"stone":   4,
+		"iron":    6,
+		"diamond": 8,
+		"golden":  12,
+	}
+)
+
+// diggingState struct is the block a player started to break in survival mode.
+type diggingState struct {
+	X, Y, Z int32
+	start   time.Time
+}
+
+func playerDiggingHandler(packet Packet, sender *Connection) error {
+	p := packet.(*PlayerDiggingPacket)
+	switch p.Status {
+	case DiggingStarted:
+		startDigging(sender, p.X, p.Y, p.Z)
+	case DiggingCancelled:
+		sender.digging = nil
+	case DiggingFinished:
+		finishDigging(sender, p.X, p.Y, p.Z)
+	}
+	return nil
+}
+
+// startDigging breaks the given block right away if the player can break it
+// instantly, or remembers when the player started to break it otherwise.
+func startDigging(sender *Connection, x, y, z int32) {
+	sender.digging = nil
+	state, ok := usableBlock(sender, x, y, z)
+	if !ok || state == world.Air {
+		sender.rollback(x, y, z)
+		return
+	}
+	pl := sender.Player
+	if pl.GameMode == player.CreativeMode {
+		// swords cannot break blocks in creative mode
+		if held := pl.Inventory.GetHeldItem(); held != nil && strings.HasSuffix(held.Material.Name, "_sword") {
+			sender.rollback(x, y, z)
+			return
+		}
+		sender.breakBlock(x, y, z)
+		return
+	}
+	mat := state.Material()
+	if !mat.IsBreakable() {
+		sender.rollback(x, y, z)
+		return
+	}
+	if breakTime(mat, pl.Inventory.GetHeldItem(), maxHasteFactor) == 0 {
+		sender.breakBlock(x, y, z)
+		return
+	}
+	sender.digging = &diggingState{x, y, z, time.Now()}
+}
+
+// finishDigging breaks the given block if the player has been breaking
+// it for long enough.
+func finishDigging(sender *Connection, x, y, z int32) {
+	digging := sender.digging
+	sender.digging = nil
+	state, ok := usableBlock(sender, x, y, z)
+	if !ok || digging == nil || digging.X != x || digging.Y != y || digging.Z != z || state == world.Air {
+		sender.rollback(x, y, z)
+		return
+	}
+	expected := breakTime(state.Material(), sender.Player.Inventory.GetHeldItem(), maxHasteFactor)
+	if elapsed := time.Since(digging.start); elapsed.Seconds() < expected.Seconds()*breakTimeTolerance {
+		log.Debug(sender.Player.GetName(), "broke a block in", elapsed, "instead of", expected)
+		sender.rollback(x, y, z)
+		return
+	}
+	sender.breakBlock(x, y, z)
+}
+
+// breakTime returns the time needed to break a block of the given material
+// in survival mode, with the given tool (nil if none), the mining speed
+// being multiplied by the given factor (1 without Haste). Returns 0 if
+// the block breaks instantly.
+func breakTime(mat material.Material, tool *player.ItemStack, factor float64) time.Duration {
+	if mat.Hardness <= 0 {
+		return 0
+	}
+	ticks := float64(mat.Hardness) * breakTimeFactor / (toolSpeed(mat, tool) * factor)
+	// as in vanilla, the blocks which take a tick break instantly
+	if ticks <= 1 {
+		return 0
+	}
+	// the rounding errors of the float32 hardnesses must not add a tick
+	return time.Duration(math.Ceil(ticks-breakTimeEpsilon)) * time.Second / 20
+}
+
+// toolSpeed returns the mining speed of the given tool on the given
+// material. The tiered tools have the speed of their tier on every block,
+// increased by their efficiency enchantment: the result is never lower
+// than the one of vanilla.
+func toolSpeed(mat material.Material, tool *player.ItemStack) float64 {
+	if tool == nil {
+		return 1
+	}
+	speed := 1.0
+	if tool.Material == material.Shears {
+		speed = 15
+	} else if i := strings.Index(tool.Material.Name, "_"); i > 0 {
+		switch tool.Material.Name[i+1:] {
+		case "pickaxe", "axe", "shovel":
+			if tier, ok := toolSpeeds[tool.Material.Name[:i]]; ok {
+				speed = tier
+			}
+		case "sword":
+			if mat == material.Web {
+				speed = swordWebSpeed
+			} else {
+				speed = swordSpeed
+			}
+		}
+	}
+	if level := enchantmentLevel(tool, efficiencyEnchantment); level > 0 && speed > 1 {
+		speed += float64(level*level + 1)
+	}
+	return speed
+}
+
+// enchantmentLevel returns the level of the given enchantment of the
+// given item, or 0 if it does not have it.
+func enchantmentLevel(item *player.ItemStack, id int16) int {
+	if item.Tag == nil {
+		return 0
+	}
+	enchantments := item.Tag.GetList("ench")
+	if enchantments == nil {
+		return 0
+	}
+	for _, tag := range enchantments.Elements {
+		if enchantment, ok := tag.(nbt.Compound); ok && enchantment.GetShort("id") == id {
+			return int(enchantment.GetShort("lvl"))
+		}
+	}
+	return 0
+}
+
+func playerBlockPlacementHandler(packet Packet, sender *Connection) error {
+	p := packet.(*PlayerBlockPlacementPacket)
+	dx, dy, dz, ok := FaceOffset(p.Face)
+	if !ok {
+		return NewProtocolError("invalid face %v", p.Face)
+	}
+	x, y, z := p.X, p.Y, p.Z
+	clicked, ok := usableBlock(sender, x, y, z)
+	if !ok {
+		sender.rollback(p.X, p.Y, p.Z)
+		sender.rollbackSlot(heldSlot(sender.Player, p.Hand))
+		return nil
+	}
+	// the replaceable blocks are replaced instead of being placed against
+	if !replaceableBlocks[clicked.ID()] {
+		x, y, z = x+dx, y+dy, z+dz
+	}
+	if !placeBlock(sender, x, y, z, p.Hand) {
+		sender.rollback(p.X, p.Y, p.Z)
+		sender.rollback(x, y, z)
+		sender.rollbackSlot(heldSlot(sender.Player, p.Hand))
+	}
+	return nil
+}
+
+// heldSlot returns the index, in the window, of the item held by the
+// given player in the given hand.
+func heldSlot(pl *player.Player, hand int32) int {
+	if hand == offHand {
+		return player.OffhandSlot
+	}
+	return pl.Inventory.HeldItemSlot()
+}
+
+// placeBlock places the block held in the given hand at the given
+// coordinates. Returns false if the player cannot place it.
+func placeBlock(sender *Connection, x, y, z int32, hand int32) bool {
+	pl := sender.Player
+	slot := heldSlot(pl, hand)
+	held := pl.Inventory.GetSlot(slot)
+	if held == nil || !held.Material.IsBlock() || held.Material == material.Air {
+		return false
+	}
+	current, ok := usableBlock(sender, x, y, z)
+	if !ok || !replaceableBlocks[current.ID()] {
+		return false
+	}
+	mat := held.Material
+	// the blocks without hitbox may be placed in the players
+	solid := mat.Opacity > 0 || mat.Hardness > 0
+	if solid {
+		intersects := false
+		w := pl.GetLocation().World
+		sender.GetServer().ForEachPlayerSync(func(c *Connection) {
+			if c.Player == nil {
+				return
+			}
+			// the other players move in their own routines
+			if location := c.Player.GetLocation(); location.World == w && intersectsPlayer(&location, x, y, z) {
+				intersects = true
+			}
+		})
+		if intersects {
+			return false
+		}
+	}
+	if pl.GameMode == player.SurvivalMode && !pl.Inventory.RemoveItem(slot, 1) {
+		return false
+	}
+	var metadata byte
+	if len(mat.Variants()) > 0 {
+		metadata = byte(held.Damage) & 0x0F
+	}
+	state := world.NewBlockState(mat, metadata)
+	w := pl.GetLocation().World
+	if !w.SetBlockState(x, y, z, state) {
+		// gives the item back
+		pl.Inventory.SetSlot(slot, held)
+		return false
+	}
+	sender.GetServer().blockChanges.add(w, x, y, z, state)
+	return true
+}
+
+// intersectsPlayer returns true if the block at the given coordinates
+// intersects the hitbox of a player at the given location.
+func intersectsPlayer(location *world.Location, x, y, z int32) bool {
+	half := float32(playerWidth / 2)
+	return location.X+half > float32(x) && location.X-half < float32(x+1) &&
+		location.Y+playerHeight > float32(y) && location.Y < float32(y+1) &&
+		location.Z+half > float32(z) && location.Z-half < float32(z+1)
+}
+
+// usableBlock returns the state of the block at the given coordinates if
+// the player can break it or place a block there: the player must be in
+// survival or creative mode, the block in a loaded chunk and within reach.
+func usableBlock(sender *Connection, x, y, z int32) (world.BlockState, bool) {
+	pl := sender.Player
+	if pl.GameMode != player.SurvivalMode && pl.GameMode != player.CreativeMode {
+		return world.Air, false
+	}
+	if y < 0 || y >= val.ChunkHeight {
+		return world.Air, false
+	}
+	location := pl.GetLocation()
+	dx := float64(location.X) - (float64(x) + 0.5)
+	dy := float64(location.Y) + eyeHeight - (float64(y) + 0.5)
+	dz := float64(location.Z) - (float64(z) + 0.5)
+	if dx*dx+dy*dy+dz*dz > maxReachSquared {
+		return world.Air, false
+	}
+	chunk := location.World.GetLoadedChunk(x>>4, z>>4)
+	if chunk == nil {
+		return world.Air, false
+	}
+	defer chunk.RUnlock()
+	chunk.RLock()
+	return chunk.GetBlockState(int(x&0x0F), int(y), int(z&0x0F)), true
+}
+
+// breakBlock removes the block at the given coordinates.
+func (c *Connection) breakBlock(x, y, z int32) {
+	w := c.Player.GetLocation().World
+	if w.SetBlockState(x, y, z, world.Air) {
+		c.GetServer().blockChanges.add(w, x, y, z, world.Air)
+	}
+}
+
+// rollback sends the actual state of the given block to the client,
+// which predicted a change that has been rejected.
+func (c *Connection) rollback(x, y, z int32) {
+	if y < 0 || y >= val.ChunkHeight {
+		return
+	}
+	chunk := c.Player.GetLocation().World.GetLoadedChunk(x>>4, z>>4)
+	if chunk == nil {
+		return
+	}
+	chunk.RLock()
+	state := chunk.GetBlockState(int(x&0x0F), int(y), int(z&0x0F))
+	chunk.RUnlock()
+	c.WritePacket(&BlockChangePacket{X: x, Y: y, Z: z, State: state})
+}
+
+// rollbackSlot sends the actual content of the given slot of the
+// inventory to the client, which predicted a change that has been rejected.
+func (c *Connection) rollbackSlot(slot int) {
+	c.WritePacket(&SetSlotPacket{
+		WindowID: InventoryWindowID,
+		Slot:     int16(slot),
+		Item:     c.Player.Inventory.GetSlot(slot),
+	})
+}
+
+func heldItemChangeHandler(packet Packet, sender *Connection) error {
+	slot := packet.(*HeldItemChangePacket).Slot
+	if !sender.Player.Inventory.SetHeldSlot(int(slot)) {
+		log.Warn(sender.Player.GetName(), "tried to select the invalid slot", slot)
+	}
+	return nil
+}
+
+func creativeInventoryActionHandler(packet Packet, sender *Connection) error {
+	p := packet.(*CreativeInventoryActionPacket)
+	if sender.Player.GameMode != player.CreativeMode {
+		return nil
+	}
+	// -1: the item is dropped; 0: the output of the crafting grid
+	if p.Slot < 1 {
+		return nil
+	}
+	if p.Item != nil && (p.Item.Count == 0 || int(p.Item.Count) > p.Item.Material.StackSize) {
+		log.Warn(sender.Player.GetName(), "tried to create an invalid stack of", p.Item.Count, p.Item.Material)
+		return nil
+	}
+	if !sender.Player.Inventory.SetSlot(int(p.Slot), p.Item) {
+		log.Warn(sender.Player.GetName(), "tried to set the invalid slot", p.Slot)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/nbt"
+	"github.com/olsdavis/goelan/player"
+	"github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/world"
+	"testing"
+	"time"
+)
+
+func TestBlockChangesPackets(t *testing.T) {
+	changes := newBlockChanges()
+	w, other := world.NewWorld("world"), world.NewWorld("other")
+	stone := world.NewBlockState(material.Stone, 0)
+	changes.add(w, -1, 64, 17, stone)
+	changes.add(w, -1, 64, 17, world.Air) // only the last change is sent
+	changes.add(w, 5, 10, 5, stone)
+	changes.add(w, 6, 11, 5, stone)
+	changes.add(other, 5, 10, 5, stone)
+
+	packets := changes.packets()
+	if len(packets) != 3 {
+		t.Fatalf("Expected the packets of 3 chunks, got %v", len(packets))
+	}
+	if _, ok := packets[worldChunkPos{other, world.ChunkPos{}}].(*protocol.BlockChangePacket); !ok {
+		t.Error("The changes of the worlds should be sent separately")
+	}
+	single, ok := packets[worldChunkPos{w, world.ChunkPos{X: -1, Z: 1}}].(*protocol.BlockChangePacket)
+	if !ok {
+		t.Fatal("A single change should be sent in a Block Change packet")
+	}
+	if single.X != -1 || single.Y != 64 || single.Z != 17 || single.State != world.Air {
+		t.Errorf("Wrong block change: %+v", single)
+	}
+	multi, ok := packets[worldChunkPos{w, world.ChunkPos{X: 0, Z: 0}}].(*protocol.MultiBlockChangePacket)
+	if !ok {
+		t.Fatal("Several changes should be sent in a Multi Block Change packet")
+	}
+	if len(multi.Records) != 2 {
+		t.Errorf("Expected 2 records, got %v", len(multi.Records))
+	}
+	if len(changes.packets()) != 0 {
+		t.Error("The changes should only be sent once")
+	}
+}
+
+func TestBreakTime(t *testing.T) {
+	if d := breakTime(material.Torch, nil, 1); d != 0 {
+		t.Error("Torches should break instantly, got", d)
+	}
+	// stone: 1.5 * 30 = 45 ticks by hand
+	if d := breakTime(material.Stone, nil, 1); d != 45*time.Second/20 {
+		t.Error("Wrong break time of stone by hand:", d)
+	}
+	pickaxe := &player.ItemStack{Material: material.DiamondPickaxe, Count: 1}
+	if d := breakTime(material.Stone, pickaxe, 1); d != 6*time.Second/20 {
+		t.Error("Wrong break time of stone with a diamond pickaxe:", d)
+	}
+	pickaxe.Tag = nbt.Compound{
+		"ench": nbt.NewList(nbt.TagCompound, nbt.Compound{"id": nbt.Short(32), "lvl": nbt.Short(2)}),
+	}
+	// 8 + 2 * 2 + 1 = 13
+	if d := breakTime(material.Stone, pickaxe, 1); d != 4*time.Second/20 {
+		t.Error("Wrong break time of stone with an efficiency pickaxe:", d)
+	}
+	// with Haste II: 45 / (13 * 1.4) = 2.5 ticks
+	if d := breakTime(material.Stone, pickaxe, maxHasteFactor); d != 3*time.Second/20 {
+		t.Error("Wrong break time of stone with an efficiency pickaxe and Haste II:", d)
+	}
+	// 0.4 * 30 / 13 < 1 tick
+	if d := breakTime(material.Netherrack, pickaxe, 1); d != 0 {
+		t.Error("Netherrack should break instantly with an efficiency pickaxe, got", d)
+	}
+	// with Haste II: 45 / 1.4 = 32.1 ticks
+	if d := breakTime(material.Stone, nil, maxHasteFactor); d != 33*time.Second/20 {
+		t.Error("Wrong break time of stone by hand with Haste II:", d)
+	}
+}
+
+func TestSwordBreakTime(t *testing.T) {
+	sword := &player.ItemStack{Material: material.DiamondSword, Count: 1}
+	// cobweb: 4 * 30 / 15 = 8 ticks, instead of 120 by hand
+	if d := breakTime(material.Web, sword, 1); d != 8*time.Second/20 {
+		t.Error("Wrong break time of cobweb with a sword:", d)
+	}
+	// leaves: 0.2 * 30 / 1.5 = 4 ticks
+	if d := breakTime(material.Leaves, sword, 1); d != 4*time.Second/20 {
+		t.Error("Wrong break time of leaves with a sword:", d)
+	}
+	if breakTime(material.Stone, sword, 1) > breakTime(material.Stone, nil, 1) {
+		t.Error("A sword should not break blocks slower than a hand")
+	}
+}
+
+func TestIntersectsPlayer(t *testing.T) {
+	location := &world.Location{Location3f: world.Location3f{X: 0.5, Y: 64, Z: 0.5}}
+	if !intersectsPlayer(location, 0, 65, 0) {
+		t.Error("The head of the player should intersect the block")
+	}
+	if intersectsPlayer(location, 0, 66, 0) || intersectsPlayer(location, 1, 64, 0) || intersectsPlayer(location, 0, 63, 0) {
+		t.Error("The blocks around the player should not intersect it")
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/player"
+	"github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/world"
+	"time"
+)
+
+// This file sends the world borders to the players, keeps them from
+// crossing them, and hurts the ones beyond them.
+
+const (
+	// the number of ticks between two damages taken beyond the border
+	borderDamageInterval = 20
+)
+
+// borderPacket returns the World Border packet initializing the given border.
+func borderPacket(b world.Border) *protocol.WorldBorderPacket {
+	return &protocol.WorldBorderPacket{
+		Action:                 protocol.WorldBorderInitialize,
+		X:                      b.CenterX,
+		Z:                      b.CenterZ,
+		OldDiameter:            b.Diameter,
+		NewDiameter:            b.TargetDiameter,
+		Speed:                  int64(b.Remaining / time.Millisecond),
+		PortalTeleportBoundary: world.PortalTeleportBoundary,
+		WarningTime:            b.WarningTime,
+		WarningBlocks:          b.WarningBlocks,
+	}
+}
+
+// sendBorder sends the border of the player's world.
+func (c *Connection) sendBorder() {
+	c.WritePacket(borderPacket(c.Player.GetLocation().World.GetBorder()))
+}
+
+// UpdateBorder sends the border of the given world to its players.
+// It must be called once the border has been changed.
+func (s *Server) UpdateBorder(w *world.World) {
+	packet := borderPacket(w.GetBorder())
+	s.ForEachPlayerSync(func(c *Connection) {
+		if c.Player.GetLocation().World == w {
+			c.WritePacket(packet)
+		}
+	})
+}
+
+// damageBeyondBorders hurts the players beyond the border of their world.
+func (s *Server) damageBeyondBorders() {
+	borders := make(map[*world.World]world.Border)
+	damages := make(map[*Connection]float64)
+	s.ForEachPlayerSync(func(c *Connection) {
+		pl := c.Player
+		if !pl.CanTakeDamage() || pl.IsDead() {
+			return
+		}
+		location := pl.GetLocation()
+		border, ok := borders[location.World]
+		if !ok {
+			border = location.World.GetBorder()
+			borders[location.World] = border
+		}
+		if damage := border.Damage(float64(location.X), float64(location.Z)); damage > 0 {
+			damages[c] = damage
+		}
+	})
+	// the deaths are broadcast to all the players
+	for c, damage := range damages {
+		c.Damage(float32(damage), c.Player.GetName()+" suffocated in a wall.")
+	}
+}
+
+// crossesBorder returns true if the move of the given player to the given
+// point takes it further beyond the border of its world.
+func crossesBorder(pl *player.Player, x, z float64) bool {
+	location := pl.GetLocation()
+	border := location.World.GetBorder()
+	distance := border.Distance(x, z)
+	return distance < 0 && distance < border.Distance(float64(location.X), float64(location.Z))
+}
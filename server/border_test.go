@@ -0,0 +1,65 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/player"
+	"github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/world"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBorderPacket(t *testing.T) {
+	packet := borderPacket(world.Border{
+		CenterX:        10,
+		CenterZ:        -10,
+		Diameter:       500,
+		TargetDiameter: 100,
+		Remaining:      90 * time.Second,
+		WarningBlocks:  5,
+		WarningTime:    15,
+	})
+	if packet.Action != protocol.WorldBorderInitialize || packet.OldDiameter != 500 || packet.NewDiameter != 100 || packet.Speed != 90000 {
+		t.Errorf("Unexpected packet %+v", packet)
+	}
+}
+
+func TestCrossesBorder(t *testing.T) {
+	w := world.NewWorld("border")
+	w.Info = world.NewWorldInfo("border", 0)
+	w.ResizeBorder(100, 0)
+	pl := &player.Player{Location: world.NewLocation(45, 64, 0, w)}
+	if crossesBorder(pl, 49, 0) {
+		t.Error("The player may move inside the border")
+	}
+	if !crossesBorder(pl, 51, 0) {
+		t.Error("The player should not cross the border")
+	}
+	// a player beyond a shrunk border may come back
+	pl.Location.X = 60
+	if crossesBorder(pl, 55, 0) || !crossesBorder(pl, 61, 0) {
+		t.Error("The player beyond the border may only come back")
+	}
+}
+
+func TestValidPosition(t *testing.T) {
+	valid := [][3]float64{{0, 64, 0}, {-3e7, -100, 3e7}, {123.5, 1e6, -456.25}}
+	invalid := [][3]float64{
+		{math.NaN(), 64, 0},
+		{0, math.NaN(), 0},
+		{0, 64, math.Inf(1)},
+		{math.Inf(-1), 64, 0},
+		{3e7 + 1, 64, 0},
+		{0, 64, -3e7 - 1},
+	}
+	for _, p := range valid {
+		if !validPosition(p[0], p[1], p[2]) {
+			t.Error("The position should be valid:", p)
+		}
+	}
+	for _, p := range invalid {
+		if validPosition(p[0], p[1], p[2]) {
+			t.Error("The position should be invalid:", p)
+		}
+	}
+}
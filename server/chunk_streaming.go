@@ -0,0 +1,300 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/world"
+	"github.com/olsdavis/goelan/world/val"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// This file streams the chunks around the players: the chunks entering
+// their view distance are requested to the chunk pool, nearest first,
+// and sent a few at a time once ready; the ones leaving it are unloaded.
+
+const (
+	// the maximal number of chunks sent to a player per tick
+	maxChunksPerTick = 8
+	// the maximal number of chunks requested to the pool for a player at once
+	maxPendingChunks = 4 * maxChunksPerTick
+	// the view distance used if neither the server nor the client set one
+	defaultViewDistance = 10
+	// the minimal view distance accepted by the client
+	minViewDistance = 2
+)
+
+// chunkTracker struct tracks the chunks that have been sent to a player.
+type chunkTracker struct {
+	world        *world.World            // the world of the tracked chunks
+	center       world.ChunkPos          // the chunk the player is in
+	viewDistance int                     // the radius, in chunks, of the area to send
+	requested    map[world.ChunkPos]bool // the chunks requested to the pool, or sent to the client
+	sent         map[world.ChunkPos]bool // the chunks sent to the client
+	queue        []world.ChunkPos        // the chunks to request, nearest first
+	pending      []*world.ChunkFuture    // the chunks requested to the pool, nearest first
+	initialized  bool
+	lock         sync.Mutex
+}
+
+// newChunkTracker creates a tracker which has not sent any chunk.
+func newChunkTracker() *chunkTracker {
+	return &chunkTracker{
+		requested: make(map[world.ChunkPos]bool),
+		sent:      make(map[world.ChunkPos]bool),
+		queue:     make([]world.ChunkPos, 0),
+		pending:   make([]*world.ChunkFuture, 0),
+	}
+}
+
+// update moves the tracked area to the given world, center and view distance.
+// Returns the chunks which left the area, and must be unloaded by the client:
+// all the chunks sent if the world changed.
+func (t *chunkTracker) update(w *world.World, center world.ChunkPos, viewDistance int) []world.ChunkPos {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	if t.initialized && t.world == w && t.center == center && t.viewDistance == viewDistance {
+		return nil
+	}
+	unload := make([]world.ChunkPos, 0)
+	if t.world != w {
+		for pos := range t.sent {
+			unload = append(unload, pos)
+		}
+		t.forget()
+		t.world = w
+	}
+	t.center = center
+	t.viewDistance = viewDistance
+	t.initialized = true
+
+	for pos := range t.requested {
+		if !t.inRange(pos) {
+			delete(t.requested, pos)
+			if t.sent[pos] {
+				delete(t.sent, pos)
+				unload = append(unload, pos)
+			}
+		}
+	}
+	pending := t.pending[:0]
+	for _, f := range t.pending {
+		if t.inRange(f.Pos) {
+			pending = append(pending, f)
+		} else {
+			f.Cancel()
+		}
+	}
+	t.pending = pending
+
+	t.queue = t.queue[:0]
+	for x := center.X - int32(viewDistance); x <= center.X+int32(viewDistance); x++ {
+		for z := center.Z - int32(viewDistance); z <= center.Z+int32(viewDistance); z++ {
+			pos := world.ChunkPos{X: x, Z: z}
+			if !t.requested[pos] {
+				t.queue = append(t.queue, pos)
+			}
+		}
+	}
+	sort.SliceStable(t.queue, func(i, j int) bool {
+		return t.distanceSquared(t.queue[i]) < t.distanceSquared(t.queue[j])
+	})
+	return unload
+}
+
+// next returns at most max chunks to request, nearest first, and
+// considers them requested.
+func (t *chunkTracker) next(max int) []world.ChunkPos {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	return t.take(max)
+}
+
+// take is next, without locking.
+func (t *chunkTracker) take(max int) []world.ChunkPos {
+	if max > len(t.queue) {
+		max = len(t.queue)
+	}
+	ret := make([]world.ChunkPos, max)
+	copy(ret, t.queue)
+	t.queue = t.queue[max:]
+	for _, pos := range ret {
+		t.requested[pos] = true
+	}
+	return ret
+}
+
+// request requests the next chunks to send to the given pool of the
+// given world, so that at most maxPendingChunks are pending. The nearest
+// chunks have the highest priority. Does nothing if the tracked world
+// has changed.
+func (t *chunkTracker) request(w *world.World, pool *world.ChunkPool) {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	if t.world != w {
+		return
+	}
+	for _, pos := range t.take(maxPendingChunks - len(t.pending)) {
+		t.pending = append(t.pending, pool.Request(pos.X, pos.Z, int(t.distanceSquared(pos))))
+	}
+}
+
+// ready returns at most max of the requested chunks which are
+// available, nearest first; markSent must be called once they are
+// sent. The chunks which could neither be loaded nor generated are
+// requested again later. Returns nothing if the tracked world has changed.
+func (t *chunkTracker) ready(w *world.World, max int) []*world.Chunk {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	ret := make([]*world.Chunk, 0)
+	if t.world != w {
+		return ret
+	}
+	pending := t.pending[:0]
+	for _, f := range t.pending {
+		if len(ret) < max {
+			if chunk, ok := f.Chunk(); ok {
+				if chunk != nil {
+					ret = append(ret, chunk)
+				} else {
+					delete(t.requested, f.Pos)
+					t.queue = append(t.queue, f.Pos)
+				}
+				continue
+			}
+		}
+		pending = append(pending, f)
+	}
+	t.pending = pending
+	return ret
+}
+
+// markSent considers the given chunk of the given world sent to the client.
+// Returns false if it is not requested anymore (it left the area, or the
+// player left the world, in the meantime), in which case the client must
+// unload it.
+func (t *chunkTracker) markSent(w *world.World, pos world.ChunkPos) bool {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	if t.world != w || !t.requested[pos] {
+		return false
+	}
+	t.sent[pos] = true
+	return true
+}
+
+// forget forgets the chunks requested and sent, and cancels the pending
+// requests, without locking.
+func (t *chunkTracker) forget() {
+	for _, f := range t.pending {
+		f.Cancel()
+	}
+	t.requested = make(map[world.ChunkPos]bool)
+	t.sent = make(map[world.ChunkPos]bool)
+	t.queue = t.queue[:0]
+	t.pending = t.pending[:0]
+}
+
+// isLoaded returns true if the given chunk has been sent to the client.
+func (t *chunkTracker) isLoaded(pos world.ChunkPos) bool {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	return t.sent[pos]
+}
+
+// area returns the world, the center and the radius of the tracked
+// area. Returns false if it has not been set yet.
+func (t *chunkTracker) area() (*world.World, world.ChunkPos, int, bool) {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	return t.world, t.center, t.viewDistance, t.initialized
+}
+
+// inRange returns true if the given chunk is in the tracked area.
+func (t *chunkTracker) inRange(pos world.ChunkPos) bool {
+	dx, dz := pos.X-t.center.X, pos.Z-t.center.Z
+	d := int32(t.viewDistance)
+	return dx >= -d && dx <= d && dz >= -d && dz <= d
+}
+
+// distanceSquared returns the squared distance, in chunks, between
+// the given chunk and the center.
+func (t *chunkTracker) distanceSquared(pos world.ChunkPos) int32 {
+	dx, dz := pos.X-t.center.X, pos.Z-t.center.Z
+	return dx*dx + dz*dz
+}
+
+// chunkPosAt returns the coordinates of the chunk containing the given location.
+func chunkPosAt(x, z float64) world.ChunkPos {
+	return world.ChunkPos{
+		X: int32(math.Floor(x / val.ChunkSize)),
+		Z: int32(math.Floor(z / val.ChunkSize)),
+	}
+}
+
+// viewDistance returns the view distance of the client: the one of the
+// server, reduced to the one of the client's settings if it is lower.
+func (c *Connection) viewDistance() int {
+	distance := c.server.GetViewDistance()
+	if distance <= 0 {
+		distance = defaultViewDistance
+	}
+	if client := int(c.Player.Settings.ViewDistance); client > 0 && client < distance {
+		distance = client
+	}
+	if distance < minViewDistance {
+		distance = minViewDistance
+	}
+	return distance
+}
+
+// updateChunks updates the area of the chunks to send to the client,
+// from its location and its view distance, and unloads the chunks
+// which left it.
+func (c *Connection) updateChunks() {
+	location := c.Player.GetLocation()
+	center := chunkPosAt(float64(location.X), float64(location.Z))
+	for _, pos := range c.chunks.update(location.World, center, c.viewDistance()) {
+		c.WritePacket(&protocol.UnloadChunkPacket{X: pos.X, Z: pos.Z})
+	}
+}
+
+// streamChunks sends the chunks in another routine, so that the ticks wait
+// neither for their encoding nor for the slow clients. Does nothing if the
+// previous ones are still being sent.
+func (c *Connection) streamChunks(max int) {
+	if !atomic.CompareAndSwapInt32(&c.streaming, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&c.streaming, 0)
+		c.sendChunks(max)
+	}()
+}
+
+// sendChunks requests the chunks waiting to be sent to the pool of
+// the tracked world, and sends at most max of those which are ready.
+func (c *Connection) sendChunks(max int) {
+	w, _, _, ok := c.chunks.area()
+	if !ok {
+		return
+	}
+	pool := c.server.GetChunkPool(w)
+	if pool == nil {
+		log.Error("Cannot send the chunks of world", w.Name, "which is not loaded")
+		return
+	}
+	c.chunks.request(w, pool)
+	for _, chunk := range c.chunks.ready(w, max) {
+		c.WritePacket(&protocol.ChunkDataPacket{
+			Chunk:     chunk,
+			FullChunk: true,
+			SkyLight:  w.Dimension.HasSkyLight(),
+		})
+		if pos := (world.ChunkPos{X: chunk.X, Z: chunk.Z}); !c.chunks.markSent(w, pos) {
+			c.WritePacket(&protocol.UnloadChunkPacket{X: pos.X, Z: pos.Z})
+		}
+	}
+}
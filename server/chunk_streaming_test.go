@@ -0,0 +1,210 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/world"
+	"github.com/olsdavis/goelan/world/generator"
+	"testing"
+)
+
+// sendAll requests at most max chunks of the given tracker, and considers them sent.
+func sendAll(tracker *chunkTracker, max int) []world.ChunkPos {
+	chunks := tracker.next(max)
+	for _, pos := range chunks {
+		tracker.markSent(nil, pos)
+	}
+	return chunks
+}
+
+func TestChunkTrackerNearestFirst(t *testing.T) {
+	tracker := newChunkTracker()
+	if unload := tracker.update(nil, world.ChunkPos{X: 3, Z: -2}, 2); len(unload) != 0 {
+		t.Fatal("No chunk should be unloaded at first")
+	}
+	chunks := tracker.next(100)
+	if len(chunks) != 25 {
+		t.Fatalf("Expected 25 chunks, got %v", len(chunks))
+	}
+	if chunks[0] != (world.ChunkPos{X: 3, Z: -2}) {
+		t.Error("The first chunk should be the center, got", chunks[0])
+	}
+	last := int32(0)
+	for _, pos := range chunks {
+		d := tracker.distanceSquared(pos)
+		if d < last {
+			t.Fatal("The chunks are not sorted by distance")
+		}
+		last = d
+	}
+	if len(tracker.next(100)) != 0 {
+		t.Error("The chunks should only be sent once")
+	}
+}
+
+func TestChunkTrackerLimit(t *testing.T) {
+	tracker := newChunkTracker()
+	tracker.update(nil, world.ChunkPos{}, 10)
+	sent := 0
+	for i := 0; i < 100; i++ {
+		chunks := tracker.next(maxChunksPerTick)
+		if len(chunks) > maxChunksPerTick {
+			t.Fatal("Too many chunks have been sent in one tick")
+		}
+		sent += len(chunks)
+	}
+	if sent != 21*21 {
+		t.Errorf("Expected %v chunks, got %v", 21*21, sent)
+	}
+}
+
+func TestChunkTrackerMove(t *testing.T) {
+	tracker := newChunkTracker()
+	tracker.update(nil, world.ChunkPos{}, 1)
+	sendAll(tracker, 100)
+	if tracker.update(nil, world.ChunkPos{}, 1) != nil {
+		t.Error("Nothing should change if the player stays in the same chunk")
+	}
+
+	unload := tracker.update(nil, world.ChunkPos{X: 1}, 1)
+	if len(unload) != 3 {
+		t.Fatalf("Expected 3 chunks to unload, got %v", unload)
+	}
+	for _, pos := range unload {
+		if pos.X != -1 {
+			t.Error("Unexpected unloaded chunk", pos)
+		}
+	}
+	chunks := tracker.next(100)
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 new chunks, got %v", chunks)
+	}
+	for _, pos := range chunks {
+		if pos.X != 2 {
+			t.Error("Unexpected new chunk", pos)
+		}
+	}
+}
+
+func TestChunkTrackerViewDistance(t *testing.T) {
+	tracker := newChunkTracker()
+	tracker.update(nil, world.ChunkPos{}, 3)
+	sendAll(tracker, 100)
+	if unload := tracker.update(nil, world.ChunkPos{}, 2); len(unload) != 7*7-5*5 {
+		t.Errorf("Expected %v chunks to unload, got %v", 7*7-5*5, len(unload))
+	}
+	if len(tracker.next(100)) != 0 {
+		t.Error("No chunk should be sent when reducing the view distance")
+	}
+}
+
+func TestChunkTrackerRequest(t *testing.T) {
+	w := world.NewWorld("test")
+	w.Generator = &generator.FlatGenerator{}
+	pool := world.NewChunkPool(w, 2)
+	defer pool.Close()
+
+	tracker := newChunkTracker()
+	tracker.update(w, world.ChunkPos{}, 10)
+	tracker.request(w, pool)
+	if len(tracker.pending) != maxPendingChunks {
+		t.Fatalf("Expected %v pending chunks, got %v", maxPendingChunks, len(tracker.pending))
+	}
+	for _, f := range tracker.pending {
+		f.Wait()
+	}
+	chunks := tracker.ready(w, maxChunksPerTick)
+	if len(chunks) != maxChunksPerTick {
+		t.Fatalf("Expected %v chunks, got %v", maxChunksPerTick, len(chunks))
+	}
+	if chunks[0].X != 0 || chunks[0].Z != 0 {
+		t.Error("The first chunk should be the center, got", chunks[0].X, chunks[0].Z)
+	}
+	tracker.request(w, pool)
+	if len(tracker.pending) != maxPendingChunks {
+		t.Errorf("The pending chunks should have been refilled, got %v", len(tracker.pending))
+	}
+
+	// the pending chunks out of range are dropped
+	tracker.update(w, world.ChunkPos{X: 100}, 2)
+	if len(tracker.pending) != 0 {
+		t.Error("No chunk should be pending after a teleportation, got", len(tracker.pending))
+	}
+}
+
+func TestChunkTrackerUnsentChunks(t *testing.T) {
+	tracker := newChunkTracker()
+	tracker.update(nil, world.ChunkPos{}, 1)
+	tracker.next(100)
+	if tracker.isLoaded(world.ChunkPos{}) {
+		t.Error("A requested chunk should not be loaded before it is sent")
+	}
+	if !tracker.markSent(nil, world.ChunkPos{}) || !tracker.isLoaded(world.ChunkPos{}) {
+		t.Error("A sent chunk should be loaded")
+	}
+	// only the chunk which has been sent must be unloaded
+	unload := tracker.update(nil, world.ChunkPos{X: 100}, 1)
+	if len(unload) != 1 || unload[0] != (world.ChunkPos{}) {
+		t.Errorf("Expected to unload the sent chunk, got %v", unload)
+	}
+	if tracker.markSent(nil, world.ChunkPos{X: 1}) {
+		t.Error("A chunk which left the area should be unloaded once sent")
+	}
+}
+
+func TestChunkTrackerFailedChunk(t *testing.T) {
+	w := world.NewWorld("test")
+	w.Generator = &generator.FlatGenerator{}
+	pool := world.NewChunkPool(w, 1)
+	// the closed pools complete the requests with a nil chunk
+	pool.Close()
+
+	tracker := newChunkTracker()
+	tracker.update(w, world.ChunkPos{}, 0)
+	tracker.request(w, pool)
+	if chunks := tracker.ready(w, maxChunksPerTick); len(chunks) != 0 {
+		t.Fatal("No chunk should be ready, got", len(chunks))
+	}
+	if tracker.isLoaded(world.ChunkPos{}) {
+		t.Error("A chunk which could not be loaded should not be considered sent")
+	}
+	if chunks := tracker.next(100); len(chunks) != 1 || chunks[0] != (world.ChunkPos{}) {
+		t.Errorf("The chunk which could not be loaded should be requested again, got %v", chunks)
+	}
+}
+
+func TestChunkTrackerWorldChange(t *testing.T) {
+	overworld := world.NewWorld("overworld")
+	nether := world.NewWorld("nether")
+	tracker := newChunkTracker()
+	tracker.update(overworld, world.ChunkPos{}, 1)
+	tracker.next(100)
+	tracker.markSent(overworld, world.ChunkPos{})
+
+	// the same position in another world must be sent again
+	unload := tracker.update(nether, world.ChunkPos{}, 1)
+	if len(unload) != 1 || unload[0] != (world.ChunkPos{}) {
+		t.Errorf("Expected to unload the chunk of the previous world, got %v", unload)
+	}
+	if tracker.isLoaded(world.ChunkPos{}) {
+		t.Error("The chunks of the previous world should be forgotten")
+	}
+	if chunks := tracker.next(100); len(chunks) != 3*3 {
+		t.Errorf("Expected %v chunks to send in the new world, got %v", 3*3, len(chunks))
+	}
+	if tracker.markSent(overworld, world.ChunkPos{}) {
+		t.Error("A chunk of the previous world should be unloaded once sent")
+	}
+}
+
+func TestChunkPosAt(t *testing.T) {
+	cases := map[[2]float64]world.ChunkPos{
+		{0, 0}:        {X: 0, Z: 0},
+		{15.9, 16}:    {X: 0, Z: 1},
+		{-0.5, -16}:   {X: -1, Z: -1},
+		{-16.1, 33.0}: {X: -2, Z: 2},
+	}
+	for location, expected := range cases {
+		if pos := chunkPosAt(location[0], location[1]); pos != expected {
+			t.Errorf("Chunk at %v: expected %v, got %v", location, expected, pos)
+		}
+	}
+}
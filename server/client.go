@@ -1,6 +1,7 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/binary"
 	"github.com/olsdavis/goelan/log"
@@ -10,18 +11,17 @@ import (
 	"io"
 	"net"
 	"sync"
+	"time"
 )
 
-type ConnectionState int
-
 var (
 	emptyArray []byte
 )
 
 const (
-	HandshakeState ConnectionState = iota
-	LoginState
-	PlayState
+	// the number of packets waiting to be sent to a client, beyond
+	// which the routines writing to it wait
+	writeQueueLength = 256
 )
 
 type FullReader struct {
@@ -48,41 +48,73 @@ func (reader FullReader) Read(p []byte) (int, error) {
 // Connection struct represents a connected client.
 type Connection struct {
 	server          *Server
+	address         net.Addr // the remote address of the client
+	socket          net.Conn
 	Writer          io.WriteCloser
 	Reader          FullReader
+	buffered        *bufio.Reader // the reader of the socket, used to peek data
 	writeChan       chan *protocol.RawPacket
 	exitChan        chan int
-	PacketHandler   stateHandler // the handler which depends on player's state
+	closed          chan struct{} // closed once the client is being disconnected
+	PacketHandler   stateHandler  // the handler which depends on player's state
 	ProtocolVersion uint32
-	ConnectionState ConnectionState // current connection's state (handshake, login or play)
+	ConnectionState protocol.ConnectionState // current connection's state (handshake, login or play)
 
 	VerifyToken    []byte // the verify token used in authentication
 	VerifyUsername string // the verify username used in authentication
 	SharedSecret   []byte // used for encrypting and decrypting data
 
+	forwarded *forwardedData // the data forwarded by the proxy (nil if none)
+
+	// the compression thresholds (negative if compression is disabled);
+	// the first one is only used by the reading routine, the second
+	// one by the writing routine
+	readThreshold  int
+	writeThreshold int
+
 	Player *player.Player
 
 	PendingKeepAlives            *PendingList
 	PendingTeleportConfirmations *PendingList
 
+	chunks *chunkTracker // the chunks sent to the client
+
+	// the packets received during the current second (only used by the reading routine)
+	packetCount  int
+	packetWindow time.Time
+
+	// the block being broken in survival mode, nil if none (only used by the reading routine)
+	digging *diggingState
+
+	pending   bool  // true until the client plays (only used by the reading routine)
+	streaming int32 // 1 while chunks are being sent (see streamChunks)
+	closing   bool  // true once Disconnect has been called
 	connected bool
 	sync.Mutex
 }
 
 // NewConnection creates a new connection from the given ReadWriter.
 func NewConnection(socket net.Conn, server *Server) *Connection {
+	buffered := bufio.NewReader(socket)
 	return &Connection{
 		server:                       server,
+		address:                      socket.RemoteAddr(),
+		socket:                       socket,
 		Writer:                       socket,
-		Reader:                       NewFullReader(socket),
-		writeChan:                    make(chan *protocol.RawPacket),
+		Reader:                       NewFullReader(buffered),
+		buffered:                     buffered,
+		writeChan:                    make(chan *protocol.RawPacket, writeQueueLength),
 		exitChan:                     make(chan int, 1),
-		ConnectionState:              HandshakeState,
+		closed:                       make(chan struct{}),
+		ConnectionState:              protocol.HandshakeState,
 		VerifyToken:                  emptyArray,
 		VerifyUsername:               "",
+		readThreshold:                -1,
+		writeThreshold:               -1,
 		Player:                       nil,
 		PendingKeepAlives:            NewPendingList(),
 		PendingTeleportConfirmations: NewPendingList(),
+		chunks:                       newChunkTracker(),
 		connected:                    true,
 		Mutex:                        sync.Mutex{},
 	}
@@ -100,22 +132,94 @@ func (c *Connection) Next() (*protocol.RawPacket, error) {
 		return nil, nil
 	}
 
+	if max := c.server.limits.maxFrameSize(c.ConnectionState); size > max {
+		return nil, protocol.NewProtocolError("packet is too long (%v bytes, max %v)", size, max)
+	}
+
 	buffer := make([]byte, size)
 	_, err = io.ReadAtLeast(c.Reader, buffer, int(size))
 	if err != nil {
 		return nil, err
 	}
+	if c.readThreshold >= 0 {
+		buffer, err = c.uncompress(buffer)
+		if err != nil {
+			return nil, protocol.NewProtocolError("%v", err)
+		}
+	}
 	id, offset := binary.Uvarint(buffer)
+	if offset <= 0 {
+		return nil, protocol.NewProtocolError("invalid packet ID")
+	}
 	rawPacket := protocol.NewRawPacket(id, buffer[offset:], nil)
 	return rawPacket, nil
 }
 
-// Write enqueues the given packet to the current connection.
+// uncompress reads the data length of the given compressed frame,
+// and returns its (uncompressed) content.
+func (c *Connection) uncompress(frame []byte) ([]byte, error) {
+	dataLength, offset := binary.Uvarint(frame)
+	if offset <= 0 {
+		return nil, protocol.BadlyCompressedError
+	}
+	// not compressed
+	if dataLength == 0 {
+		return frame[offset:], nil
+	}
+	if dataLength < uint64(c.readThreshold) {
+		return nil, protocol.BadlyCompressedError
+	}
+	return protocol.Decompress(frame[offset:], int(dataLength))
+}
+
+// EnableCompression sends the Set Compression packet to the client, and
+// compresses, from then on, the packets which are at least as long as the
+// given threshold. Must be called from the reading routine.
+func (c *Connection) EnableCompression(threshold int) {
+	if threshold < 0 {
+		return
+	}
+	packet, err := protocol.Marshal(c.ProtocolVersion, c.ConnectionState, protocol.Clientbound, &protocol.SetCompressionPacket{
+		Threshold: int32(threshold),
+	})
+	if err != nil {
+		log.Error("Could not enable compression:", err)
+		return
+	}
+	// the client compresses its packets once it receives this one
+	packet.Callback = func() {
+		c.writeThreshold = threshold
+	}
+	c.readThreshold = threshold
+	c.Write(packet)
+}
+
+// Write enqueues the given packet to the current connection. The
+// packet is dropped if the client is being disconnected.
 func (c *Connection) Write(packet *protocol.RawPacket) {
 	if packet == nil {
 		return
 	}
-	c.writeChan <- packet
+	select {
+	case <-c.closed:
+		return
+	default:
+	}
+	select {
+	case c.writeChan <- packet:
+	case <-c.closed:
+	}
+}
+
+// WritePacket encodes the given packet with the ID it has in the
+// current connection's state, and enqueues it.
+func (c *Connection) WritePacket(packet protocol.Packet) {
+	raw, err := protocol.Marshal(c.ProtocolVersion, c.ConnectionState, protocol.Clientbound, packet)
+	if err != nil {
+		log.Error("Could not write packet:", err)
+		return
+	}
+	c.Write(raw)
 }
 
 // write receives packets from the writeChan channel and sends
@@ -135,7 +239,13 @@ func (c *Connection) write() {
 				continue
 			}
 
-			_, err := c.Writer.Write(toByteArray(packet))
+			data, err := toByteArray(packet, c.writeThreshold)
+			if err != nil {
+				log.Error("Could not compress a packet:", err)
+				break
+			}
+
+			_, err = c.Writer.Write(data)
 
 			// omit this error
 			if err != nil {
@@ -150,12 +260,68 @@ func (c *Connection) write() {
 }
 
 // Creates a byte array from the given raw packet. Releases the packet at the end.
-func toByteArray(packet *protocol.RawPacket) []byte {
+// If threshold is positive or zero, the packet is framed with its data length,
+// and compressed if it is at least threshold bytes long.
+func toByteArray(packet *protocol.RawPacket, threshold int) ([]byte, error) {
 	send := new(bytes.Buffer)
 	send.Write(protocol.Uvarint(uint32(packet.ID)))
 	send.Write(packet.Data.Buf)
 	packet.Release()
-	return append(protocol.Uvarint(uint32(send.Len())), send.Bytes()...)
+	if threshold >= 0 {
+		frame := new(bytes.Buffer)
+		if send.Len() >= threshold {
+			compressed, err := protocol.Compress(send.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			frame.Write(protocol.Uvarint(uint32(send.Len())))
+			frame.Write(compressed)
+		} else {
+			frame.Write(protocol.Uvarint(0))
+			frame.Write(send.Bytes())
+		}
+		send = frame
+	}
+	return append(protocol.Uvarint(uint32(send.Len())), send.Bytes()...), nil
+}
+
+// RemoteAddr returns the remote address of the client.
+func (c *Connection) RemoteAddr() net.Addr {
+	return c.address
+}
+
+// RemoteIP returns the IP address of the client.
+func (c *Connection) RemoteIP() string {
+	return addressIP(c.address)
+}
+
+// addressIP returns the IP of the given address, without its port.
+func addressIP(addr net.Addr) string {
+	if tcp, ok := addr.(*net.TCPAddr); ok {
+		return tcp.IP.String()
+	}
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+	return addr.String()
+}
+
+// setForwardedAddress replaces the remote address of the client
+// by its real address, forwarded by a proxy.
+func (c *Connection) setForwardedAddress(ip net.IP) {
+	port := 0
+	if addr, ok := c.address.(*net.TCPAddr); ok {
+		port = addr.Port
+	}
+	c.address = &net.TCPAddr{IP: ip, Port: port}
+}
+
+// finishPending signals to the throttle that the client is not pending anymore.
+func (c *Connection) finishPending() {
+	if c.pending {
+		c.pending = false
+		c.server.throttle.loginDone()
+	}
 }
 
 // GetServer returns client's server.
@@ -185,71 +351,64 @@ func (c *Connection) SetConnected(b bool) {
 // - DefaultMessageMode (mode 1): what you should use (system messages);
 // - ActionBarMode (mode 2): if you want to send messages above the hotbar, use this mode.
 func (c *Connection) SendMessage(message string, mode protocol.MessageMode) {
-	response := protocol.NewResponse()
-	response.WriteJSON(protocol.ChatComponent{Text: message})
-	response.WriteUnsignedByte(byte(mode))
-	c.Write(response.ToRawPacket(protocol.OutgoingChatPacketId))
+	c.WritePacket(&protocol.ChatPacket{
+		Message:  protocol.ChatComponent{Text: message},
+		Position: mode,
+	})
 }
 
 // AddPlayers sends to the current client the packet which adds
 // to his player list the given players.
 func (c *Connection) AddPlayers(players []*player.Player) {
-	packet := protocol.NewResponse()
-	packet.WriteByte(protocol.PlayerListItemActionAddPlayer)
-	packet.WriteUVarint(uint32(len(players)))
-	for _, pl := range players {
+	packet := &protocol.PlayerListItemPacket{
+		Action:  protocol.PlayerListItemActionAddPlayer,
+		Players: make([]protocol.PlayerListEntry, len(players)),
+	}
+	for i, pl := range players {
 		profile := pl.Profile
-
-		packet.WriteUUID(*profile.RealUUID)
-		packet.WriteString(profile.Name)
-		packet.WriteVarint(int32(len(profile.Properties)))
-		for _, property := range profile.Properties {
-			packet.WriteString(property.Name)
-			packet.WriteString(property.Value)
-			if property.Signature == "" {
-				packet.WriteBoolean(false)
-			} else {
-				packet.WriteBoolean(true)
-				packet.WriteString(property.Signature)
-			}
+		packet.Players[i] = protocol.PlayerListEntry{
+			UUID:        *profile.RealUUID,
+			Name:        profile.Name,
+			Properties:  profile.Properties,
+			GameMode:    int32(pl.GameMode),
+			Ping:        0,
+			DisplayName: &protocol.ChatComponent{Text: profile.Name},
 		}
-		packet.WriteVarint(int32(pl.GameMode))
-		packet.WriteVarint(0)
-		packet.WriteBoolean(true)
-		packet.WriteJSON(protocol.ChatComponent{Text: profile.Name})
 	}
-	c.Write(packet.ToRawPacket(protocol.PlayerListItemPacketId))
+	c.WritePacket(packet)
 }
 
 // Disconnect disconnects the current client for the given reason. (May be empty.)
 func (c *Connection) Disconnect(reason string) {
-	if !c.IsConnected() {
+	c.Lock()
+	if !c.connected || c.closing {
+		c.Unlock()
 		return
 	}
+	c.closing = true
+	c.Unlock()
 
-	if reason != "" {
-		var packetId uint64
-
-		switch c.ConnectionState {
-		case PlayState:
-			packetId = protocol.KickPlayerPacketId
-		case LoginState:
-			packetId = protocol.LoginStateDisconnectPacketId
-		}
-
-		response := protocol.NewResponse()
-		response.WriteJSON(protocol.ChatComponent{Text: reason})
-		rp := response.ToRawPacket(packetId)
-		// waits the packet to be send; it prevents us from writing messages
-		// while the socket is being closed
-		done := make(chan int, 1)
-		rp.Callback = func() {
-			close(done)
+	// the handshake state has no disconnect packet
+	if reason != "" && c.ConnectionState != protocol.HandshakeState {
+		rp, err := protocol.Marshal(c.ProtocolVersion, c.ConnectionState, protocol.Clientbound, &protocol.DisconnectPacket{
+			Reason: protocol.ChatComponent{Text: reason},
+		})
+		if err != nil {
+			log.Error("Could not write disconnect packet:", err)
+		} else {
+			// waits the packet to be send; it prevents us from writing messages
+			// while the socket is being closed
+			done := make(chan int, 1)
+			rp.Callback = func() {
+				close(done)
+			}
+			c.Write(rp)
+			<-done
 		}
-		c.Write(rp)
-		<-done
 	}
 
+	// the packets written by the other routines are dropped from now on
+	close(c.closed)
 	c.exitChan <- 0
 	err := c.Writer.Close()
 	c.SetConnected(false)
@@ -257,6 +416,4 @@ func (c *Connection) Disconnect(reason string) {
 	if err != nil {
 		log.Error("Could not properly close the connection with a client:", err)
 	}
-
-	close(c.writeChan)
 }
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/protocol"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWriteAfterDisconnect(t *testing.T) {
+	server := &Server{limits: newConnectionLimits(defaultProperties())}
+	client, socket := net.Pipe()
+	defer client.Close()
+	c := NewConnection(socket, server)
+	c.ConnectionState = protocol.PlayState
+	c.Disconnect("")
+
+	done := make(chan struct{})
+	go func() {
+		// more than the queue, which nobody reads anymore
+		for i := 0; i < 2*writeQueueLength; i++ {
+			c.WritePacket(&protocol.KeepAlivePacket{ID: int64(i)})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Writing to a disconnected client should not block")
+	}
+	// disconnecting twice does nothing
+	c.Disconnect("")
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/player"
+	"github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/util"
+	"net"
+	"strings"
+)
+
+// This file handles the data forwarded by the proxies (BungeeCord only),
+// which authenticate the players and give us their real address and profile.
+
+// ForwardingMode represents the way the proxy in front of the server forwards
+// the players' data.
+type ForwardingMode string
+
+const (
+	NoForwarding         ForwardingMode = "none"       // the players connect directly
+	BungeeCordForwarding ForwardingMode = "bungeecord" // legacy forwarding, in the handshake's server address
+	// Velocity's modern forwarding needs the login plugin messages of 1.13+,
+	// so it is not supported; it is only named to be rejected clearly
+	velocityForwarding ForwardingMode = "velocity"
+)
+
+const bungeeCordRequiredMessage = "If you wish to use IP forwarding, please enable it in your BungeeCord config as well!"
+
+// UnmarshalText reads the forwarding mode of the properties file, and
+// rejects the modes which are not supported.
+func (m *ForwardingMode) UnmarshalText(text []byte) error {
+	switch mode := ForwardingMode(text); mode {
+	case NoForwarding, BungeeCordForwarding:
+		*m = mode
+		return nil
+	case velocityForwarding:
+		return fmt.Errorf("velocity forwarding needs 1.13 or later, and the server only supports %v: use bungeecord instead",
+			supportedVersionsName())
+	default:
+		return fmt.Errorf("unknown forwarding mode %q (none or bungeecord)", mode)
+	}
+}
+
+// forwardedData struct represents the data forwarded by a proxy.
+type forwardedData struct {
+	Address    net.IP
+	UUID       string // without hyphens
+	Name       string // empty if not forwarded
+	Properties []player.Property
+}
+
+// parseBungeeCordAddress parses the server address of a handshake sent by
+// BungeeCord: "host\x00ip\x00uuid\x00properties", the properties being optional.
+func parseBungeeCordAddress(address string) (*forwardedData, error) {
+	parts := strings.Split(address, "\x00")
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, fmt.Errorf("no forwarded data")
+	}
+	ip := net.ParseIP(parts[1])
+	if ip == nil {
+		return nil, fmt.Errorf("invalid forwarded address %q", parts[1])
+	}
+	uuid := strings.Replace(parts[2], "-", "", -1)
+	if len(uuid) != 32 {
+		return nil, fmt.Errorf("invalid forwarded UUID %q", parts[2])
+	}
+	data := &forwardedData{Address: ip, UUID: uuid}
+	if len(parts) == 4 {
+		if err := json.Unmarshal([]byte(parts[3]), &data.Properties); err != nil {
+			return nil, fmt.Errorf("invalid forwarded properties: %v", err)
+		}
+	}
+	return data, nil
+}
+
+// handleForwardedHandshake reads the data forwarded in the server address,
+// if the server is behind BungeeCord. Disconnects the client if the data is missing.
+func handleForwardedHandshake(address string, sender *Connection) error {
+	if sender.GetServer().GetForwardingMode() != BungeeCordForwarding {
+		if len(address) > protocol.MaxServerAddressLength {
+			return protocol.NewProtocolError("server address is too long (%v)", len(address))
+		}
+		return nil
+	}
+	data, err := parseBungeeCordAddress(address)
+	if err != nil {
+		log.Warn("Rejecting", sender.RemoteAddr(), "not forwarded by BungeeCord:", err)
+		sender.Disconnect(bungeeCordRequiredMessage)
+		return nil
+	}
+	sender.forwarded = data
+	sender.setForwardedAddress(data.Address)
+	return nil
+}
+
+// loginForwarded logs in the player which data has been forwarded
+// by the proxy. (The proxy has already authenticated the player.)
+func loginForwarded(sender *Connection, username string) {
+	data := sender.forwarded
+	if data.Name != "" {
+		username = data.Name
+	}
+	uuid, err := util.StringToUUID(util.ToHyphenUUID(data.UUID))
+	if err != nil {
+		sender.Disconnect("Error encountered during connection: " + err.Error())
+		return
+	}
+	profile := player.PlayerProfile{
+		RealUUID:   uuid,
+		UUID:       data.UUID,
+		Name:       username,
+		Properties: data.Properties,
+	}
+	initializePlayer(profile, sender)
+	processLogin(sender, &sender.Player.Profile, nil)
+}
+
+// GetForwardingMode returns the way the proxy in front of the server
+// forwards the players' data.
+func (s *Server) GetForwardingMode() ForwardingMode {
+	return s.properties.ForwardingMode
+}
+
+// checkForwarding checks the forwarding properties. (The properties
+// file cannot set an unknown mode, see ForwardingMode.UnmarshalText.)
+func (s *Server) checkForwarding() {
+	switch s.properties.ForwardingMode {
+	case NoForwarding:
+	case BungeeCordForwarding:
+		log.Info("Players must connect through BungeeCord.")
+	default:
+		log.Warn(fmt.Sprintf("Unknown forwarding mode %q, players will connect directly.", s.properties.ForwardingMode))
+		s.properties.ForwardingMode = NoForwarding
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"github.com/BurntSushi/toml"
+	"testing"
+)
+
+func TestParseBungeeCordAddress(t *testing.T) {
+	address := "localhost\x00192.168.1.2\x00069a79f444e94726a5befca90e38aaf5\x00" +
+		`[{"name":"textures","value":"skin","signature":"sig"}]`
+	data, err := parseBungeeCordAddress(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Address.String() != "192.168.1.2" {
+		t.Error("Address should be 192.168.1.2, currently:", data.Address)
+	}
+	if data.UUID != "069a79f444e94726a5befca90e38aaf5" {
+		t.Error("UUID should be 069a79f444e94726a5befca90e38aaf5, currently:", data.UUID)
+	}
+	if len(data.Properties) != 1 || data.Properties[0].Value != "skin" || data.Properties[0].Signature != "sig" {
+		t.Errorf("Properties should contain the skin, currently: %+v", data.Properties)
+	}
+
+	if _, err := parseBungeeCordAddress("localhost"); err == nil {
+		t.Error("An address without forwarded data should be rejected")
+	}
+}
+
+func TestForwardingModeProperty(t *testing.T) {
+	var properties ServerProperties
+	if _, err := toml.Decode(`forwarding-mode = "bungeecord"`, &properties); err != nil {
+		t.Fatal(err)
+	}
+	if properties.ForwardingMode != BungeeCordForwarding {
+		t.Error("Forwarding mode should be bungeecord, currently:", properties.ForwardingMode)
+	}
+	for _, mode := range []string{"velocity", "unknown"} {
+		if _, err := toml.Decode(`forwarding-mode = "`+mode+`"`, &properties); err == nil {
+			t.Errorf("The %v forwarding mode should be rejected", mode)
+		}
+	}
+}
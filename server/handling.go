@@ -5,28 +5,39 @@ import (
 	. "github.com/olsdavis/goelan/protocol"
 )
 
-type PacketHandler func(packet *RawPacket, sender *Connection)
+// PacketHandler handles a decoded packet. The returned error is a violation
+// of the protocol by the sender, who is then disconnected.
+type PacketHandler func(packet Packet, sender *Connection) error
 
 var (
 	handlers map[ConnectionState]stateHandler
 )
 
 type stateHandler interface {
-	callHandler(packet *RawPacket, sender *Connection)
+	callHandler(packet *RawPacket, sender *Connection) error
 }
 
 type stateMapHandler struct {
+	state    ConnectionState
 	handlers map[uint64]PacketHandler
 }
 
-func (handler stateMapHandler) callHandler(packet *RawPacket, sender *Connection) {
-	h, ok := handler.handlers[packet.ID]
-	if ok {
-		h(packet, sender)
-	} else {
+// callHandler decodes the given packet, and calls the handler associated to its ID.
+// (Handlers are associated to the IDs of the latest version.)
+// Returns an error if the packet could not be decoded or handled.
+func (handler stateMapHandler) callHandler(packet *RawPacket, sender *Connection) error {
+	id, ok := ToLatestID(sender.ProtocolVersion, handler.state, Serverbound, packet.ID)
+	h, handled := handler.handlers[id]
+	if !ok || !handled {
 		log.Debug("Unhandled ID:", packet.ID)
 		log.Debug("Unhandled Data:", packet.Data.Buf)
+		return nil
+	}
+	decoded, err := Unmarshal(sender.ProtocolVersion, handler.state, Serverbound, packet)
+	if err != nil {
+		return err
 	}
+	return h(decoded, sender)
 }
 
 func init() {
@@ -34,18 +45,21 @@ func init() {
 
 	// registers packet handlers
 	handlers[HandshakeState] = stateMapHandler{
+		HandshakeState,
 		map[uint64]PacketHandler{
 			HandshakePacketId: handshakeHandler,
 			PingPacketId:      pingPongHandler,
 		},
 	}
 	handlers[LoginState] = stateMapHandler{
+		LoginState,
 		map[uint64]PacketHandler{
 			LoginStartPacketId:         loginStartHandler,
 			EncryptionResponsePacketId: encryptionResponseHandler,
 		},
 	}
 	handlers[PlayState] = stateMapHandler{
+		PlayState,
 		map[uint64]PacketHandler{
 			PluginMessagePacketId:                 pluginMessageHandler,
 			KeepAliveIncomingPacketId:             keepAliveHandler,
@@ -53,10 +67,16 @@ func init() {
 			ClientStatusPacketId:                  clientStatusHandler,
 			IncomingChatPacketId:                  chatMessageHandler,
 			TeleportConfirmPacketId:               teleportConfirmHandler,
+			IncomingPlayerPositionPacketId:        playerPositionHandler,
 			IncomingPlayerPositionAndLookPacketId: playerPositionAndLookHandler,
+			IncomingPlayerLookPacketId:            playerLookHandler,
 			IncomingAnimationPacketId:             animationHandler,
 			ClickWindowPacketId:                   clickWindowHandler,
 			CloseWindowPacketId:                   closeWindowHandler,
+			PlayerDiggingPacketId:                 playerDiggingHandler,
+			PlayerBlockPlacementPacketId:          playerBlockPlacementHandler,
+			HeldItemChangePacketId:                heldItemChangeHandler,
+			CreativeInventoryActionPacketId:       creativeInventoryActionHandler,
 		},
 	}
 }
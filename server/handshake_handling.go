@@ -1,25 +1,22 @@
 package server
 
 import (
-	. "github.com/olsdavis/goelan/protocol"
 	"github.com/olsdavis/goelan/log"
+	. "github.com/olsdavis/goelan/protocol"
+	"time"
 )
 
 // This file contains all the handlers for the handshake state.
 
 // Handles the handshake.
-func handshakeHandler(packet *RawPacket, sender *Connection) {
-	sender.ProtocolVersion = packet.ReadUnsignedVarint()
-	// omit the following data, we don't need it
-	packet.ReadStringMax(255)
-	packet.ReadUnsignedShort()
-	// end
-	nextState := packet.ReadUnsignedVarint()
+func handshakeHandler(packet Packet, sender *Connection) error {
+	handshake := packet.(*HandshakePacket)
+	sender.ProtocolVersion = handshake.ProtocolVersion
+	nextState := handshake.NextState
 	switch nextState {
 	// Status (server list)
 	case HandshakeStatusNextState:
-		response := NewResponse()
-		version := sender.GetServer().GetServerVersion()
+		version := sender.GetServer().GetServerVersionFor(sender.ProtocolVersion)
 		list := ServerListPing{
 			Ver: Version{Name: version.Name, Protocol: version.ProtocolVersion},
 			Pl: Players{Max: sender.GetServer().GetMaxPlayers(),
@@ -30,22 +27,27 @@ func handshakeHandler(packet *RawPacket, sender *Connection) {
 		if sender.GetServer().HasFavicon() {
 			list.Fav = sender.GetServer().GetFavicon()
 		}
-		response.WriteJSON(list)
-		sender.Write(response.ToRawPacket(HandshakePacketId))
+		sender.WritePacket(&StatusResponsePacket{Status: list})
 		// Login (wants to play)
 	case HandshakeLoginNextState:
 		sender.ConnectionState = LoginState
 		AssignHandler(sender)
+		if err := handleForwardedHandshake(handshake.ServerAddress, sender); err != nil || !sender.IsConnected() {
+			return err
+		}
+		if !sender.GetServer().throttle.allowLogin(sender.RemoteIP(), time.Now()) {
+			log.Info("Throttling login from", sender.RemoteAddr())
+			sender.Disconnect(throttledMessage)
+		}
 		// Unknown
 	default:
-		log.Error("Client handshake next state:", nextState)
+		return NewProtocolError("unknown handshake next state %v", nextState)
 	}
+	return nil
 }
 
 // Handles the ping packet. Sends back a pong packet with the received payload.
-func pingPongHandler(packet *RawPacket, sender *Connection) {
-	payload := packet.ReadLong()
-	response := NewResponse()
-	response.WriteLong(payload)
-	sender.Write(response.ToRawPacket(PingPacketId))
+func pingPongHandler(packet Packet, sender *Connection) error {
+	sender.WritePacket(&PingPacket{Payload: packet.(*PingPacket).Payload})
+	return nil
 }
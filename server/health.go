@@ -0,0 +1,53 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/protocol"
+)
+
+// This file hurts the players, and respawns the ones who died.
+
+// sendHealth sends the health and the food of the player.
+func (c *Connection) sendHealth() {
+	health, food, saturation := c.Player.GetHealth()
+	c.WritePacket(&protocol.UpdateHealthPacket{Health: health, Food: food, Saturation: saturation})
+}
+
+// Damage hurts the player by the given amount. If the player has no health
+// left, it dies: the given message is broadcast, and its client shows the
+// death screen until it respawns.
+func (c *Connection) Damage(amount float32, deathMessage string) {
+	pl := c.Player
+	if !pl.Hurt(amount) {
+		return
+	}
+	c.sendHealth()
+	if !pl.IsDead() {
+		return
+	}
+	c.WritePacket(&protocol.CombatEventPacket{
+		Event:    protocol.CombatEventEntityDead,
+		PlayerID: 0, // the entity ID of the Join Game packet
+		KillerID: -1,
+		Message:  protocol.ChatComponent{Text: deathMessage},
+	})
+	log.Info(deathMessage)
+	c.server.BroadcastMessage(deathMessage, protocol.DefaultMessageMode)
+}
+
+// respawn moves the dead player to the spawn of the default world,
+// with its health restored.
+func (c *Connection) respawn() {
+	pl := c.Player
+	if !pl.Revive() {
+		return
+	}
+	spawn := c.server.GetWorld().GetSpawnLocation()
+	// the client leaves the death screen when it receives a Respawn
+	// packet, which Teleport only sends if the world changes
+	if spawn.World == pl.GetLocation().World {
+		c.WritePacket(respawnPacket(spawn.World, pl))
+	}
+	c.Teleport(spawn)
+	c.sendHealth()
+}
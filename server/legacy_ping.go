@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/protocol"
+	"unicode/utf16"
+)
+
+// This file handles the server list ping of the clients
+// which are older than 1.7 (before the Netty rewrite).
+
+const (
+	legacyPingPacketId = 0xFE
+	legacyKickPacketId = 0xFF
+	// the payload which follows the ping since 1.4
+	legacyPingPayload = 0x01
+	// the plugin message which follows the payload since 1.6
+	legacyPluginMessageId = 0xFA
+	// the protocol version sent to legacy clients, so that they
+	// know that they cannot connect
+	legacyProtocolVersion = 127
+)
+
+// the kinds of the first bytes sent by a client
+const (
+	notLegacyPing  = iota // a modern handshake
+	legacyPingBeta        // beta 1.8 to 1.3: 0xFE alone
+	legacyPing            // 1.4 to 1.6: 0xFE 0x01, followed by MC|PingHost since 1.6
+)
+
+// legacyPingChannel is the plugin channel of the ping of the clients 1.6.
+var legacyPingChannel = utf16.Encode([]rune("MC|PingHost"))
+
+// handleLegacyPing answers the client if it sent a legacy ping. Returns
+// true if it was a legacy ping; the connection must then be closed.
+func (s *Server) handleLegacyPing(c *Connection) bool {
+	var response []byte
+	switch legacyPingKind(c.buffered) {
+	case notLegacyPing:
+		return false
+	case legacyPing:
+		response = legacyPingResponse(s.GetServerVersion().Name, s.GetMotd(), s.GetOnlinePlayersCount(), s.GetMaxPlayers())
+	default:
+		response = legacyKickPacket(fmt.Sprintf("%v§%v§%v", s.GetMotd(), s.GetOnlinePlayersCount(), s.GetMaxPlayers()))
+	}
+	if _, err := c.Writer.Write(response); err != nil {
+		log.Debug("Could not answer legacy ping:", err)
+	}
+	return true
+}
+
+// legacyPingKind returns the kind of the first bytes of the given reader,
+// without consuming them. As vanilla does, a legacy ping is only detected
+// from the bytes received with its first byte: the length of a modern
+// handshake of 254 bytes or more also starts with 0xFE 0x01.
+func legacyPingKind(r *bufio.Reader) int {
+	first, err := r.Peek(1)
+	if err != nil || first[0] != legacyPingPacketId {
+		return notLegacyPing
+	}
+	data, _ := r.Peek(r.Buffered())
+	switch {
+	case len(data) == 1:
+		return legacyPingBeta
+	case data[1] != legacyPingPayload:
+		return notLegacyPing
+	case len(data) == 2:
+		return legacyPing
+	case data[2] != legacyPluginMessageId:
+		return notLegacyPing
+	}
+	// 0xFE 0x01 0xFA, then the length of the channel and its UTF-16 encoding
+	header := 3 + 2 + 2*len(legacyPingChannel)
+	if data, err = r.Peek(header); err != nil {
+		return notLegacyPing
+	}
+	if int(protocol.ByteOrder.Uint16(data[3:])) != len(legacyPingChannel) {
+		return notLegacyPing
+	}
+	for i, c := range legacyPingChannel {
+		if protocol.ByteOrder.Uint16(data[5+2*i:]) != c {
+			return notLegacyPing
+		}
+	}
+	return legacyPing
+}
+
+// legacyPingResponse creates the kick packet which answers the
+// pings of the clients from 1.4 to 1.6.
+func legacyPingResponse(version, motd string, online, max uint) []byte {
+	return legacyKickPacket(fmt.Sprintf("§1\x00%v\x00%v\x00%v\x00%v\x00%v",
+		legacyProtocolVersion, version, motd, online, max))
+}
+
+// legacyKickPacket creates a legacy kick packet with the given
+// reason: its length in characters, and then its UTF-16 encoding.
+func legacyKickPacket(reason string) []byte {
+	encoded := utf16.Encode([]rune(reason))
+	buf := new(bytes.Buffer)
+	buf.WriteByte(legacyKickPacketId)
+	binary.Write(buf, protocol.ByteOrder, uint16(len(encoded)))
+	binary.Write(buf, protocol.ByteOrder, encoded)
+	return buf.Bytes()
+}
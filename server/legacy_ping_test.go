@@ -0,0 +1,55 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/olsdavis/goelan/protocol"
+	"testing"
+)
+
+func TestLegacyPingResponse(t *testing.T) {
+	expected := []byte{
+		0xFF, 0x00, 0x15, // kick packet, 21 characters
+		0x00, 0xA7, 0x00, '1', 0x00, 0x00, // §1
+		0x00, '1', 0x00, '2', 0x00, '7', 0x00, 0x00, // protocol
+		0x00, '1', 0x00, '.', 0x00, '1', 0x00, '2', 0x00, 0x00, // version
+		0x00, 'M', 0x00, 'O', 0x00, 'T', 0x00, 'D', 0x00, 0x00, // motd
+		0x00, '3', 0x00, 0x00, // online players
+		0x00, '2', 0x00, '0', // max players
+	}
+	if response := legacyPingResponse("1.12", "MOTD", 3, 20); !bytes.Equal(expected, response) {
+		t.Errorf("Legacy ping response should be:\n%x\nCurrently:\n%x", expected, response)
+	}
+}
+
+func TestLegacyPingKind(t *testing.T) {
+	ping16 := []byte{0xFE, 0x01, 0xFA, 0x00, 0x0B}
+	for _, c := range "MC|PingHost" {
+		ping16 = append(ping16, 0x00, byte(c))
+	}
+	ping16 = append(ping16, 0x00, 0x07) // the rest of the ping is not read
+
+	// a modern handshake of 300 bytes: its length starts with 0xFE 0x01
+	handshake := append(protocol.Varint(300), make([]byte, 300)...)
+
+	cases := []struct {
+		data     []byte
+		expected int
+	}{
+		{[]byte{0xFE}, legacyPingBeta},
+		{[]byte{0xFE, 0x01}, legacyPing},
+		{ping16, legacyPing},
+		{handshake, notLegacyPing},
+		{[]byte{0xFE, 0x01, 0xFA, 0x00, 0x0B, 0x00, 'M'}, notLegacyPing},
+		{[]byte{0x10, 0x00}, notLegacyPing},
+	}
+	for _, c := range cases {
+		r := bufio.NewReader(bytes.NewReader(c.data))
+		if kind := legacyPingKind(r); kind != c.expected {
+			t.Errorf("Kind of %x: expected %v, got %v", c.data, c.expected, kind)
+		}
+		if r.Buffered() != len(c.data) {
+			t.Errorf("The bytes of %x should not have been consumed", c.data)
+		}
+	}
+}
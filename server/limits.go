@@ -0,0 +1,110 @@
+package server
+
+import (
+	"fmt"
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/protocol"
+	"net"
+	"time"
+)
+
+// This file limits what a single client can send, so that it cannot
+// allocate huge buffers, hold a connection open or flood the server.
+
+const (
+	// the maximal length of a frame in the protocol (3-byte VarInt)
+	maxFrameSize = 2097151
+
+	loginTimedOutMessage   = "Took too long to log in."
+	tooManyPacketsMessage  = "You are sending too many packets!"
+	packetRateWindowLength = time.Second
+)
+
+// connectionLimits struct contains the limits applied to every connection.
+type connectionLimits struct {
+	maxFrameSizes       map[protocol.ConnectionState]uint64 // by state
+	loginTimeout        time.Duration                       // the maximal duration of the handshake and the login (0 if none)
+	maxPacketsPerSecond int                                 // the maximal number of packets per second in play state (0 if unlimited)
+}
+
+// newConnectionLimits creates the limits from the given properties.
+func newConnectionLimits(properties ServerProperties) connectionLimits {
+	return connectionLimits{
+		maxFrameSizes: map[protocol.ConnectionState]uint64{
+			protocol.HandshakeState: frameSizeLimit(properties.MaxHandshakeFrameSize),
+			protocol.LoginState:     frameSizeLimit(properties.MaxLoginFrameSize),
+			protocol.PlayState:      frameSizeLimit(properties.MaxPlayFrameSize),
+		},
+		loginTimeout:        time.Duration(properties.LoginTimeout) * time.Second,
+		maxPacketsPerSecond: properties.MaxPacketsPerSecond,
+	}
+}
+
+// frameSizeLimit returns the given limit, or the limit of the protocol
+// if the given one is not set or higher.
+func frameSizeLimit(limit int) uint64 {
+	if limit <= 0 || limit > maxFrameSize {
+		return maxFrameSize
+	}
+	return uint64(limit)
+}
+
+// maxFrameSize returns the maximal length of a frame in the given state.
+func (l connectionLimits) maxFrameSize(state protocol.ConnectionState) uint64 {
+	if size, ok := l.maxFrameSizes[state]; ok {
+		return size
+	}
+	return maxFrameSize
+}
+
+// startLoginTimeout sets the deadline before which the client
+// must have logged in.
+func (c *Connection) startLoginTimeout() {
+	if timeout := c.server.limits.loginTimeout; timeout > 0 {
+		c.socket.SetReadDeadline(time.Now().Add(timeout))
+	}
+}
+
+// stopLoginTimeout removes the deadline once the client has logged in.
+// (Keep alives take over in play state.)
+func (c *Connection) stopLoginTimeout() {
+	if c.server.limits.loginTimeout > 0 {
+		c.socket.SetReadDeadline(time.Time{})
+	}
+}
+
+// countPacket counts a packet received at the given time. Returns an
+// error if the client exceeded the number of packets allowed per second.
+func (c *Connection) countPacket(now time.Time) error {
+	max := c.server.limits.maxPacketsPerSecond
+	if max <= 0 || c.ConnectionState != protocol.PlayState {
+		return nil
+	}
+	if now.Sub(c.packetWindow) >= packetRateWindowLength {
+		c.packetWindow = now
+		c.packetCount = 0
+	}
+	c.packetCount++
+	if c.packetCount > max {
+		return fmt.Errorf("sent more than %v packets in one second", max)
+	}
+	return nil
+}
+
+// isTimeout returns true if the given error is a timeout of the socket.
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// kickTimedOut disconnects the given client, which took too long to log in.
+func (s *Server) kickTimedOut(c *Connection) {
+	log.Info("Disconnecting", c.RemoteAddr(), "because it took too long to log in")
+	c.Disconnect(loginTimedOutMessage)
+}
+
+// kickFlooder disconnects the given client, which sent too many packets.
+func (s *Server) kickFlooder(c *Connection, err error) {
+	log.Warn("Kicking", c.Player.GetName(), "["+c.RemoteAddr().String()+"]", "for flooding:", err)
+	c.Disconnect(tooManyPacketsMessage)
+}
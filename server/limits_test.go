@@ -0,0 +1,61 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/protocol"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFrameSizeLimit(t *testing.T) {
+	if limit := frameSizeLimit(0); limit != maxFrameSize {
+		t.Error("Unset limits should be the limit of the protocol, currently:", limit)
+	}
+	if limit := frameSizeLimit(maxFrameSize + 1); limit != maxFrameSize {
+		t.Error("Limits cannot exceed the limit of the protocol, currently:", limit)
+	}
+	if limit := frameSizeLimit(1024); limit != 1024 {
+		t.Error("Limit should be 1024, currently:", limit)
+	}
+}
+
+func TestNextTooLongFrame(t *testing.T) {
+	properties := defaultProperties()
+	properties.MaxLoginFrameSize = 1024
+	server := &Server{limits: newConnectionLimits(properties)}
+	client, socket := net.Pipe()
+	defer client.Close()
+	c := NewConnection(socket, server)
+	c.ConnectionState = protocol.LoginState
+
+	go client.Write(protocol.Uvarint(1025))
+	if _, err := c.Next(); err == nil {
+		t.Error("Frames longer than the limit should be rejected")
+	} else if _, ok := err.(*protocol.ProtocolError); !ok {
+		t.Error("Too long frames should be protocol errors, got:", err)
+	}
+}
+
+func TestCountPacket(t *testing.T) {
+	properties := defaultProperties()
+	properties.MaxPacketsPerSecond = 2
+	c := &Connection{server: &Server{limits: newConnectionLimits(properties)}, ConnectionState: protocol.PlayState}
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := c.countPacket(now); err != nil {
+			t.Fatal("The first packets should be allowed:", err)
+		}
+	}
+	if err := c.countPacket(now.Add(time.Millisecond)); err == nil {
+		t.Error("The third packet in one second should be refused")
+	}
+	if err := c.countPacket(now.Add(time.Second)); err != nil {
+		t.Error("Packets should be allowed again after one second:", err)
+	}
+
+	c.ConnectionState = protocol.LoginState
+	c.packetCount = 10
+	if err := c.countPacket(now.Add(time.Second)); err != nil {
+		t.Error("Packets should only be limited in play state:", err)
+	}
+}
@@ -13,39 +13,42 @@ import (
 	"github.com/olsdavis/goelan/log"
 	"github.com/olsdavis/goelan/util"
 	"github.com/olsdavis/goelan/player"
-	"github.com/olsdavis/goelan/world"
 )
 
 // This file contains all the handlers for the login state.
 
 // Handles the login start packet.
-func loginStartHandler(packet *RawPacket, sender *Connection) {
-	username := packet.ReadStringMax(16)
-	if sender.GetServer().GetServerVersion().ProtocolVersion > sender.ProtocolVersion {
-		// old version
-		sender.Disconnect(fmt.Sprintf("Your client is outdated. I'm on %v.", sender.GetServer().GetServerVersion().Name))
-		return
-	} else if sender.GetServer().GetServerVersion().ProtocolVersion < sender.ProtocolVersion {
-		// new version
-		sender.Disconnect(fmt.Sprintf("I'm still on %v.", sender.GetServer().GetServerVersion().Name))
-		return
+func loginStartHandler(packet Packet, sender *Connection) error {
+	username := packet.(*LoginStartPacket).Username
+	if !IsSupportedVersion(sender.ProtocolVersion) {
+		if sender.ProtocolVersion < OldestVersion().Protocol {
+			// old version
+			sender.Disconnect(fmt.Sprintf("Your client is outdated. I'm on %v.", supportedVersionsName()))
+		} else {
+			// new version
+			sender.Disconnect(fmt.Sprintf("I'm still on %v.", supportedVersionsName()))
+		}
+		return nil
+	}
+
+	switch sender.GetServer().GetForwardingMode() {
+	case BungeeCordForwarding:
+		if sender.forwarded == nil {
+			sender.Disconnect(bungeeCordRequiredMessage)
+		} else {
+			loginForwarded(sender, username)
+		}
+		return nil
 	}
 
 	if Get().IsOnlineMode() {
 		// send encryption request
-		response := NewResponse()
 		token := encrypt.GenerateVerifyToken()
-		var encryptionRequest = struct {
-			ServerID    string
-			PublicKey   []byte
-			VerifyToken []byte
-		}{
-			"",
-			sender.GetServer().GetPublicKey(),
-			token,
-		}
-		response.WriteStructure(encryptionRequest)
-		sender.Write(response.ToRawPacket(EncryptionRequestPacketId))
+		sender.WritePacket(&EncryptionRequestPacket{
+			ServerID:    "",
+			PublicKey:   sender.GetServer().GetPublicKey(),
+			VerifyToken: token,
+		})
 		sender.VerifyToken = token
 		sender.VerifyUsername = username
 	} else {
@@ -54,7 +57,7 @@ func loginStartHandler(packet *RawPacket, sender *Connection) {
 		uuid, err := util.NameToUUID(offlineUUID)
 		if err != nil {
 			sender.Disconnect("Error encountered during connection: " + err.Error())
-			return
+			return nil
 		}
 		profile := player.PlayerProfile{
 			RealUUID:   uuid,
@@ -70,25 +73,30 @@ func loginStartHandler(packet *RawPacket, sender *Connection) {
 			RealUUID:   uuid,
 		}, nil)
 	}
+	return nil
 }
 
 // Handles the encryption request packet.
-func encryptionResponseHandler(packet *RawPacket, sender *Connection) {
-	sharedSecret, err := rsa.DecryptPKCS1v15(rand.Reader, sender.GetServer().GetPrivateKey(), packet.ReadByteArray())
+func encryptionResponseHandler(packet Packet, sender *Connection) error {
+	if len(sender.VerifyToken) == 0 {
+		return NewProtocolError("unexpected encryption response")
+	}
+	encryptionResponse := packet.(*EncryptionResponsePacket)
+	sharedSecret, err := rsa.DecryptPKCS1v15(rand.Reader, sender.GetServer().GetPrivateKey(), encryptionResponse.SharedSecret)
 	if err != nil {
-		panic(err)
+		return NewProtocolError("could not decrypt shared secret: %v", err)
 	}
-	verifyToken, err := rsa.DecryptPKCS1v15(rand.Reader, sender.GetServer().GetPrivateKey(), packet.ReadByteArray())
+	verifyToken, err := rsa.DecryptPKCS1v15(rand.Reader, sender.GetServer().GetPrivateKey(), encryptionResponse.VerifyToken)
 	if err != nil {
-		panic(err)
+		return NewProtocolError("could not decrypt verify token: %v", err)
 	}
 	if !bytes.Equal(verifyToken, sender.VerifyToken) {
 		sender.Disconnect("Invalid verify token.")
-		return
+		return nil
 	}
 	aesCipher, err := aes.NewCipher(sharedSecret)
 	if err != nil {
-		panic(err)
+		return NewProtocolError("invalid shared secret: %v", err)
 	}
 	sender.Writer = cipher.StreamWriter{
 		W: sender.Writer,
@@ -104,85 +112,55 @@ func encryptionResponseHandler(packet *RawPacket, sender *Connection) {
 	if err != nil {
 		sender.Disconnect("Could not connect to Mojang servers.")
 		log.Error("Error while connecting to Mojang servers:", err)
-		return
+		return nil
 	}
 	initializePlayer(*profile, sender)
 	processLogin(sender, profile, sharedSecret)
+	return nil
 }
 
 func processLogin(sender *Connection, profile *player.PlayerProfile, sharedSecret []byte) {
-	// Login Success packet
-	response := NewResponse()
-	{
-		var uuid string
-		if Get().IsOnlineMode() {
-			uuid = util.ToHyphenUUID(profile.UUID)
-		} else {
-			uuid = profile.RealUUID.String()
-		}
-		var loginSuccess = struct {
-			UUID string
-			Name string
-		}{
-			uuid,
-			profile.Name,
-		}
-		response.WriteStructure(loginSuccess)
-		sender.Write(response.ToRawPacket(LoginSuccessPacketId))
+	// the client must still be in the login state to read the reason of a refusal
+	if ok, reason := sender.GetServer().CanConnect(profile.Name, profile.UUID, sender.RemoteIP()); !ok {
+		sender.Disconnect(reason)
+		return
 	}
+	// Set Compression packet (must be sent before Login Success)
+	sender.EnableCompression(sender.GetServer().GetCompressionThreshold())
+	// Login Success packet
+	sender.WritePacket(&LoginSuccessPacket{
+		UUID:     profile.RealUUID.String(),
+		Username: profile.Name,
+	})
 	if sharedSecret != nil {
 		sender.SharedSecret = sharedSecret
 	}
 	// release the data we don't need anymore
 	sender.VerifyToken = emptyArray
 	sender.VerifyUsername = ""
-	if ok, reason := sender.GetServer().CanConnect(profile.Name, profile.UUID); !ok {
-		sender.Disconnect(reason)
-		return
-	}
 	// New connection state
 	sender.ConnectionState = PlayState
 	AssignHandler(sender)
-	response.Clear()
+	sender.finishPending()
+	sender.stopLoginTimeout()
 	// Join Game packet
-	var joinGame = struct {
-		EntityId         int
-		Gamemode         uint8
-		Dimension        int
-		Difficulty       uint8
-		MaxPlayers       uint8
-		LevelType        string
-		ReducedDebugInfo bool
-	}{
-		0,
-		0,
-		0,
-		0,
-		0,
-		"default",
-		false,
-	}
-	sender.Write(response.WriteStructure(joinGame).ToRawPacket(JoinGamePacketId))
-	response.Clear()
+	sender.WritePacket(sender.GetServer().joinGamePacket(sender.Player))
 	sender.GetServer().FinishLogin(*profile, sender)
 }
 
 func initializePlayer(profile player.PlayerProfile, sender *Connection) {
+	profile.Address = sender.RemoteAddr()
+	s := sender.GetServer()
 	pl := player.Player{
 		Permissions: nil,
 		Profile:     profile,
 		Settings:    &player.ClientSettings{},
-		Location: &world.Location{
-			Location3f: world.Location3f{
-				X: 0,
-				Y: 80,
-				Z: 0,
-			},
-			Orientation: world.Orientation{
-				Yaw:   90,
-				Pitch: 0,
-			},
-		},
+		Location:    s.GetWorld().GetSpawnLocation(),
+		GameMode:    player.GameMode(s.GetWorld().Info.GameType),
+		Inventory:   player.NewInventory(),
+		Health:      player.MaxHealth,
+		Food:        player.MaxFood,
+		Saturation:  player.DefaultSaturation,
 	}
 	sender.Player = &pl
 }
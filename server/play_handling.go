@@ -4,60 +4,138 @@ import (
 	"github.com/olsdavis/goelan/log"
 	"github.com/olsdavis/goelan/player"
 	. "github.com/olsdavis/goelan/protocol"
+	"math"
 )
 
 // This file contains all the handlers for the play state.
 
+const (
+	// the farthest horizontal position accepted from the clients
+	maxHorizontalPosition = 3.0e7
+)
+
 // clientSettingsHandler updates clients' settings.
-func clientSettingsHandler(packet *RawPacket, sender *Connection) {
-	sender.Player.Settings.Locale = packet.ReadStringMax(16)
-	sender.Player.Settings.ViewDistance = packet.ReadByte()
-	sender.Player.Settings.ChatMode = player.ChatMode(packet.ReadVarint())
-	sender.Player.Settings.ColorsEnabled = packet.ReadBoolean()
-	sender.Player.Settings.DisplayedSkinParts = packet.ReadUnsignedByte()
-	sender.Player.Settings.MainHand = player.Hand(packet.ReadVarint())
+func clientSettingsHandler(packet Packet, sender *Connection) error {
+	settings := packet.(*ClientSettingsPacket)
+	sender.Player.Settings.Locale = settings.Locale
+	sender.Player.Settings.ViewDistance = settings.ViewDistance
+	sender.Player.Settings.ChatMode = player.ChatMode(settings.ChatMode)
+	sender.Player.Settings.ColorsEnabled = settings.ChatColors
+	sender.Player.Settings.DisplayedSkinParts = settings.DisplayedSkinParts
+	sender.Player.Settings.MainHand = player.Hand(settings.MainHand)
+	sender.updateChunks()
+	return nil
 }
 
-func clientStatusHandler(packet *RawPacket, sender *Connection) {
+func clientStatusHandler(packet Packet, sender *Connection) error {
+	if packet.(*ClientStatusPacket).ActionID == ClientStatusRespawn {
+		sender.respawn()
+	}
+	return nil
 }
 
-func pluginMessageHandler(packet *RawPacket, sender *Connection) {
+func pluginMessageHandler(packet Packet, sender *Connection) error {
+	return nil
 }
 
-func keepAliveHandler(packet *RawPacket, sender *Connection) {
-	id := packet.ReadLong()
+func keepAliveHandler(packet Packet, sender *Connection) error {
+	id := packet.(*KeepAlivePacket).ID
 	sender.PendingKeepAlives.QueryAndComplete(func(test interface{}) bool {
 		data := test.(player.KeepAliveData)
 		return data.ID == id
 	})
+	return nil
 }
 
-func chatMessageHandler(packet *RawPacket, sender *Connection) {
-	message := sender.Player.GetName() + " > " + packet.ReadString()
+func chatMessageHandler(packet Packet, sender *Connection) error {
+	message := sender.Player.GetName() + " > " + packet.(*IncomingChatPacket).Message
 	log.Info(sender.Player.GetName(), message)
 	sender.SendMessage(message, ChatMessageMode)
+	return nil
 }
 
-func teleportConfirmHandler(packet *RawPacket, sender *Connection) {
-	id := packet.ReadVarint()
+func teleportConfirmHandler(packet Packet, sender *Connection) error {
+	id := packet.(*TeleportConfirmPacket).TeleportID
 	sender.PendingTeleportConfirmations.QueryAndComplete(func(test interface{}) bool {
 		data := test.(player.TeleportConfirmData)
 		return data.ID == id
 	})
+	return nil
 }
 
-func playerPositionAndLookHandler(packet *RawPacket, sender *Connection) {
-	//TODO: implement
+func playerPositionAndLookHandler(packet Packet, sender *Connection) error {
+	p := packet.(*IncomingPositionAndLookPacket)
+	if isTeleporting(sender) {
+		return nil
+	}
+	if err := moveTo(sender, p.X, p.Y, p.Z); err != nil {
+		return err
+	}
+	sender.Player.Look(p.Yaw, p.Pitch)
+	return nil
+}
+
+func playerPositionHandler(packet Packet, sender *Connection) error {
+	p := packet.(*IncomingPositionPacket)
+	if isTeleporting(sender) {
+		return nil
+	}
+	return moveTo(sender, p.X, p.Y, p.Z)
+}
+
+func playerLookHandler(packet Packet, sender *Connection) error {
+	p := packet.(*IncomingLookPacket)
+	if isTeleporting(sender) {
+		return nil
+	}
+	sender.Player.Look(p.Yaw, p.Pitch)
+	return nil
+}
+
+// isTeleporting returns true if the client has not confirmed a teleport yet:
+// the positions it sends until then are outdated.
+func isTeleporting(sender *Connection) bool {
+	return len(sender.PendingTeleportConfirmations.Elements()) > 0
+}
+
+// moveTo updates player's location, and the chunks around it. The
+// player is moved back if it goes further beyond the world border.
+// Returns an error if the location is invalid.
+func moveTo(sender *Connection, x, y, z float64) error {
+	if !validPosition(x, y, z) {
+		return NewProtocolError("invalid position %v %v %v", x, y, z)
+	}
+	if crossesBorder(sender.Player, x, z) {
+		sender.sendPosition()
+		return nil
+	}
+	sender.Player.Move(float32(x), float32(y), float32(z))
+	sender.updateChunks()
+	return nil
+}
+
+// validPosition returns true if the given position is finite and, as
+// vanilla requires, within maxHorizontalPosition of the origin.
+func validPosition(x, y, z float64) bool {
+	for _, c := range []float64{x, y, z} {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return false
+		}
+	}
+	return math.Abs(x) <= maxHorizontalPosition && math.Abs(z) <= maxHorizontalPosition
 }
 
-func animationHandler(packet *RawPacket, sender *Connection) {
+func animationHandler(packet Packet, sender *Connection) error {
 	//TODO: implement
+	return nil
 }
 
-func clickWindowHandler(packet *RawPacket, sender *Connection) {
+func clickWindowHandler(packet Packet, sender *Connection) error {
 	//TODO: implement
+	return nil
 }
 
-func closeWindowHandler(packet *RawPacket, sender *Connection) {
+func closeWindowHandler(packet Packet, sender *Connection) error {
 	//TODO: implement
+	return nil
 }
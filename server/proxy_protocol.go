@@ -0,0 +1,152 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// This file implements the PROXY protocol (v1 and v2) of HAProxy, used by
+// the load balancers to give us the real address of the clients.
+
+const (
+	proxyHeaderTimeout = 5 * time.Second
+	// the maximal length of a v1 header, including the CRLF
+	proxyV1MaxLength = 107
+
+	proxyV2VersionCommandLength = 4 // version/command, family/protocol and length
+	proxyV2Version              = 0x20
+	proxyV2LocalCommand         = 0x00
+	proxyV2ProxyCommand         = 0x01
+	proxyV2TCP4                 = 0x11
+	proxyV2TCP6                 = 0x21
+)
+
+var (
+	proxyV1Signature = []byte("PROXY ")
+	proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// proxiedConn struct is a connection which header has been read, and
+// which remote address is the one given by the proxy.
+type proxiedConn struct {
+	net.Conn
+	reader *bufio.Reader
+	remote net.Addr
+}
+
+// Read reads the data following the header.
+func (c *proxiedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// RemoteAddr returns the real address of the client.
+func (c *proxiedConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// readProxyHeader reads the PROXY protocol header sent at the beginning of
+// the given connection, and returns the connection with the real address of the client.
+func readProxyHeader(conn net.Conn) (net.Conn, error) {
+	conn.SetReadDeadline(time.Now().Add(proxyHeaderTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	reader := bufio.NewReader(conn)
+	remote, err := parseProxyHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+	if remote == nil {
+		// the proxy itself is connecting (health check)
+		remote = conn.RemoteAddr()
+	}
+	return &proxiedConn{conn, reader, remote}, nil
+}
+
+// parseProxyHeader parses a v1 or v2 header. Returns a nil address if the
+// header does not contain the address of a client.
+func parseProxyHeader(reader *bufio.Reader) (net.Addr, error) {
+	if signature, err := reader.Peek(len(proxyV2Signature)); err == nil && bytes.Equal(signature, proxyV2Signature) {
+		return parseProxyV2Header(reader)
+	}
+	if signature, err := reader.Peek(len(proxyV1Signature)); err == nil && bytes.Equal(signature, proxyV1Signature) {
+		return parseProxyV1Header(reader)
+	}
+	return nil, fmt.Errorf("no PROXY protocol header")
+}
+
+// parseProxyV1Header parses a text header: "PROXY TCP4 src dst srcport dstport\r\n".
+func parseProxyV1Header(reader *bufio.Reader) (net.Addr, error) {
+	line := make([]byte, 0, proxyV1MaxLength)
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) == proxyV1MaxLength {
+			return nil, fmt.Errorf("PROXY v1 header is too long")
+		}
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+	}
+	fields := strings.Split(strings.TrimSuffix(string(line), "\r\n"), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("invalid PROXY v1 header %q", line)
+	}
+	ip := net.ParseIP(fields[2])
+	if ip == nil {
+		return nil, fmt.Errorf("invalid PROXY v1 source address %q", fields[2])
+	}
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROXY v1 source port %q", fields[4])
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// parseProxyV2Header parses a binary header.
+func parseProxyV2Header(reader *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, len(proxyV2Signature)+proxyV2VersionCommandLength)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	header = header[len(proxyV2Signature):]
+	if header[0]&0xF0 != proxyV2Version {
+		return nil, fmt.Errorf("invalid PROXY v2 version %v", header[0]>>4)
+	}
+	addresses := make([]byte, binary.BigEndian.Uint16(header[2:]))
+	if _, err := io.ReadFull(reader, addresses); err != nil {
+		return nil, err
+	}
+
+	switch header[0] & 0x0F {
+	case proxyV2LocalCommand:
+		return nil, nil
+	case proxyV2ProxyCommand:
+	default:
+		return nil, fmt.Errorf("invalid PROXY v2 command %v", header[0]&0x0F)
+	}
+	// the addresses may be followed by TLVs, which we ignore
+	switch header[1] {
+	case proxyV2TCP4:
+		if len(addresses) < 12 {
+			return nil, fmt.Errorf("PROXY v2 addresses are too short")
+		}
+		return &net.TCPAddr{IP: net.IP(addresses[:4]), Port: int(binary.BigEndian.Uint16(addresses[8:]))}, nil
+	case proxyV2TCP6:
+		if len(addresses) < 36 {
+			return nil, fmt.Errorf("PROXY v2 addresses are too short")
+		}
+		return &net.TCPAddr{IP: net.IP(addresses[:16]), Port: int(binary.BigEndian.Uint16(addresses[32:]))}, nil
+	}
+	// unspecified or unsupported family
+	return nil, nil
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestProxyV1Header(t *testing.T) {
+	reader := bufio.NewReader(bytes.NewReader([]byte("PROXY TCP4 192.168.1.2 10.0.0.1 56324 25565\r\n\x10\x00")))
+	addr, err := parseProxyHeader(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.String() != "192.168.1.2:56324" {
+		t.Error("Address should be 192.168.1.2:56324, currently:", addr)
+	}
+	if b, _ := reader.ReadByte(); b != 0x10 {
+		t.Error("The data following the header should not be consumed")
+	}
+
+	if addr, err := parseProxyHeader(bufio.NewReader(bytes.NewReader([]byte("PROXY UNKNOWN\r\n")))); err != nil || addr != nil {
+		t.Error("Unknown connections should have no address, got:", addr, err)
+	}
+}
+
+func TestProxyV2Header(t *testing.T) {
+	header := append([]byte(nil), proxyV2Signature...)
+	header = append(header, 0x21, proxyV2TCP4, 0x00, 0x0C, // proxy command, TCP over IPv4, 12 bytes
+		192, 168, 1, 2, 10, 0, 0, 1, // source and destination
+		0xDC, 0x04, 0x63, 0xDD, // 56324 and 25565
+		0x10)
+	reader := bufio.NewReader(bytes.NewReader(header))
+	addr, err := parseProxyHeader(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addr.(*net.TCPAddr).IP.Equal(net.IPv4(192, 168, 1, 2)) || addr.(*net.TCPAddr).Port != 56324 {
+		t.Error("Address should be 192.168.1.2:56324, currently:", addr)
+	}
+	if b, _ := reader.ReadByte(); b != 0x10 {
+		t.Error("The data following the header should not be consumed")
+	}
+}
+
+func TestMissingProxyHeader(t *testing.T) {
+	if _, err := parseProxyHeader(bufio.NewReader(bytes.NewReader([]byte{0x10, 0x00, 0xBC, 0x02}))); err == nil {
+		t.Error("Connections without header should be rejected")
+	}
+}
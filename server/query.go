@@ -0,0 +1,226 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/protocol"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// This file implements the GameSpy4 query protocol (UDP), used by
+// the server lists to get the list of the online players.
+
+const (
+	queryMagic            = 0xFEFD
+	queryHandshakeType    = 0x09
+	queryStatType         = 0x00
+	querySessionIdMask    = 0x0F0F0F0F
+	queryChallengeTimeout = 30 * time.Second
+	queryMaxPacketLength  = 1460
+	queryGameType         = "SMP"
+	queryGameId           = "MINECRAFT"
+)
+
+var (
+	// the padding sent before the key-values of the full stat
+	queryKeyValuesPadding = []byte{0x73, 0x70, 0x6C, 0x69, 0x74, 0x6E, 0x75, 0x6D, 0x00, 0x80, 0x00}
+	// the padding sent before the players of the full stat
+	queryPlayersPadding = []byte{0x01, 0x70, 0x6C, 0x61, 0x79, 0x65, 0x72, 0x5F, 0x00, 0x00}
+)
+
+// queryStats struct represents the data sent to the query clients.
+type queryStats struct {
+	Motd       string
+	Version    string
+	Plugins    string
+	Map        string
+	Players    []string
+	MaxPlayers uint
+	Port       uint16
+	Address    string
+}
+
+// queryChallenge struct represents a challenge token given to a client.
+type queryChallenge struct {
+	token    int32
+	deadline time.Time
+}
+
+// queryListener struct answers the query requests.
+type queryListener struct {
+	stats      func() queryStats         // returns the data to send
+	challenges map[string]queryChallenge // the challenge tokens of the clients, by address
+	lock       sync.Mutex                // lock for the challenges map
+}
+
+// newQueryListener creates a new query listener, which sends the data
+// returned by the given function.
+func newQueryListener(stats func() queryStats) *queryListener {
+	return &queryListener{
+		stats:      stats,
+		challenges: make(map[string]queryChallenge),
+		lock:       sync.Mutex{},
+	}
+}
+
+// startQuery starts the query listener on the query port.
+func (s *Server) startQuery() {
+	listen := fmt.Sprintf("%v:%v", s.properties.Address, s.properties.QueryPort)
+	conn, err := net.ListenPacket("udp", listen)
+	if err != nil {
+		log.Error("Could not start query listener:", err)
+		return
+	}
+	log.Info("Query listening on", listen)
+	listener := newQueryListener(s.queryStats)
+	buffer := make([]byte, queryMaxPacketLength)
+	for s.run {
+		n, addr, err := conn.ReadFrom(buffer)
+		if err != nil {
+			log.Debug("Could not read query packet:", err)
+			continue
+		}
+		if response := listener.handle(buffer[:n], addr.String()); response != nil {
+			conn.WriteTo(response, addr)
+		}
+	}
+	conn.Close()
+}
+
+// queryStats returns the data sent to the query clients.
+func (s *Server) queryStats() queryStats {
+	players := s.GetAllPlayers()
+	names := make([]string, len(players))
+	for i, pl := range players {
+		names[i] = pl.GetName()
+	}
+	mapName := ""
+	if w := s.GetWorld(); w != nil {
+		mapName = w.Name
+	}
+	return queryStats{
+		Motd:       s.GetMotd(),
+		Version:    protocol.LatestVersion().Name,
+		Plugins:    s.GetServerVersion().Name,
+		Map:        mapName,
+		Players:    names,
+		MaxPlayers: s.GetMaxPlayers(),
+		Port:       s.properties.Port,
+		Address:    s.properties.Address,
+	}
+}
+
+// handle handles the given query packet, sent from the given address.
+// Returns the response, or nil if nothing should be answered.
+func (q *queryListener) handle(packet []byte, addr string) []byte {
+	if len(packet) < 7 || binary.BigEndian.Uint16(packet) != queryMagic {
+		return nil
+	}
+	packetType := packet[2]
+	sessionId := int32(binary.BigEndian.Uint32(packet[3:])) & querySessionIdMask
+	payload := packet[7:]
+	switch packetType {
+	case queryHandshakeType:
+		return q.handshake(sessionId, addr)
+	case queryStatType:
+		if len(payload) < 4 || !q.checkChallenge(int32(binary.BigEndian.Uint32(payload)), addr) {
+			return nil
+		}
+		// the full stat request is padded with 4 bytes
+		if len(payload) >= 8 {
+			return writeFullStat(sessionId, q.stats())
+		}
+		return writeBasicStat(sessionId, q.stats())
+	}
+	return nil
+}
+
+// handshake gives a new challenge token to the client.
+func (q *queryListener) handshake(sessionId int32, addr string) []byte {
+	token := rand.Int31()
+	q.lock.Lock()
+	q.challenges[addr] = queryChallenge{token, time.Now().Add(queryChallengeTimeout)}
+	// clean the expired challenges
+	for key, challenge := range q.challenges {
+		if challenge.deadline.Before(time.Now()) {
+			delete(q.challenges, key)
+		}
+	}
+	q.lock.Unlock()
+
+	buf := newQueryResponse(queryHandshakeType, sessionId)
+	writeQueryString(buf, strconv.Itoa(int(token)))
+	return buf.Bytes()
+}
+
+// checkChallenge returns true if the given token has been given to the client
+// and has not expired.
+func (q *queryListener) checkChallenge(token int32, addr string) bool {
+	defer q.lock.Unlock()
+	q.lock.Lock()
+	challenge, ok := q.challenges[addr]
+	return ok && challenge.token == token && challenge.deadline.After(time.Now())
+}
+
+// writeBasicStat returns the basic stat response.
+func writeBasicStat(sessionId int32, stats queryStats) []byte {
+	buf := newQueryResponse(queryStatType, sessionId)
+	writeQueryString(buf, stats.Motd)
+	writeQueryString(buf, queryGameType)
+	writeQueryString(buf, stats.Map)
+	writeQueryString(buf, strconv.Itoa(len(stats.Players)))
+	writeQueryString(buf, strconv.Itoa(int(stats.MaxPlayers)))
+	// the port is the only little endian value
+	binary.Write(buf, binary.LittleEndian, stats.Port)
+	writeQueryString(buf, stats.Address)
+	return buf.Bytes()
+}
+
+// writeFullStat returns the full stat response.
+func writeFullStat(sessionId int32, stats queryStats) []byte {
+	buf := newQueryResponse(queryStatType, sessionId)
+	buf.Write(queryKeyValuesPadding)
+	keyValues := [][2]string{
+		{"hostname", stats.Motd},
+		{"gametype", queryGameType},
+		{"game_id", queryGameId},
+		{"version", stats.Version},
+		{"plugins", stats.Plugins},
+		{"map", stats.Map},
+		{"numplayers", strconv.Itoa(len(stats.Players))},
+		{"maxplayers", strconv.Itoa(int(stats.MaxPlayers))},
+		{"hostport", strconv.Itoa(int(stats.Port))},
+		{"hostip", stats.Address},
+	}
+	for _, kv := range keyValues {
+		writeQueryString(buf, kv[0])
+		writeQueryString(buf, kv[1])
+	}
+	buf.WriteByte(0)
+	buf.Write(queryPlayersPadding)
+	for _, name := range stats.Players {
+		writeQueryString(buf, name)
+	}
+	buf.WriteByte(0)
+	return buf.Bytes()
+}
+
+// newQueryResponse creates a buffer starting with the header of the responses.
+func newQueryResponse(packetType byte, sessionId int32) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(packetType)
+	binary.Write(buf, binary.BigEndian, sessionId)
+	return buf
+}
+
+// writeQueryString writes the given null-terminated string.
+func writeQueryString(buf *bytes.Buffer, str string) {
+	buf.WriteString(str)
+	buf.WriteByte(0)
+}
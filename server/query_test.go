@@ -0,0 +1,80 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"testing"
+)
+
+var testQueryStats = queryStats{
+	Motd:       "MOTD",
+	Version:    "1.12.2",
+	Plugins:    "Goelan 1.12.2",
+	Map:        "world",
+	Players:    []string{"Notch", "jeb_"},
+	MaxPlayers: 20,
+	Port:       25565,
+	Address:    "127.0.0.1",
+}
+
+// queryRequest creates a query request packet.
+func queryRequest(packetType byte, sessionId int32, payload ...int32) []byte {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, uint16(queryMagic))
+	buf.WriteByte(packetType)
+	binary.Write(buf, binary.BigEndian, sessionId)
+	for _, i := range payload {
+		binary.Write(buf, binary.BigEndian, i)
+	}
+	return buf.Bytes()
+}
+
+// queryChallengeToken performs the handshake and returns the challenge token.
+func queryChallengeToken(t *testing.T, q *queryListener, addr string) int32 {
+	response := q.handle(queryRequest(queryHandshakeType, 1), addr)
+	if len(response) < 6 || response[0] != queryHandshakeType || response[len(response)-1] != 0 {
+		t.Fatalf("Invalid handshake response: %x", response)
+	}
+	token, err := strconv.Atoi(string(response[5 : len(response)-1]))
+	if err != nil {
+		t.Fatal("Invalid challenge token:", err)
+	}
+	return int32(token)
+}
+
+func TestQueryBasicStat(t *testing.T) {
+	q := newQueryListener(func() queryStats { return testQueryStats })
+	token := queryChallengeToken(t, q, "client")
+
+	expected := []byte("\x00\x00\x00\x00\x01MOTD\x00SMP\x00world\x002\x0020\x00\xDD\x63127.0.0.1\x00")
+	if response := q.handle(queryRequest(queryStatType, 1, token), "client"); !bytes.Equal(expected, response) {
+		t.Errorf("Basic stat should be:\n%q\nCurrently:\n%q", expected, response)
+	}
+}
+
+func TestQueryFullStat(t *testing.T) {
+	q := newQueryListener(func() queryStats { return testQueryStats })
+	token := queryChallengeToken(t, q, "client")
+
+	expected := []byte("\x00\x00\x00\x00\x01splitnum\x00\x80\x00" +
+		"hostname\x00MOTD\x00gametype\x00SMP\x00game_id\x00MINECRAFT\x00version\x001.12.2\x00" +
+		"plugins\x00Goelan 1.12.2\x00map\x00world\x00numplayers\x002\x00maxplayers\x0020\x00" +
+		"hostport\x0025565\x00hostip\x00127.0.0.1\x00\x00" +
+		"\x01player_\x00\x00Notch\x00jeb_\x00\x00")
+	if response := q.handle(queryRequest(queryStatType, 1, token, 0), "client"); !bytes.Equal(expected, response) {
+		t.Errorf("Full stat should be:\n%q\nCurrently:\n%q", expected, response)
+	}
+}
+
+func TestQueryInvalidChallenge(t *testing.T) {
+	q := newQueryListener(func() queryStats { return testQueryStats })
+	token := queryChallengeToken(t, q, "client")
+
+	if response := q.handle(queryRequest(queryStatType, 1, token+1), "client"); response != nil {
+		t.Error("Stat with a wrong challenge token should not be answered")
+	}
+	if response := q.handle(queryRequest(queryStatType, 1, token), "other"); response != nil {
+		t.Error("Stat with the challenge token of another client should not be answered")
+	}
+}
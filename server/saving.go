@@ -0,0 +1,91 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/world"
+	"sync/atomic"
+)
+
+// This file saves the worlds periodically, and unloads the chunks
+// which no player can see, so that the memory does not grow with
+// the explored area.
+
+const (
+	// the number of ticks between two unloadings of the unseen chunks (30 seconds)
+	chunkUnloadInterval = 30 * 20
+	// the number of ticks between two saves of the worlds (5 minutes);
+	// must be a multiple of chunkUnloadInterval
+	autoSaveInterval = 5 * 60 * 20
+	// the distance, in chunks, beyond the view distance of the players
+	// where the chunks stay loaded, so that the players moving during
+	// the unloading do not see unloaded chunks
+	chunkUnloadMargin = 2
+)
+
+// startSaving unloads the chunks which no player can see, and saves the
+// worlds if save is true, in another routine so that the ticks do not
+// wait for the disk. Does nothing if the previous save has not ended.
+func (s *Server) startSaving(save bool) {
+	if !atomic.CompareAndSwapInt32(&s.saving, 0, 1) {
+		return
+	}
+	visible := s.visibleChunks()
+	go func() {
+		defer atomic.StoreInt32(&s.saving, 0)
+		s.worlds.unloadChunks(func(w *world.World, pos world.ChunkPos) bool {
+			return visible[w][pos]
+		})
+		if save {
+			s.worlds.save()
+		}
+	}()
+}
+
+// visibleChunks returns the chunks of each world which are within the
+// view distance of a player, extended by chunkUnloadMargin.
+func (s *Server) visibleChunks() map[*world.World]map[world.ChunkPos]bool {
+	ret := make(map[*world.World]map[world.ChunkPos]bool)
+	s.ForEachPlayerSync(func(c *Connection) {
+		w, center, distance, ok := c.chunks.area()
+		if !ok {
+			return
+		}
+		if ret[w] == nil {
+			ret[w] = make(map[world.ChunkPos]bool)
+		}
+		distance += chunkUnloadMargin
+		for x := center.X - int32(distance); x <= center.X+int32(distance); x++ {
+			for z := center.Z - int32(distance); z <= center.Z+int32(distance); z++ {
+				ret[w][world.ChunkPos{X: x, Z: z}] = true
+			}
+		}
+	})
+	return ret
+}
+
+// unloadChunks saves and unloads the chunks of the loaded worlds
+// for which keep returns false.
+func (m *worldManager) unloadChunks(keep func(w *world.World, pos world.ChunkPos) bool) {
+	// the worlds are not closed meanwhile
+	defer m.lock.RUnlock()
+	m.lock.RLock()
+	for _, w := range m.worlds {
+		unloaded, _ := w.UnloadChunks(func(pos world.ChunkPos) bool {
+			return keep(w, pos)
+		})
+		if unloaded > 0 {
+			log.Debug("Unloaded", unloaded, "chunks of world", w.Name)
+		}
+	}
+}
+
+// save saves the loaded worlds.
+func (m *worldManager) save() {
+	defer m.lock.RUnlock()
+	m.lock.RLock()
+	for _, w := range m.worlds {
+		if err := w.Save(); err != nil {
+			log.Error("Could not save world", w.Name+". Some chunks may not have been saved. Error's reason:", err)
+		}
+	}
+}
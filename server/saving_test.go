@@ -0,0 +1,32 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/player"
+	"github.com/olsdavis/goelan/world"
+	"testing"
+)
+
+func TestVisibleChunks(t *testing.T) {
+	w := world.NewWorld("test")
+	c := &Connection{
+		Player:    &player.Player{Location: world.NewLocation(0, 64, 0, w)},
+		chunks:    newChunkTracker(),
+		connected: true,
+	}
+	s := &Server{clients: map[string]*Connection{"player": c}}
+	if len(s.visibleChunks()) != 0 {
+		t.Error("The chunks of a player who has not received any should not be visible")
+	}
+
+	c.chunks.update(w, world.ChunkPos{X: 10, Z: -4}, 2)
+	visible := s.visibleChunks()[w]
+	if distance := 2 + chunkUnloadMargin; len(visible) != (2*distance+1)*(2*distance+1) {
+		t.Errorf("Expected %v visible chunks, got %v", (2*distance+1)*(2*distance+1), len(visible))
+	}
+	if !visible[world.ChunkPos{X: 10 + 2 + chunkUnloadMargin, Z: -4}] {
+		t.Error("The chunks within the margin should be visible")
+	}
+	if visible[world.ChunkPos{X: 10 + 3 + chunkUnloadMargin, Z: -4}] {
+		t.Error("The chunks beyond the margin should not be visible")
+	}
+}
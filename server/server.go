@@ -16,7 +16,7 @@ import (
 	"sync"
 	"time"
 	"math/rand"
-	"github.com/olsdavis/goelan/world"
+	"github.com/olsdavis/goelan/world/generator"
 )
 
 var (
@@ -30,6 +30,7 @@ func Get() *Server {
 
 const (
 	banListFile    = "banlist.json"
+	ipBanListFile  = "banned-ips.json"
 	faviconFile    = "server-icon.png"
 	propertiesFile = "server.toml"
 )
@@ -43,6 +44,40 @@ type ServerProperties struct {
 	MaxPlayers   int32  `toml:"max-players"` // the maximal amount of players that the server should host
 	OnlineMode   bool   `toml:"online-mode"` // if true => authentication with Mojang servers
 	ViewDistance int    `toml:"view-distance"`
+	LevelName    string `toml:"level-name"` // the directory of the default world
+	// the number of routines loading and generating the chunks (0 or less for one per CPU)
+	ChunkWorkers int `toml:"chunk-workers"`
+	// the following properties are only used when creating the default world
+	LevelSeed  string `toml:"level-seed"` // a number or any text (random if empty)
+	Gamemode   int    `toml:"gamemode"`   // the default gamemode (0: survival, 1: creative, 2: adventure, 3: spectator)
+	Difficulty int    `toml:"difficulty"` // 0: peaceful, 1: easy, 2: normal, 3: hard
+	LevelType  string `toml:"level-type"` // the name of the generator
+	// the options of the generator (the preset of the flat generator)
+	GeneratorSettings string `toml:"generator-settings"`
+	// packets at least as long as this threshold are compressed (negative to disable compression)
+	CompressionThreshold int    `toml:"network-compression-threshold"`
+	EnableQuery          bool   `toml:"enable-query"` // if true => answers the GameSpy4 queries (UDP)
+	QueryPort            uint16 `toml:"query-port"`
+	EnableRcon           bool   `toml:"enable-rcon"` // if true => remote console enabled
+	RconPort             uint16 `toml:"rcon-port"`
+	RconPassword         string `toml:"rcon-password"`
+	// how the proxy in front of the server forwards the players' data (none or bungeecord)
+	ForwardingMode ForwardingMode `toml:"forwarding-mode"`
+	// if true => the clients connect through a load balancer which sends a PROXY protocol header
+	ProxyProtocol bool `toml:"proxy-protocol"`
+	// the minimal delay (in milliseconds) between two logins from the same IP (0 or less to disable)
+	ConnectionThrottle  int `toml:"connection-throttle"`
+	MaxConnectionsPerIP int `toml:"max-connections-per-ip"` // 0 or less if unlimited
+	// the maximal number of connections which are not playing yet (0 or less if unlimited)
+	MaxPendingHandshakes int `toml:"max-pending-handshakes"`
+	// the maximal length of the packets sent by the clients in each state (in bytes)
+	MaxHandshakeFrameSize int `toml:"max-handshake-packet-size"`
+	MaxLoginFrameSize     int `toml:"max-login-packet-size"`
+	MaxPlayFrameSize      int `toml:"max-play-packet-size"`
+	LoginTimeout          int `toml:"login-timeout"`          // in seconds (0 or less to disable)
+	MaxPacketsPerSecond   int `toml:"max-packets-per-second"` // in play state (0 or less if unlimited)
+	// the worlds loaded in addition to the default one
+	Worlds []WorldProperties `toml:"worlds,omitempty"`
 }
 
 // Server struct represents a running Golang Minecraft server.
@@ -54,7 +89,8 @@ type Server struct {
 	clients    map[string]*Connection // online players
 	playerLock sync.Mutex             // lock for the clients map
 
-	BanList *player.BanList // contains the players that have been banned from the server
+	BanList   *player.BanList   // contains the players that have been banned from the server
+	IPBanList *player.IPBanList // contains the IP addresses that have been banned from the server
 
 	serverVersion   ServerVersion   // server's version (protocol and name)
 	favicon         string          // the favicon
@@ -63,7 +99,14 @@ type Server struct {
 	rsaPrivateKey   *rsa.PrivateKey // the keypair used for encryption
 	publicKey       []byte          // the public key in bytes
 
-	world *world.World // one world only, for the moment
+	worlds *worldManager // the loaded worlds
+
+	blockChanges *blockChanges // the blocks changed during the current tick
+	ticks        uint64        // the number of ticks since the start
+	saving       int32         // 1 while the worlds are being saved (see saving.go)
+
+	throttle *connectionThrottle // limits the connections
+	limits   connectionLimits    // limits what the clients send
 
 	ExitChan chan int // a channel used for server's close
 }
@@ -79,14 +122,18 @@ func CreateServer(properties ServerProperties) *Server {
 		properties:      properties,
 		clients:         make(map[string]*Connection),
 		playerLock:      sync.Mutex{},
-		serverVersion:   ServerVersion{"Goelan 1.12.2", 340},
+		serverVersion:   newServerVersion(protocol.LatestVersion()),
 		favicon:         "",
 		ticker:          nil,
 		keepAliveTicker: nil,
 		rsaPrivateKey:   encrypt.GeneratePrivateKey(),
 		publicKey:       nil,
-		world:           nil,
-		ExitChan:        make(chan int, 1),
+		worlds:          newWorldManager(),
+		blockChanges:    newBlockChanges(),
+		throttle: newConnectionThrottle(time.Duration(properties.ConnectionThrottle)*time.Millisecond,
+			properties.MaxConnectionsPerIP, properties.MaxPendingHandshakes),
+		limits:   newConnectionLimits(properties),
+		ExitChan: make(chan int, 1),
 	}
 	return serverInstance
 }
@@ -94,26 +141,59 @@ func CreateServer(properties ServerProperties) *Server {
 // CreateServerFromProperties creates a new server from the properties file.
 func CreateServerFromProperties() *Server {
 	props := readProperties()
+	if props == nil {
+		log.Fatal("Cannot start the server with an invalid configuration.")
+		os.Exit(1)
+	}
 	return CreateServer(*props)
 }
 
+// defaultProperties returns the properties used when they are
+// not defined in the properties file.
+func defaultProperties() ServerProperties {
+	return ServerProperties{
+		Port:                 25565,
+		Address:              "127.0.0.1",
+		Motd:                 "A Goelan Minecraft server",
+		MaxPlayers:           10,
+		OnlineMode:           true,
+		ViewDistance:         15,
+		LevelName:            "world",
+		ChunkWorkers:         0,
+		LevelSeed:            "",
+		Gamemode:             0,
+		Difficulty:           1,
+		LevelType:            generator.DefaultName,
+		GeneratorSettings:    "",
+		CompressionThreshold: 256,
+		EnableQuery:          false,
+		QueryPort:            25565,
+		EnableRcon:           false,
+		RconPort:             25575,
+		RconPassword:         "",
+		ForwardingMode:       NoForwarding,
+		ProxyProtocol:        false,
+		ConnectionThrottle:   4000,
+		MaxConnectionsPerIP:  3,
+		MaxPendingHandshakes: 256,
+		// the handshake and the login may contain data forwarded by a proxy
+		MaxHandshakeFrameSize: 32768,
+		MaxLoginFrameSize:     32768,
+		MaxPlayFrameSize:      maxFrameSize,
+		LoginTimeout:          30,
+		MaxPacketsPerSecond:   500,
+	}
+}
+
 // readProperties reads the properties file ("server.toml").
 func readProperties() *ServerProperties {
-	var properties ServerProperties
+	// missing properties keep their default value
+	properties := defaultProperties()
 
 	// properties file read
 	if _, err := os.Open(propertiesFile); err != nil && os.IsNotExist(err) {
 		log.Info(fmt.Sprintf("No %v file found. Creating one.", propertiesFile))
 
-		properties = ServerProperties{
-			Port:         25565,
-			Address:      "127.0.0.1",
-			Motd:         "A Goelan Minecraft server",
-			MaxPlayers:   10,
-			OnlineMode:   true,
-			ViewDistance: 15,
-		}
-
 		f, e := os.Create(propertiesFile)
 		if e != nil {
 			log.Fatal(fmt.Sprintf("Could not create the '%v' file! %s", propertiesFile, e))
@@ -174,16 +254,47 @@ func (s *Server) GetPrivateKey() *rsa.PrivateKey {
 	return s.rsaPrivateKey
 }
 
-// GetServerVersion returns server's version (protocol and name).
+// GetServerVersion returns server's version (protocol and name), which is
+// the one of the latest supported Minecraft version.
 func (s *Server) GetServerVersion() ServerVersion {
 	return s.serverVersion
 }
 
+// GetServerVersionFor returns the version compatible with the given protocol
+// version, or server's version if the protocol version is not supported.
+func (s *Server) GetServerVersionFor(protocolVersion uint32) ServerVersion {
+	if version, ok := protocol.GetVersion(protocolVersion); ok {
+		return newServerVersion(version)
+	}
+	return s.serverVersion
+}
+
+// GetCompressionThreshold returns the size from which packets are compressed.
+// Negative if the compression is disabled.
+func (s *Server) GetCompressionThreshold() int {
+	return s.properties.CompressionThreshold
+}
+
 // GetViewDistance returns server's view distance.
 func (s *Server) GetViewDistance() int {
 	return s.properties.ViewDistance
 }
 
+// IsRconEnabled returns whether the remote console is enabled or not.
+func (s *Server) IsRconEnabled() bool {
+	return s.properties.EnableRcon
+}
+
+// GetRconAddress returns the address on which the remote console listens.
+func (s *Server) GetRconAddress() string {
+	return fmt.Sprintf("%v:%v", s.properties.Address, s.properties.RconPort)
+}
+
+// GetRconPassword returns the password of the remote console.
+func (s *Server) GetRconPassword() string {
+	return s.properties.RconPassword
+}
+
 // IsServer returns true if the server is currently running.
 func (s *Server) IsRunning() bool {
 	return s.run
@@ -196,6 +307,8 @@ func (s *Server) Start() {
 	}
 
 	log.Info(fmt.Sprintf("Protocol #%v (Minecraft %v)", s.serverVersion.ProtocolVersion, s.serverVersion.Name))
+	log.Info("Supported versions:", supportedVersionsName())
+	s.checkForwarding()
 
 	// listen
 	listen := fmt.Sprintf("%v:%v", s.properties.Address, s.properties.Port)
@@ -207,6 +320,8 @@ func (s *Server) Start() {
 
 	s.load()
 
+	s.loadWorlds()
+
 	s.initialized = true
 
 	// 20 ticks per second
@@ -215,12 +330,22 @@ func (s *Server) Start() {
 	go s.tick()
 	go s.keepAlive()
 
-	s.world = world.NewWorld("default")
+	if s.properties.EnableQuery {
+		go s.startQuery()
+	}
 
 	log.Info("Done start up! Waiting for players to join.")
 	log.Info("Listening on", listen)
 	for s.run {
-		conn, _ := socket.Accept()
+		conn, err := socket.Accept()
+		if err != nil {
+			if !s.run {
+				break
+			}
+			log.Error("Could not accept connection:", err)
+			time.Sleep(acceptErrorDelay)
+			continue
+		}
 		go s.handleConnection(conn)
 	}
 }
@@ -234,7 +359,18 @@ func (s *Server) Reload() {
 func (s *Server) tick() {
 	for s.run {
 		<-s.ticker.C
-		// TODO: Logic
+		s.ticks++
+		s.tickWorlds()
+		if s.ticks%borderDamageInterval == 0 {
+			s.damageBeyondBorders()
+		}
+		s.ForEachPlayerSync(func(c *Connection) {
+			c.streamChunks(maxChunksPerTick)
+		})
+		s.sendBlockChanges()
+		if s.ticks%chunkUnloadInterval == 0 {
+			s.startSaving(s.ticks%autoSaveInterval == 0)
+		}
 	}
 }
 
@@ -245,7 +381,6 @@ func (s *Server) keepAlive() {
 		<-s.keepAliveTicker.C
 
 		id := int64(rand.Intn(0xFFFE))
-		packet := protocol.NewResponse().WriteLong(id).ToRawPacket(protocol.KeepAliveOutgoingPacketId)
 		s.ForEachPlayerSync(func(c *Connection) {
 			c.Lock()
 			list := c.PendingKeepAlives.Elements()
@@ -254,7 +389,7 @@ func (s *Server) keepAlive() {
 					Deadline: time.Now().Add(time.Second * time.Duration(30)),
 					ID:       id,
 				})
-				c.Write(packet)
+				c.WritePacket(&protocol.KeepAlivePacket{ID: id})
 			} else {
 				for _, element := range list {
 					data := element.(player.KeepAliveData)
@@ -275,12 +410,18 @@ func (s *Server) load() {
 	s.loadBanList()
 }
 
-// loadBanList loads the players banned from the server.
+// loadBanList loads the players and the IP addresses banned from the server.
 func (s *Server) loadBanList() {
 	s.BanList = player.NewBanList()
 	if b, _ := util.Exists(banListFile); b {
 		s.BanList.LoadFile(banListFile)
 	}
+	s.IPBanList = player.NewIPBanList()
+	if b, _ := util.Exists(ipBanListFile); b {
+		if err := s.IPBanList.LoadFile(ipBanListFile); err != nil {
+			log.Error("Could not load", ipBanListFile, err)
+		}
+	}
 }
 
 // loadFavicon loads server's favicon that appears in the
@@ -309,49 +450,106 @@ func (s *Server) Stop() {
 	if err != nil {
 		log.Error("Could not save ban list file. If some modifications have been done since the last back-up, they have not been saved. Error's reason:", err)
 	}
+	err = s.IPBanList.SaveFile(ipBanListFile)
+	if err != nil {
+		log.Error("Could not save IP ban list file. If some modifications have been done since the last back-up, they have not been saved. Error's reason:", err)
+	}
+	s.worlds.close()
 	close(s.ExitChan)
 }
 
 // handleConnection handles new connections.
 func (s *Server) handleConnection(conn net.Conn) {
+	if s.properties.ProxyProtocol {
+		proxied, err := readProxyHeader(conn)
+		if err != nil {
+			log.Warn("Closing connection from", conn.RemoteAddr(), "with an invalid PROXY protocol header:", err)
+			conn.Close()
+			return
+		}
+		log.Debug("Connection from", proxied.RemoteAddr(), "proxied by", conn.RemoteAddr())
+		conn = proxied
+	}
+	if !s.acceptConnection(conn) {
+		return
+	}
+	defer s.throttle.release(addressIP(conn.RemoteAddr()))
 	c := NewConnection(conn, s)
+	c.pending = true
+	defer c.finishPending()
+	c.startLoginTimeout()
+	if s.handleLegacyPing(c) {
+		c.Disconnect("")
+		return
+	}
 	AssignHandler(c)
 	go c.write()
 	for c.IsConnected() {
 		read, err := c.Next()
 
 		if err != nil {
-			// just exit
+			if _, ok := err.(*protocol.ProtocolError); ok {
+				s.kickProtocolError(c, err)
+			} else if isTimeout(err) && c.ConnectionState != protocol.PlayState {
+				s.kickTimedOut(c)
+			}
+			// otherwise, just exit
 			break
 		}
 
 		if read != nil {
-			c.PacketHandler.callHandler(read, c)
+			if err = c.countPacket(time.Now()); err != nil {
+				read.Release()
+				s.kickFlooder(c, err)
+				break
+			}
+			err = c.PacketHandler.callHandler(read, c)
 			read.Release()
+			if err != nil {
+				s.kickProtocolError(c, err)
+				break
+			}
 		}
 	}
-	c.Disconnect("")
 	// if the last connection state was the play state, we want to log his disconnection
-	if c.ConnectionState == PlayState {
+	if c.ConnectionState == protocol.PlayState {
+		// the other routines stop writing to the client before it is closed
 		s.playerLock.Lock()
 		delete(s.clients, c.Player.Profile.UUID)
 		s.playerLock.Unlock()
+		c.Disconnect("")
 
 		// broadcast
 		message := c.Player.GetName() + " has left the server."
 		s.BroadcastMessage(message, protocol.DefaultMessageMode)
 		log.Info(message)
+	} else {
+		c.Disconnect("")
 	}
 }
 
-// CanConnect returns true if the given user can connect to
-// the server. Otherwise, returns false and the reason why
+// kickProtocolError disconnects the given client, which has not respected
+// the protocol, and logs the reason.
+func (s *Server) kickProtocolError(c *Connection, err error) {
+	log.Warn("Disconnecting", c.RemoteAddr(), "because of a protocol error:", err)
+	c.Disconnect("Protocol error.")
+}
+
+// CanConnect returns true if the given user, connecting from the given IP,
+// can connect to the server. Otherwise, returns false and the reason why
 // the player cannot connect.
-func (s *Server) CanConnect(username, uuid string) (bool, string) {
+func (s *Server) CanConnect(username, uuid, ip string) (bool, string) {
 	if !util.IsValidUsername(username) {
 		return false, "Your username is invalid."
 	}
 
+	if banned, reason := s.IPBanList.IsBanned(ip); banned {
+		if reason == "" {
+			reason = "Your IP address is banned from this server."
+		}
+		return false, reason
+	}
+
 	if banned, reason := s.BanList.IsBanned(uuid); banned {
 		return false, reason
 	}
@@ -371,27 +569,21 @@ func (s *Server) FinishLogin(profile player.PlayerProfile, connection *Connectio
 	s.playerLock.Lock()
 	s.clients[pl.Profile.UUID] = connection
 	s.playerLock.Unlock()
-	packet := protocol.NewResponse()
+	info := pl.GetLocation().World.Info
+	connection.WritePacket(&protocol.SpawnPositionPacket{X: info.SpawnX, Y: info.SpawnY, Z: info.SpawnZ})
+	connection.sendTimeAndWeather()
+	connection.sendBorder()
 	// send position and look packet
-	{
-		teleportId := int32(rand.Intn(0xFFFE))
-		packet.WriteStructure(protocol.PositionAndLookPacket{
-			Location:   *pl.Location,
-			Flags:      0,
-			TeleportID: teleportId,
-		})
-		connection.PendingTeleportConfirmations.Append(player.TeleportConfirmData{teleportId})
-		connection.Write(packet.ToRawPacket(protocol.OutgoingPlayerPositionAndLookPacketId))
-		packet.Clear()
-	}
+	connection.sendPosition()
+	// the chunks are then sent by the ticks
+	connection.updateChunks()
 	// send abilities packet
-	packet.WriteStructure(protocol.PlayerAbilitiesPacket{
+	connection.WritePacket(&protocol.PlayerAbilitiesPacket{
 		Flags:       0,
 		FlyingSpeed: 1,
 		FovModifier: 1,
 	})
-	connection.Write(packet.ToRawPacket(protocol.PlayerAbilitiesPacketId))
-	packet.Clear()
+	connection.sendHealth()
 	connection.AddPlayers(s.GetAllPlayers())
 	s.ForEachPlayerSync(func(c *Connection) {
 		if c.Player.Profile.UUID != connection.Player.Profile.UUID {
@@ -400,7 +592,7 @@ func (s *Server) FinishLogin(profile player.PlayerProfile, connection *Connectio
 	})
 	// announce login in chat and logs
 	message := profile.Name + " has joined the server."
-	log.Info(message)
+	log.Info(profile.Name, "["+connection.RemoteAddr().String()+"]", "logged in.")
 	s.BroadcastMessage(message, protocol.DefaultMessageMode)
 }
 
@@ -451,13 +643,26 @@ func (s *Server) GetPlayerByUUID(uuid string) (bool, *Connection) {
 	}
 }
 
+// connections returns the connections of the online players.
+func (s *Server) connections() []*Connection {
+	defer s.playerLock.Unlock()
+	s.playerLock.Lock()
+	ret := make([]*Connection, 0, len(s.clients))
+	for _, client := range s.clients {
+		ret = append(ret, client)
+	}
+	return ret
+}
+
 // ForEachPlayer executes the given action for each online player.
 // This function runs a go routine for each player, ands waits
 // the end of each routine.
 func (s *Server) ForEachPlayer(action func(*Connection)) {
-	s.playerLock.Lock()
 	wg := sync.WaitGroup{}
-	for _, client := range s.clients {
+	for _, client := range s.connections() {
+		if !client.IsConnected() {
+			continue
+		}
 		wg.Add(1)
 		go func() {
 			action(client)
@@ -465,16 +670,18 @@ func (s *Server) ForEachPlayer(action func(*Connection)) {
 		}()
 	}
 	wg.Wait()
-	s.playerLock.Unlock()
 }
 
 // ForEachPlayerSync executes the given action for each online player.
+// The action is executed without holding the lock of the players, so
+// that a slow client does not block the other routines; the clients
+// disconnected meanwhile are skipped.
 func (s *Server) ForEachPlayerSync(action func(*Connection)) {
-	s.playerLock.Lock()
-	for _, client := range s.clients {
-		action(client)
+	for _, client := range s.connections() {
+		if client.IsConnected() {
+			action(client)
+		}
 	}
-	s.playerLock.Unlock()
 }
 
 // BroadcastPacket broadcasts the given packet to all the online players (async).
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/protocol"
+	"net"
+	"sync"
+	"time"
+)
+
+// This file limits the connections, so that a single address (or a flood
+// of connections) cannot exhaust server's resources.
+
+const (
+	throttledMessage     = "Connection throttled! Please wait before reconnecting."
+	tooManyConnections   = "Too many connections from your IP address."
+	serverBusyMessage    = "The server is busy, please try again later."
+	acceptErrorDelay     = 50 * time.Millisecond
+	throttleCleanupDelay = time.Minute
+	// the maximal time given to a refused connection to send its handshake
+	refusedHandshakeTimeout = 5 * time.Second
+)
+
+// connectionThrottle struct counts the connections, and the
+// logins of each address.
+type connectionThrottle struct {
+	loginDelay  time.Duration // the minimal delay between two logins from the same IP (0 if none)
+	maxPerIP    int           // the maximal number of connections from the same IP (0 if unlimited)
+	maxPending  int           // the maximal number of connections which are not playing (0 if unlimited)
+	connections map[string]int
+	lastLogins  map[string]time.Time
+	pending     int
+	lastCleanup time.Time
+	lock        sync.Mutex
+}
+
+// newConnectionThrottle creates a new throttle with the given limits.
+func newConnectionThrottle(loginDelay time.Duration, maxPerIP, maxPending int) *connectionThrottle {
+	return &connectionThrottle{
+		loginDelay:  loginDelay,
+		maxPerIP:    maxPerIP,
+		maxPending:  maxPending,
+		connections: make(map[string]int),
+		lastLogins:  make(map[string]time.Time),
+		lastCleanup: time.Now(),
+	}
+}
+
+// acquire counts a new connection from the given IP. Returns false and the
+// reason if the connection must be refused; otherwise, release must be called
+// when the connection is closed, and loginDone when it stops being pending.
+// The number of connections per IP is not limited if countPerIP is false.
+func (t *connectionThrottle) acquire(ip string, countPerIP bool) (bool, string) {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	if t.maxPending > 0 && t.pending >= t.maxPending {
+		return false, serverBusyMessage
+	}
+	if countPerIP && t.maxPerIP > 0 && t.connections[ip] >= t.maxPerIP {
+		return false, tooManyConnections
+	}
+	t.connections[ip]++
+	t.pending++
+	return true, ""
+}
+
+// loginDone signals that a connection is not pending anymore.
+func (t *connectionThrottle) loginDone() {
+	t.lock.Lock()
+	t.pending--
+	t.lock.Unlock()
+}
+
+// release signals that a connection from the given IP has been closed.
+func (t *connectionThrottle) release(ip string) {
+	t.lock.Lock()
+	if t.connections[ip] <= 1 {
+		delete(t.connections, ip)
+	} else {
+		t.connections[ip]--
+	}
+	t.lock.Unlock()
+}
+
+// allowLogin returns true if the given IP has not logged in during
+// the last login delay, and records the login.
+func (t *connectionThrottle) allowLogin(ip string, now time.Time) bool {
+	if t.loginDelay <= 0 {
+		return true
+	}
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	if now.Sub(t.lastCleanup) > throttleCleanupDelay {
+		for key, last := range t.lastLogins {
+			if now.Sub(last) >= t.loginDelay {
+				delete(t.lastLogins, key)
+			}
+		}
+		t.lastCleanup = now
+	}
+	last, ok := t.lastLogins[ip]
+	t.lastLogins[ip] = now
+	return !ok || now.Sub(last) >= t.loginDelay
+}
+
+// acceptConnection counts the given connection. If it must be refused,
+// refuses it and returns false.
+func (s *Server) acceptConnection(conn net.Conn) bool {
+	// behind a proxy, all the connections come from the proxy's address
+	countPerIP := s.GetForwardingMode() == NoForwarding
+	ok, reason := s.throttle.acquire(addressIP(conn.RemoteAddr()), countPerIP)
+	if !ok {
+		log.Info("Refusing connection from", conn.RemoteAddr(), "-", reason)
+		s.refuseConnection(conn, reason)
+	}
+	return ok
+}
+
+// refuseConnection reads the handshake of the given refused connection and
+// closes it. The reason is sent in a login Disconnect packet only if the
+// client wants to log in: the status requests (server list pings) are closed
+// without any answer, since the client cannot read a login packet then.
+func (s *Server) refuseConnection(conn net.Conn, reason string) {
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(refusedHandshakeTimeout))
+	raw, err := NewConnection(conn, s).Next()
+	if err != nil || raw == nil {
+		return
+	}
+	defer raw.Release()
+	packet, err := protocol.Unmarshal(protocol.LatestProtocolVersion, protocol.HandshakeState, protocol.Serverbound, raw)
+	if err != nil {
+		return
+	}
+	if handshake, ok := packet.(*protocol.HandshakePacket); ok && handshake.NextState == protocol.HandshakeLoginNextState {
+		writeLoginDisconnect(conn, reason)
+	}
+}
+
+// writeLoginDisconnect writes, outside of any Connection, a login Disconnect
+// packet with the given reason to the given socket.
+func writeLoginDisconnect(conn net.Conn, reason string) {
+	packet, err := protocol.Marshal(protocol.LatestProtocolVersion, protocol.LoginState, protocol.Clientbound,
+		&protocol.DisconnectPacket{Reason: protocol.ChatComponent{Text: reason}})
+	if err != nil {
+		log.Error("Could not write Disconnect packet:", err)
+		return
+	}
+	data, err := toByteArray(packet, -1)
+	if err != nil {
+		log.Error("Could not write Disconnect packet:", err)
+		return
+	}
+	conn.SetWriteDeadline(time.Now().Add(time.Second))
+	conn.Write(data)
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/protocol"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestThrottleConnectionsPerIP(t *testing.T) {
+	throttle := newConnectionThrottle(0, 2, 0)
+	for i := 0; i < 2; i++ {
+		if ok, _ := throttle.acquire("1.2.3.4", true); !ok {
+			t.Fatal("The first two connections should be accepted")
+		}
+	}
+	if ok, reason := throttle.acquire("1.2.3.4", true); ok || reason != tooManyConnections {
+		t.Error("The third connection should be refused")
+	}
+	if ok, _ := throttle.acquire("1.2.3.4", false); !ok {
+		t.Error("Connections should not be limited per IP behind a proxy")
+	}
+	if ok, _ := throttle.acquire("5.6.7.8", true); !ok {
+		t.Error("Connections from another IP should be accepted")
+	}
+	throttle.release("1.2.3.4")
+	throttle.release("1.2.3.4")
+	if ok, _ := throttle.acquire("1.2.3.4", true); !ok {
+		t.Error("A connection should be accepted once the others are closed")
+	}
+}
+
+func TestThrottlePendingConnections(t *testing.T) {
+	throttle := newConnectionThrottle(0, 0, 1)
+	throttle.acquire("1.2.3.4", true)
+	if ok, reason := throttle.acquire("5.6.7.8", true); ok || reason != serverBusyMessage {
+		t.Error("The pending connections should be limited")
+	}
+	throttle.loginDone()
+	if ok, _ := throttle.acquire("5.6.7.8", true); !ok {
+		t.Error("A connection should be accepted once the other one is playing")
+	}
+}
+
+func TestThrottleLoginDelay(t *testing.T) {
+	throttle := newConnectionThrottle(4*time.Second, 0, 0)
+	now := time.Now()
+	if !throttle.allowLogin("1.2.3.4", now) {
+		t.Error("The first login should be allowed")
+	}
+	if throttle.allowLogin("1.2.3.4", now.Add(time.Second)) {
+		t.Error("A login one second later should be throttled")
+	}
+	if !throttle.allowLogin("5.6.7.8", now.Add(time.Second)) {
+		t.Error("Logins from another IP should be allowed")
+	}
+	if !throttle.allowLogin("1.2.3.4", now.Add(6*time.Second)) {
+		t.Error("A login after the delay should be allowed")
+	}
+}
+
+// handshakeFrame returns the frame of a handshake with the given next state.
+func handshakeFrame(t *testing.T, nextState uint32) []byte {
+	packet, err := protocol.Marshal(protocol.LatestProtocolVersion, protocol.HandshakeState, protocol.Serverbound,
+		&protocol.HandshakePacket{ProtocolVersion: protocol.LatestProtocolVersion, ServerAddress: "localhost",
+			ServerPort: 25565, NextState: nextState})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := toByteArray(packet, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRefuseConnection(t *testing.T) {
+	server := &Server{limits: newConnectionLimits(defaultProperties())}
+	for nextState, answered := range map[uint32]bool{
+		protocol.HandshakeStatusNextState: false,
+		protocol.HandshakeLoginNextState:  true,
+	} {
+		client, socket := net.Pipe()
+		go server.refuseConnection(socket, serverBusyMessage)
+		client.SetDeadline(time.Now().Add(time.Second))
+		if _, err := client.Write(handshakeFrame(t, nextState)); err != nil {
+			t.Fatal("Could not write the handshake:", err)
+		}
+		data, err := ioutil.ReadAll(client)
+		if err != nil {
+			t.Fatal("The connection should be closed, got:", err)
+		}
+		if answered && len(data) == 0 {
+			t.Error("The login should be refused with a Disconnect packet")
+		} else if !answered && len(data) != 0 {
+			t.Error("The status requests should be closed without answer, got", data)
+		}
+		client.Close()
+	}
+}
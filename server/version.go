@@ -1,7 +1,30 @@
 package server
 
+import (
+	"fmt"
+	"github.com/olsdavis/goelan/protocol"
+)
+
+const (
+	serverName = "Goelan"
+)
+
 // ServerVersion struct represents a server's version--its name and protocol version.
 type ServerVersion struct {
 	Name            string
 	ProtocolVersion uint32
 }
+
+// newServerVersion creates the server's version corresponding to the given Minecraft version.
+func newServerVersion(version protocol.MinecraftVersion) ServerVersion {
+	return ServerVersion{serverName + " " + version.Name, version.Protocol}
+}
+
+// supportedVersionsName returns the range of the supported Minecraft versions (e.g. "1.12-1.12.2").
+func supportedVersionsName() string {
+	oldest, latest := protocol.OldestVersion(), protocol.LatestVersion()
+	if oldest == latest {
+		return latest.Name
+	}
+	return fmt.Sprintf("%v-%v", oldest.Name, latest.Name)
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/world"
+)
+
+// This file advances the time and the weather of the worlds, and
+// sends them to their players.
+
+const (
+	// the number of ticks between two Time Update packets
+	timeUpdateInterval = 20
+)
+
+// initTimeAndWeather sets the game rules, the time and the weather of
+// the given world from its properties.
+func initTimeAndWeather(w *world.World, config WorldProperties) {
+	for name, value := range config.GameRules {
+		w.Info.SetGameRule(name, value)
+	}
+	if config.Time != nil {
+		w.SetTime(*config.Time)
+	}
+	if config.Weather != "" {
+		if weather, ok := world.ParseWeather(config.Weather); ok {
+			w.SetWeather(weather, 0)
+		} else {
+			log.Warn("Unknown weather", config.Weather, "of world", w.Name+": it must be clear, rain or thunder.")
+		}
+	}
+}
+
+// tickWorlds advances the time and the weather of the loaded worlds,
+// and sends their changes to the players.
+func (s *Server) tickWorlds() {
+	for _, w := range s.GetWorlds() {
+		change := w.Tick()
+		packets := weatherPackets(change)
+		if s.ticks%timeUpdateInterval == 0 {
+			packets = append(packets, timeUpdatePacket(w))
+		}
+		if len(packets) == 0 {
+			continue
+		}
+		s.ForEachPlayerSync(func(c *Connection) {
+			if c.Player.GetLocation().World == w {
+				for _, packet := range packets {
+					c.WritePacket(packet)
+				}
+			}
+		})
+	}
+}
+
+// weatherPackets returns the Change Game State packets describing the
+// given change of the weather, as vanilla sends them.
+func weatherPackets(change world.WeatherChange) []protocol.Packet {
+	if !change.Toggled && !change.Changed {
+		return nil
+	}
+	ret := make([]protocol.Packet, 0, 3)
+	if change.Toggled {
+		reason := uint8(protocol.ChangeGameStateEndRain)
+		if change.Raining {
+			reason = protocol.ChangeGameStateBeginRain
+		}
+		ret = append(ret, &protocol.ChangeGameStatePacket{Reason: reason})
+	}
+	return append(ret,
+		&protocol.ChangeGameStatePacket{Reason: protocol.ChangeGameStateRainStrength, Value: change.RainStrength},
+		&protocol.ChangeGameStatePacket{Reason: protocol.ChangeGameStateThunderStrength, Value: change.ThunderStrength},
+	)
+}
+
+// timeUpdatePacket returns the Time Update packet of the given world.
+func timeUpdatePacket(w *world.World) *protocol.TimeUpdatePacket {
+	age, dayTime := w.GetTime()
+	// the clients do not advance a negative time
+	if !w.IsDaylightCycle() {
+		dayTime = -dayTime
+		if dayTime == 0 {
+			dayTime = -1
+		}
+	}
+	return &protocol.TimeUpdatePacket{WorldAge: age, TimeOfDay: dayTime}
+}
+
+// sendTimeAndWeather sends the time and the weather of the player's world.
+func (c *Connection) sendTimeAndWeather() {
+	w := c.Player.GetLocation().World
+	c.WritePacket(timeUpdatePacket(w))
+	rain, thunder := w.GetWeatherStrengths()
+	if rain > 0 {
+		for _, packet := range weatherPackets(world.WeatherChange{
+			Toggled:         true,
+			Raining:         true,
+			RainStrength:    rain,
+			ThunderStrength: thunder,
+		}) {
+			c.WritePacket(packet)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/world"
+	"reflect"
+	"testing"
+)
+
+func TestWeatherPackets(t *testing.T) {
+	if packets := weatherPackets(world.WeatherChange{}); len(packets) != 0 {
+		t.Error("No packet should be sent without change, got", packets)
+	}
+	packets := weatherPackets(world.WeatherChange{Toggled: true, Raining: false, RainStrength: 0.19, ThunderStrength: 0})
+	expected := []protocol.Packet{
+		&protocol.ChangeGameStatePacket{Reason: protocol.ChangeGameStateEndRain},
+		&protocol.ChangeGameStatePacket{Reason: protocol.ChangeGameStateRainStrength, Value: 0.19},
+		&protocol.ChangeGameStatePacket{Reason: protocol.ChangeGameStateThunderStrength, Value: 0},
+	}
+	if !reflect.DeepEqual(packets, expected) {
+		t.Errorf("Expected %v, got %v", expected, packets)
+	}
+}
+
+func TestTimeUpdatePacket(t *testing.T) {
+	w := world.NewWorld("lobby")
+	w.Info = world.NewWorldInfo("lobby", 0)
+	w.SetTime(world.Noon)
+	if p := timeUpdatePacket(w); p.TimeOfDay != world.Noon {
+		t.Error("Expected the time", world.Noon, "got", p.TimeOfDay)
+	}
+	w.Info.SetGameRule(world.DaylightCycleRule, "false")
+	if p := timeUpdatePacket(w); p.TimeOfDay != -world.Noon {
+		t.Error("The time should be negative when the daylight cycle is stopped, got", p.TimeOfDay)
+	}
+	w.SetTime(0)
+	if p := timeUpdatePacket(w); p.TimeOfDay != -1 {
+		t.Error("The stopped time 0 should be sent as -1, got", p.TimeOfDay)
+	}
+}
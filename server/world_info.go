@@ -0,0 +1,94 @@
+package server
+
+import (
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/player"
+	"github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/world"
+	"github.com/olsdavis/goelan/world/generator"
+)
+
+const (
+	// the flag added to the gamemode of the Join Game packet in hardcore mode
+	hardcoreFlag = 0x08
+)
+
+// initWorldInfo creates the metadata of the given world if it has not
+// got any level.dat yet, from the given properties and the ones of the
+// server, and its generator.
+func (s *Server) initWorldInfo(w *world.World, config WorldProperties) {
+	if w.Info == nil {
+		info := world.NewWorldInfo(w.Name, world.ParseSeed(config.LevelSeed))
+		info.GameType = int32(s.properties.Gamemode)
+		info.Difficulty = world.Difficulty(s.properties.Difficulty)
+		info.GeneratorName = config.LevelType
+		info.GeneratorOptions = config.GeneratorSettings
+		w.Info = info
+		log.Info("Creating world", w.Name, "with seed", info.Seed)
+	} else {
+		log.Info("Seed of world", w.Name+":", w.Info.Seed)
+	}
+	initGenerator(w)
+
+	info := w.Info
+	if len(config.Spawn) != 0 && len(config.Spawn) != 3 {
+		log.Warn("The spawn of world", w.Name, "must be 3 coordinates (x, y, z), got", config.Spawn)
+	}
+	if len(config.Spawn) == 3 {
+		info.SpawnX, info.SpawnY, info.SpawnZ = config.Spawn[0], config.Spawn[1], config.Spawn[2]
+	} else if info.Initialized {
+		return
+	} else if chunk := w.GetChunk(info.SpawnX>>4, info.SpawnZ>>4); chunk != nil {
+		// spawn on the ground
+		info.SpawnY = int32(chunk.HighestBlock(int(info.SpawnX&0x0F), int(info.SpawnZ&0x0F)) + 1)
+	}
+	info.Initialized = true
+	if err := info.Save(w.Directory); err != nil {
+		log.Error("Could not save the metadata of world", w.Name+":", err)
+	}
+}
+
+// initGenerator creates the generator of the given world, or falls
+// back to the default flat generator if it is unknown.
+func initGenerator(w *world.World) {
+	info := w.Info
+	gen, err := generator.New(info.GeneratorName, info.Seed, info.GeneratorOptions)
+	if err != nil {
+		log.Error("Could not create the generator of world", w.Name+":", err, "- using the default flat generator instead.")
+		gen = &generator.FlatGenerator{}
+	}
+	w.Generator = gen
+}
+
+// joinGamePacket returns the Join Game packet sent to the given player.
+func (s *Server) joinGamePacket(pl *player.Player) *protocol.JoinGamePacket {
+	w := pl.GetLocation().World
+	info := w.Info
+	gameMode := uint8(pl.GameMode)
+	if info.Hardcore {
+		gameMode |= hardcoreFlag
+	}
+	maxPlayers := s.GetMaxPlayers()
+	if maxPlayers > 255 {
+		maxPlayers = 255
+	}
+	return &protocol.JoinGamePacket{
+		EntityID:         0,
+		GameMode:         gameMode,
+		Dimension:        int32(w.Dimension),
+		Difficulty:       uint8(info.Difficulty),
+		MaxPlayers:       uint8(maxPlayers),
+		LevelType:        levelType(info.GeneratorName),
+		ReducedDebugInfo: false,
+	}
+}
+
+// levelType returns the level type sent to the clients for the given
+// generator (the clients only use it to render the sky).
+func levelType(generatorName string) string {
+	switch generatorName {
+	case "flat", "largeBiomes", "amplified", "customized", "debug_all_block_states":
+		return generatorName
+	}
+	return "default"
+}
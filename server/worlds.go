@@ -0,0 +1,267 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/player"
+	"github.com/olsdavis/goelan/protocol"
+	"github.com/olsdavis/goelan/world"
+	"github.com/olsdavis/goelan/world/generator"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// This file loads the worlds hosted by the server, and moves
+// the players from one world to another.
+
+// WorldProperties struct represents the settings of a world of the
+// "worlds" tables of the properties file.
+type WorldProperties struct {
+	Name      string `toml:"name"`      // the directory of the world
+	Dimension string `toml:"dimension"` // overworld (default), nether or the_end
+	// the spawn point (x, y, z); if not set, the one of level.dat
+	Spawn []int32 `toml:"spawn,omitempty"`
+	// the game rules set when the world is loaded, such as doDaylightCycle
+	GameRules map[string]string `toml:"game-rules,omitempty"`
+	// the time of the day and the weather (clear, rain or thunder)
+	// set when the world is loaded; if not set, the ones of level.dat
+	Time    *int64 `toml:"time,omitempty"`
+	Weather string `toml:"weather,omitempty"`
+	// the following properties are only used when creating the world
+	LevelSeed         string `toml:"level-seed"` // a number or any text (random if empty)
+	LevelType         string `toml:"level-type"` // the name of the generator
+	GeneratorSettings string `toml:"generator-settings"`
+}
+
+// worldManager struct holds the worlds hosted by the server,
+// and the pools loading and generating their chunks.
+type worldManager struct {
+	worlds []*world.World // the default world first
+	pools  map[*world.World]*world.ChunkPool
+	lock   sync.RWMutex
+}
+
+// newWorldManager creates a manager without any world.
+func newWorldManager() *worldManager {
+	return &worldManager{
+		worlds: make([]*world.World, 0),
+		pools:  make(map[*world.World]*world.ChunkPool),
+	}
+}
+
+// add adds the given world, and creates its pool with the given number of
+// workers. The first world added is the default one. Returns false if a
+// world of the same name is already loaded.
+func (m *worldManager) add(w *world.World, workers int) bool {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+	for _, loaded := range m.worlds {
+		if loaded.Name == w.Name {
+			return false
+		}
+	}
+	m.worlds = append(m.worlds, w)
+	m.pools[w] = world.NewChunkPool(w, workers)
+	return true
+}
+
+// get returns the world of the given name, or nil.
+func (m *worldManager) get(name string) *world.World {
+	defer m.lock.RUnlock()
+	m.lock.RLock()
+	for _, w := range m.worlds {
+		if w.Name == name {
+			return w
+		}
+	}
+	return nil
+}
+
+// getDefault returns the world where the players spawn, or nil if none is loaded.
+func (m *worldManager) getDefault() *world.World {
+	defer m.lock.RUnlock()
+	m.lock.RLock()
+	if len(m.worlds) == 0 {
+		return nil
+	}
+	return m.worlds[0]
+}
+
+// all returns the loaded worlds, the default one first.
+func (m *worldManager) all() []*world.World {
+	defer m.lock.RUnlock()
+	m.lock.RLock()
+	ret := make([]*world.World, len(m.worlds))
+	copy(ret, m.worlds)
+	return ret
+}
+
+// getPool returns the pool of the given world, or nil if it is not loaded.
+func (m *worldManager) getPool(w *world.World) *world.ChunkPool {
+	defer m.lock.RUnlock()
+	m.lock.RLock()
+	return m.pools[w]
+}
+
+// close stops the pools, and saves and closes the worlds.
+func (m *worldManager) close() {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+	for _, w := range m.worlds {
+		m.pools[w].Close()
+		if err := w.Close(); err != nil {
+			log.Error("Could not save world", w.Name+". Some chunks may not have been saved. Error's reason:", err)
+		}
+	}
+	m.worlds = m.worlds[:0]
+	m.pools = make(map[*world.World]*world.ChunkPool)
+}
+
+// worldProperties returns the properties of the worlds to load: the
+// default world, described by the level properties, then the others.
+// A "worlds" table named as the default world sets its spawn, game
+// rules, time and weather.
+func (s *Server) worldProperties() []WorldProperties {
+	ret := []WorldProperties{{
+		Name:              s.properties.LevelName,
+		Dimension:         world.Overworld.String(),
+		LevelSeed:         s.properties.LevelSeed,
+		LevelType:         s.properties.LevelType,
+		GeneratorSettings: s.properties.GeneratorSettings,
+	}}
+	for _, config := range s.properties.Worlds {
+		if config.Name != s.properties.LevelName {
+			ret = append(ret, config)
+			continue
+		}
+		ret[0].Spawn = config.Spawn
+		ret[0].GameRules = config.GameRules
+		ret[0].Time = config.Time
+		ret[0].Weather = config.Weather
+	}
+	return ret
+}
+
+// loadWorlds loads the worlds of the properties. Panics if the
+// default world cannot be loaded.
+func (s *Server) loadWorlds() {
+	workers := s.properties.ChunkWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	for i, config := range s.worldProperties() {
+		w, err := s.loadWorld(config)
+		if err != nil {
+			if i == 0 {
+				panic(fmt.Sprintf("Could not open world: %v", err))
+			}
+			log.Error("Could not open world", config.Name+":", err)
+			continue
+		}
+		if !s.worlds.add(w, workers) {
+			log.Error("Could not load world", config.Name+": another world is named", w.Name)
+			w.Close()
+		}
+	}
+}
+
+// loadWorld opens the world described by the given properties, and
+// creates its metadata if it is new.
+func (s *Server) loadWorld(config WorldProperties) (*world.World, error) {
+	if config.Name == "" {
+		return nil, errors.New("the world has no name")
+	}
+	dimension := world.Overworld
+	if config.Dimension != "" {
+		var ok bool
+		if dimension, ok = world.ParseDimension(config.Dimension); !ok {
+			return nil, fmt.Errorf("unknown dimension %v", config.Dimension)
+		}
+	}
+	if config.LevelType == "" {
+		config.LevelType = generator.DefaultName
+	}
+	w, err := world.OpenWorld(config.Name)
+	if err != nil {
+		return nil, err
+	}
+	w.Dimension = dimension
+	log.Info("Loading world", w.Name, "("+dimension.String()+") from", w.Directory)
+	s.initWorldInfo(w, config)
+	initTimeAndWeather(w, config)
+	return w, nil
+}
+
+// GetWorld returns the default world: the one where the players spawn.
+func (s *Server) GetWorld() *world.World {
+	return s.worlds.getDefault()
+}
+
+// GetWorldByName returns the loaded world of the given name, or nil.
+func (s *Server) GetWorldByName(name string) *world.World {
+	return s.worlds.get(name)
+}
+
+// GetWorlds returns the loaded worlds, the default one first.
+func (s *Server) GetWorlds() []*world.World {
+	return s.worlds.all()
+}
+
+// GetChunkPool returns the pool which loads and generates
+// the chunks of the given world, or nil if it is not loaded.
+func (s *Server) GetChunkPool(w *world.World) *world.ChunkPool {
+	return s.worlds.getPool(w)
+}
+
+// Teleport moves the player to the given location, which may be in
+// another world. The client unloads the chunks of its previous world.
+func (c *Connection) Teleport(location *world.Location) {
+	previous := c.Player.GetLocation().World
+	target := location.World
+	if target != previous {
+		info := target.Info
+		respawn := respawnPacket(target, c.Player)
+		// the client keeps its chunks if it respawns in the same dimension
+		if target.Dimension == previous.Dimension {
+			other := *respawn
+			other.Dimension = int32(world.Nether)
+			if target.Dimension == world.Nether {
+				other.Dimension = int32(world.Overworld)
+			}
+			c.WritePacket(&other)
+		}
+		c.WritePacket(respawn)
+		c.WritePacket(&protocol.SpawnPositionPacket{X: info.SpawnX, Y: info.SpawnY, Z: info.SpawnZ})
+	}
+	c.Player.SetLocation(location)
+	if target != previous {
+		c.sendTimeAndWeather()
+		c.sendBorder()
+	}
+	c.sendPosition()
+	c.updateChunks()
+}
+
+// respawnPacket returns the Respawn packet moving the given player to the given world.
+func respawnPacket(w *world.World, pl *player.Player) *protocol.RespawnPacket {
+	return &protocol.RespawnPacket{
+		Dimension:  int32(w.Dimension),
+		Difficulty: uint8(w.Info.Difficulty),
+		GameMode:   uint8(pl.GameMode),
+		LevelType:  levelType(w.Info.GeneratorName),
+	}
+}
+
+// sendPosition sends the location of the player to the client, which
+// must confirm it before its own positions are accepted again.
+func (c *Connection) sendPosition() {
+	teleportId := int32(rand.Intn(0xFFFE))
+	c.WritePacket(&protocol.PositionAndLookPacket{
+		Location:   c.Player.GetLocation(),
+		Flags:      0,
+		TeleportID: teleportId,
+	})
+	c.PendingTeleportConfirmations.Append(player.TeleportConfirmData{ID: teleportId})
+}
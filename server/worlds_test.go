@@ -0,0 +1,84 @@
+package server
+
+import (
+	"bytes"
+	"github.com/BurntSushi/toml"
+	"github.com/olsdavis/goelan/world"
+	"reflect"
+	"testing"
+)
+
+func TestWorldManager(t *testing.T) {
+	manager := newWorldManager()
+	defer manager.close()
+	overworld, nether := world.NewWorld("world"), world.NewWorld("world_nether")
+	nether.Dimension = world.Nether
+	if !manager.add(overworld, 1) || !manager.add(nether, 1) {
+		t.Fatal("Could not add the worlds")
+	}
+	if manager.add(world.NewWorld("world_nether"), 1) {
+		t.Error("Two worlds cannot have the same name")
+	}
+	if manager.getDefault() != overworld {
+		t.Error("The first world should be the default one")
+	}
+	if manager.get("world_nether") != nether || manager.get("world_end") != nil {
+		t.Error("Wrong world found by name")
+	}
+	if manager.getPool(nether) == nil || manager.getPool(nether) == manager.getPool(overworld) {
+		t.Error("Each world should have its own pool")
+	}
+	if worlds := manager.all(); !reflect.DeepEqual(worlds, []*world.World{overworld, nether}) {
+		t.Error("Wrong worlds:", worlds)
+	}
+}
+
+func TestWorldProperties(t *testing.T) {
+	properties := defaultProperties()
+	_, err := toml.Decode(`
+level-name = "lobby"
+
+[[worlds]]
+name = "world_nether"
+dimension = "nether"
+level-type = "flat"
+spawn = [0, 70, 0]
+
+[[worlds]]
+name = "world_the_end"
+dimension = "the_end"
+
+[[worlds]]
+name = "lobby"
+time = 6000
+weather = "clear"
+game-rules = { doDaylightCycle = "false", doWeatherCycle = "false" }
+`, &properties)
+	if err != nil {
+		t.Fatal("Could not decode the properties:", err)
+	}
+	s := &Server{properties: properties}
+	worlds := s.worldProperties()
+	if len(worlds) != 3 {
+		t.Fatalf("Expected 3 worlds, got %v", len(worlds))
+	}
+	if worlds[0].Name != "lobby" || worlds[0].LevelType != properties.LevelType {
+		t.Errorf("The default world should be described by the level properties, got %+v", worlds[0])
+	}
+	if worlds[0].Time == nil || *worlds[0].Time != world.Noon || worlds[0].Weather != "clear" || worlds[0].GameRules[world.DaylightCycleRule] != "false" {
+		t.Errorf("The table of the default world should set its time, weather and game rules, got %+v", worlds[0])
+	}
+	expected := WorldProperties{Name: "world_nether", Dimension: "nether", LevelType: "flat", Spawn: []int32{0, 70, 0}}
+	if !reflect.DeepEqual(worlds[1], expected) {
+		t.Errorf("Expected %+v, got %+v", expected, worlds[1])
+	}
+
+	// the default properties have no additional world
+	buf := new(bytes.Buffer)
+	if err = toml.NewEncoder(buf).Encode(defaultProperties()); err != nil {
+		t.Fatal("Could not encode the default properties:", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("worlds")) {
+		t.Error("The default properties should not contain any world")
+	}
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"encoding/binary"
 	"fmt"
 	"errors"
 	"strings"
@@ -14,25 +15,17 @@ type UUID struct {
 	LeastSig             int64 // the least significant bits of the UUID
 }
 
+// NameToUUID returns the name-based (version 3) UUID of the given string.
 func NameToUUID(str string) (*UUID, error) {
-	hash := md5.New()
-	b := hash.Sum([]byte(str))
+	b := md5.Sum([]byte(str))
 	b[6] &= 0x0F
 	b[6] |= 0x30
 	b[8] &= 0x3F
 	b[8] |= 0x80
 
-	var msb byte = 0
-	var lsb byte = 0
-	for i := 0; i < 8; i++ {
-		msb = (msb << 8) | (b[i] & 0xFF)
-	}
-	for i := 8; i < 16; i++ {
-		lsb = (lsb << 8) | (b[i] & 0xFF)
-	}
 	return &UUID{
-		MostSig:              int64(msb),
-		LeastSig:             int64(lsb),
+		MostSig:              int64(binary.BigEndian.Uint64(b[:8])),
+		LeastSig:             int64(binary.BigEndian.Uint64(b[8:])),
 	}, nil
 }
 
@@ -61,12 +54,11 @@ func StringToUUID(str string) (*UUID, error) {
 	return uuid, nil
 }
 
+// String returns the UUID with the hyphens.
 func (uuid *UUID) String() string {
-	return digits(byte(uuid.MostSig) >> 32, 8) + "-" +
-		digits(byte(uuid.MostSig >> 16), 4) + "-" +
-		digits(byte(uuid.MostSig >> 48), 4) + "-" +
-		digits(byte(uuid.LeastSig >> 48), 4) + "-" +
-		digits(byte(uuid.LeastSig), 12)
+	msb, lsb := uint64(uuid.MostSig), uint64(uuid.LeastSig)
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", msb>>32, (msb>>16)&0xFFFF, msb&0xFFFF,
+		lsb>>48, lsb&0xFFFFFFFFFFFF)
 }
 
 func mustDecodeHex(str string) int64 {
@@ -77,11 +69,6 @@ func mustDecodeHex(str string) int64 {
 	return val
 }
 
-func digits(val byte, digits uint) string {
-	var hi byte = 1 << (digits * 4)
-	return strconv.FormatInt(int64(hi | (val & (hi - 1))), 16)
-}
-
 // ToHyphenUUID returns the uuid with the hyphens.
 func ToHyphenUUID(uuid string) string {
 	// 8 - 4 - 4 - 4 - 12
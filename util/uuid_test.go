@@ -13,6 +13,23 @@ func TestToHypenUUID(t *testing.T) {
 	}
 }
 
+func TestUUIDString(t *testing.T) {
+	parsed, err := StringToUUID(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str := parsed.String(); str != expected {
+		t.Error("Expected " + expected + " and got: " + str)
+	}
+}
+
+func TestNameToUUID(t *testing.T) {
+	offline, _ := NameToUUID("OfflinePlayer:Notch")
+	if str := offline.String(); str != "b50ad385-829d-3141-a216-7e7d7539ba7f" {
+		t.Error("Expected b50ad385-829d-3141-a216-7e7d7539ba7f and got: " + str)
+	}
+}
+
 func TestIsValidUsername(t *testing.T) {
 	valid := "Nathanael"
 	if !IsValidUsername(valid) {
@@ -0,0 +1,204 @@
+package world
+
+import (
+	"fmt"
+	"github.com/olsdavis/goelan/nbt"
+	"github.com/olsdavis/goelan/world/val"
+	"time"
+)
+
+// This file converts the chunks from and to the NBT format of the
+// Anvil region files, as written by vanilla 1.12.
+
+const (
+	// DataVersion is the version of the data written by vanilla 1.12.2.
+	DataVersion = 1343
+	// the version of the chunk format, written in the "V" tag
+	chunkFormatVersion = 1
+)
+
+// handledLevelTags are the tags of the Level compound which are written
+// from the chunk; the other ones are kept as they were loaded.
+var handledLevelTags = map[string]bool{
+	"xPos":           true,
+	"zPos":           true,
+	"LastUpdate":     true,
+	"LightPopulated": true,
+	"V":              true,
+	"Biomes":         true,
+	"HeightMap":      true,
+	"Sections":       true,
+}
+
+// chunkToNBT returns the given chunk as stored in region files.
+func chunkToNBT(c *Chunk) nbt.Compound {
+	sections := nbt.NewList(nbt.TagCompound)
+	for y, section := range c.Sections {
+		if section == nil || section.IsEmpty() {
+			continue
+		}
+		sections.Add(sectionToNBT(section, y))
+	}
+
+	heightMap := make(nbt.IntArray, val.ChunkSize*val.ChunkSize)
+	for z := 0; z < val.ChunkSize; z++ {
+		for x := 0; x < val.ChunkSize; x++ {
+			heightMap[z<<4|x] = int32(c.HighestBlock(x, z) + 1)
+		}
+	}
+
+	biomes := make(nbt.ByteArray, len(c.Biomes))
+	copy(biomes, c.Biomes[:])
+	level := nbt.Compound{
+		"xPos":             nbt.Int(c.X),
+		"zPos":             nbt.Int(c.Z),
+		"LastUpdate":       nbt.Long(time.Now().Unix()),
+		"InhabitedTime":    nbt.Long(0),
+		"TerrainPopulated": nbt.Byte(1),
+		"LightPopulated":   nbt.Byte(1),
+		"V":                nbt.Byte(chunkFormatVersion),
+		"Biomes":           biomes,
+		"HeightMap":        heightMap,
+		"Sections":         sections,
+		"Entities":         nbt.NewList(nbt.TagCompound),
+		"TileEntities":     nbt.NewList(nbt.TagCompound),
+	}
+	// the defaults above are replaced by the loaded tags, if any
+	for name, tag := range c.level {
+		level[name] = tag
+	}
+	return nbt.Compound{
+		"DataVersion": nbt.Int(DataVersion),
+		"Level":       level,
+	}
+}
+
+// sectionToNBT returns the given section: the 8 lower bits of the block ids in
+// "Blocks", the 4 upper ones in "Add" (if any), and the metadata in "Data".
+func sectionToNBT(section *Section, y int) nbt.Compound {
+	blocks := make(nbt.ByteArray, val.SectionVolume)
+	var add, data NibbleArray
+	hasAdd := false
+	for i := 0; i < val.SectionVolume; i++ {
+		state := section.GetBlockStateAt(i)
+		blocks[i] = byte(state.ID())
+		if id := state.ID() >> 8; id != 0 {
+			add.Set(i, byte(id))
+			hasAdd = true
+		}
+		data.Set(i, state.Metadata())
+	}
+
+	ret := nbt.Compound{
+		"Y":          nbt.Byte(y),
+		"Blocks":     blocks,
+		"Data":       nbt.ByteArray(data[:]),
+		"BlockLight": nbt.ByteArray(append([]byte(nil), section.BlockLight[:]...)),
+		"SkyLight":   nbt.ByteArray(append([]byte(nil), section.SkyLight[:]...)),
+	}
+	if hasAdd {
+		ret["Add"] = nbt.ByteArray(add[:])
+	}
+	return ret
+}
+
+// chunkFromNBT reads a chunk stored in a region file.
+func chunkFromNBT(root nbt.Compound) (*Chunk, error) {
+	level := root.GetCompound("Level")
+	if level == nil {
+		return nil, fmt.Errorf("missing Level tag")
+	}
+	c := NewChunk(level.GetInt("xPos"), level.GetInt("zPos"))
+	// the handled tags are not kept, not to hold the sections twice
+	c.level = make(nbt.Compound)
+	for name, tag := range level {
+		if !handledLevelTags[name] {
+			c.level[name] = tag
+		}
+	}
+	if biomes := level.GetByteArray("Biomes"); len(biomes) == len(c.Biomes) {
+		copy(c.Biomes[:], biomes)
+	}
+
+	sections := level.GetList("Sections")
+	if sections == nil {
+		return c, nil
+	}
+	for _, tag := range sections.Elements {
+		compound, ok := tag.(nbt.Compound)
+		if !ok {
+			return nil, fmt.Errorf("invalid section %v", tag.Type())
+		}
+		y := int(compound.GetByte("Y"))
+		if y < 0 || y >= val.SectionsPerChunk {
+			// vanilla may store light-only sections out of the world
+			continue
+		}
+		section, err := sectionFromNBT(compound)
+		if err != nil {
+			return nil, fmt.Errorf("section %v: %v", y, err)
+		}
+		c.Sections[y] = section
+	}
+	return c, nil
+}
+
+// sectionFromNBT reads a section written by sectionToNBT.
+func sectionFromNBT(compound nbt.Compound) (*Section, error) {
+	blocks := compound.GetByteArray("Blocks")
+	if len(blocks) != val.SectionVolume {
+		return nil, fmt.Errorf("invalid Blocks length %v", len(blocks))
+	}
+	var add, data NibbleArray
+	if err := readNibbles(compound, "Data", &data, true); err != nil {
+		return nil, err
+	}
+	if err := readNibbles(compound, "Add", &add, false); err != nil {
+		return nil, err
+	}
+
+	section := NewSection()
+	if err := readNibbles(compound, "BlockLight", &section.BlockLight, false); err != nil {
+		return nil, err
+	}
+	if err := readNibbles(compound, "SkyLight", &section.SkyLight, false); err != nil {
+		return nil, err
+	}
+	for i := 0; i < val.SectionVolume; i++ {
+		id := int(add.Get(i))<<8 | int(blocks[i])
+		section.SetBlockState(i&0x0F, i>>8, (i>>4)&0x0F, BlockState(id<<4|int(data.Get(i))))
+	}
+	return section, nil
+}
+
+// readNibbles copies the nibble array of the given name to the given array.
+func readNibbles(compound nbt.Compound, name string, array *NibbleArray, required bool) error {
+	tag, ok := compound[name]
+	if !ok {
+		if required {
+			return fmt.Errorf("missing %v tag", name)
+		}
+		return nil
+	}
+	data, ok := tag.(nbt.ByteArray)
+	if !ok || len(data) != len(array) {
+		return fmt.Errorf("invalid %v tag", name)
+	}
+	copy(array[:], data)
+	return nil
+}
+
+// HighestBlock returns the height of the highest block which is not
+// air at the given coordinates, or -1 if there is none.
+func (c *Chunk) HighestBlock(x, z int) int {
+	for y := val.ChunkHeight - 1; y >= 0; y-- {
+		if c.Sections[y/val.SectionHeight] == nil {
+			y -= y % val.SectionHeight
+			continue
+		}
+		if c.GetBlockState(x, y, z) != Air {
+			return y
+		}
+	}
+	return -1
+}
@@ -1,21 +1,18 @@
 package world
 
 import (
-	"bytes"
 	"github.com/olsdavis/goelan/material"
 )
 
 type Block struct {
-	location   *Location3i
-	material   material.Material
-	BlockState byte
+	location *Location3i
+	State    BlockState // the material and the metadata of the block
 }
 
-func NewBlock(loc *Location3i, mat material.Material, state byte) *Block {
+func NewBlock(loc *Location3i, mat material.Material, metadata byte) *Block {
 	return &Block{
-		location:   loc,
-		material:   mat,
-		BlockState: state,
+		location: loc,
+		State:    NewBlockState(mat, metadata),
 	}
 }
 
@@ -24,9 +21,10 @@ func (b *Block) GetLocation() *Location3i {
 }
 
 func (b *Block) GetMaterial() material.Material {
-	return b.material
+	return b.State.Material()
 }
 
-func (b *Block) writeBlock(buffer *bytes.Buffer) {
-
+// GetMetadata returns the metadata of the block.
+func (b *Block) GetMetadata() byte {
+	return b.State.Metadata()
 }
@@ -0,0 +1,176 @@
+package world
+
+import (
+	"math"
+	"time"
+)
+
+// This file handles the world border: a square, centered on a point,
+// which the players cannot cross, and which hurts the ones beyond it.
+// Its diameter may move towards another one over time.
+
+const (
+	// the default border of vanilla, which is as large as the world
+	DefaultBorderDiameter       = 60000000
+	DefaultBorderSafeZone       = 5
+	DefaultBorderDamagePerBlock = 0.2
+	DefaultBorderWarningBlocks  = 5
+	DefaultBorderWarningTime    = 15
+	// the smallest diameter of the border
+	MinBorderDiameter = 1
+	// the distance from the center beyond which the players coming from a
+	// portal are moved inside the world (sent to the clients)
+	PortalTeleportBoundary = 29999984
+)
+
+// Border struct is the state of the border of a world at a given time.
+type Border struct {
+	CenterX, CenterZ float64
+	Diameter         float64       // the current diameter
+	TargetDiameter   float64       // the diameter reached at the end of the resizing
+	Remaining        time.Duration // the remaining time of the resizing (0 if none)
+	SafeZone         float64       // the distance beyond the border where the players are not hurt
+	DamagePerBlock   float64       // the damage per second, per block beyond the safe zone
+	WarningBlocks    int32         // the distance to the border at which the clients show a warning
+	WarningTime      int32         // the clients show a warning if a shrinking border reaches them within this time, in seconds
+}
+
+// Distance returns the distance between the given point and the closest
+// side of the border: positive inside the border, negative beyond it.
+func (b Border) Distance(x, z float64) float64 {
+	radius := b.Diameter / 2
+	return math.Min(
+		math.Min(x-(b.CenterX-radius), b.CenterX+radius-x),
+		math.Min(z-(b.CenterZ-radius), b.CenterZ+radius-z),
+	)
+}
+
+// Contains returns true if the given point is inside the border.
+func (b Border) Contains(x, z float64) bool {
+	return b.Distance(x, z) >= 0
+}
+
+// Damage returns the damage per second taken by a player at the given
+// point: at least 1 beyond the safe zone, as vanilla does, 0 otherwise.
+func (b Border) Damage(x, z float64) float64 {
+	beyond := -(b.Distance(x, z) + b.SafeZone)
+	if beyond <= 0 || b.DamagePerBlock <= 0 {
+		return 0
+	}
+	return math.Max(1, math.Floor(beyond*b.DamagePerBlock))
+}
+
+// resetBorder sets the default border of vanilla.
+func (info *WorldInfo) resetBorder() {
+	info.BorderCenterX, info.BorderCenterZ = 0, 0
+	info.BorderSize = DefaultBorderDiameter
+	info.BorderSizeLerpTarget = DefaultBorderDiameter
+	info.BorderSizeLerpTime = 0
+	info.BorderSafeZone = DefaultBorderSafeZone
+	info.BorderDamagePerBlock = DefaultBorderDamagePerBlock
+	info.BorderWarningBlocks = DefaultBorderWarningBlocks
+	info.BorderWarningTime = DefaultBorderWarningTime
+}
+
+// GetBorder returns the current state of the border of the world.
+func (w *World) GetBorder() Border {
+	defer w.borderLock.Unlock()
+	w.borderLock.Lock()
+	return w.borderAt(time.Now())
+}
+
+// borderAt returns the state of the border at the given time, without locking.
+func (w *World) borderAt(now time.Time) Border {
+	info := w.Info
+	if info == nil {
+		info = &WorldInfo{}
+		info.resetBorder()
+	}
+	border := Border{
+		CenterX:        info.BorderCenterX,
+		CenterZ:        info.BorderCenterZ,
+		Diameter:       info.BorderSize,
+		TargetDiameter: info.BorderSize,
+		SafeZone:       info.BorderSafeZone,
+		DamagePerBlock: info.BorderDamagePerBlock,
+		WarningBlocks:  int32(info.BorderWarningBlocks),
+		WarningTime:    int32(info.BorderWarningTime),
+	}
+	// the size of the metadata is the one at the start of the resizing
+	if info.BorderSizeLerpTime > 0 {
+		total := time.Duration(info.BorderSizeLerpTime) * time.Millisecond
+		elapsed := now.Sub(w.borderStart)
+		border.TargetDiameter = info.BorderSizeLerpTarget
+		if elapsed >= total {
+			border.Diameter = info.BorderSizeLerpTarget
+		} else {
+			progress := float64(elapsed) / float64(total)
+			border.Diameter = info.BorderSize + (info.BorderSizeLerpTarget-info.BorderSize)*progress
+			border.Remaining = total - elapsed
+		}
+	}
+	return border
+}
+
+// updateBorder stores the current state of the resizing in the metadata,
+// so that it may be changed or saved, without locking.
+func (w *World) updateBorder(now time.Time) {
+	if w.Info == nil {
+		return
+	}
+	border := w.borderAt(now)
+	w.Info.BorderSize = border.Diameter
+	w.Info.BorderSizeLerpTarget = border.TargetDiameter
+	w.Info.BorderSizeLerpTime = int64(border.Remaining / time.Millisecond)
+	w.borderStart = now
+}
+
+// SetBorderCenter moves the center of the border.
+func (w *World) SetBorderCenter(x, z float64) {
+	defer w.borderLock.Unlock()
+	w.borderLock.Lock()
+	if w.Info != nil {
+		w.Info.BorderCenterX, w.Info.BorderCenterZ = x, z
+	}
+}
+
+// ResizeBorder moves the diameter of the border to the given one during
+// the given time, or at once if it is 0. The diameter is kept between
+// MinBorderDiameter and DefaultBorderDiameter.
+func (w *World) ResizeBorder(diameter float64, duration time.Duration) {
+	defer w.borderLock.Unlock()
+	w.borderLock.Lock()
+	if w.Info == nil {
+		return
+	}
+	diameter = math.Max(MinBorderDiameter, math.Min(DefaultBorderDiameter, diameter))
+	now := time.Now()
+	w.updateBorder(now)
+	w.Info.BorderSizeLerpTarget = diameter
+	if duration <= 0 {
+		w.Info.BorderSize = diameter
+		w.Info.BorderSizeLerpTime = 0
+	} else {
+		w.Info.BorderSizeLerpTime = int64(duration / time.Millisecond)
+	}
+}
+
+// SetBorderDamage sets the damage per second and per block taken by the
+// players beyond the border, and the distance where they are not hurt.
+func (w *World) SetBorderDamage(damagePerBlock, safeZone float64) {
+	defer w.borderLock.Unlock()
+	w.borderLock.Lock()
+	if w.Info != nil {
+		w.Info.BorderDamagePerBlock, w.Info.BorderSafeZone = damagePerBlock, safeZone
+	}
+}
+
+// SetBorderWarning sets the distance and the time, in seconds, at
+// which the clients warn the players approaching the border.
+func (w *World) SetBorderWarning(blocks, seconds int32) {
+	defer w.borderLock.Unlock()
+	w.borderLock.Lock()
+	if w.Info != nil {
+		w.Info.BorderWarningBlocks, w.Info.BorderWarningTime = float64(blocks), float64(seconds)
+	}
+}
@@ -0,0 +1,98 @@
+package world
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestBorderDistance(t *testing.T) {
+	border := Border{CenterX: 100, CenterZ: -100, Diameter: 20, SafeZone: 5, DamagePerBlock: 0.2}
+	cases := []struct {
+		x, z     float64
+		distance float64
+		damage   float64
+	}{
+		{100, -100, 10, 0},
+		{108, -95, 2, 0},
+		{112, -100, -2, 0},   // in the safe zone
+		{100, -84, -6, 1},    // at least 1 beyond the safe zone
+		{130, -100, -20, 3},  // 15 blocks beyond the safe zone
+		{130, -130, -20, 3},  // in a corner, the closest side counts
+		{100, -200, -90, 17}, // 85 blocks beyond the safe zone
+	}
+	for _, c := range cases {
+		if distance := border.Distance(c.x, c.z); distance != c.distance {
+			t.Errorf("Distance at %v %v: expected %v, got %v", c.x, c.z, c.distance, distance)
+		}
+		if contains := border.Contains(c.x, c.z); contains != (c.distance >= 0) {
+			t.Errorf("Contains at %v %v: got %v", c.x, c.z, contains)
+		}
+		if damage := border.Damage(c.x, c.z); damage != c.damage {
+			t.Errorf("Damage at %v %v: expected %v, got %v", c.x, c.z, c.damage, damage)
+		}
+	}
+}
+
+func TestResizeBorder(t *testing.T) {
+	w := NewWorld("border")
+	w.Info = NewWorldInfo("border", 0)
+	if border := w.GetBorder(); border.Diameter != DefaultBorderDiameter || border.Remaining != 0 {
+		t.Errorf("Unexpected default border %+v", border)
+	}
+	w.ResizeBorder(1000, 0)
+	w.ResizeBorder(200, 10*time.Second)
+	start := w.borderStart
+	border := w.borderAt(start.Add(5 * time.Second))
+	if border.Diameter != 600 || border.TargetDiameter != 200 || border.Remaining != 5*time.Second {
+		t.Errorf("Unexpected border in the middle of the resizing %+v", border)
+	}
+	if border = w.borderAt(start.Add(time.Minute)); border.Diameter != 200 || border.Remaining != 0 {
+		t.Errorf("Unexpected border after the resizing %+v", border)
+	}
+
+	// the resizing is stored as it is at the time of the save
+	w.updateBorder(start.Add(2 * time.Second))
+	if info := w.Info; info.BorderSize != 840 || info.BorderSizeLerpTarget != 200 || info.BorderSizeLerpTime != 8000 {
+		t.Errorf("Unexpected stored border %v %v %v", info.BorderSize, info.BorderSizeLerpTarget, info.BorderSizeLerpTime)
+	}
+	if border = w.borderAt(w.borderStart.Add(4 * time.Second)); border.Diameter != 520 {
+		t.Error("The resizing should go on after being stored, got", border.Diameter)
+	}
+
+	w.ResizeBorder(0, 0)
+	if border = w.GetBorder(); border.Diameter != MinBorderDiameter {
+		t.Error("The diameter should not be lower than", MinBorderDiameter, "got", border.Diameter)
+	}
+}
+
+func TestBorderPersistence(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	w, err := OpenWorld(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Info = NewWorldInfo("border", 0)
+	w.SetBorderCenter(50, -50)
+	w.ResizeBorder(1000, time.Hour)
+	w.SetBorderDamage(1, 2)
+	w.SetBorderWarning(10, 30)
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := OpenWorld(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer read.Close()
+	border := read.GetBorder()
+	if border.CenterX != 50 || border.CenterZ != -50 || border.TargetDiameter != 1000 || border.Remaining <= 59*time.Minute {
+		t.Errorf("The resizing should have been saved, got %+v", border)
+	}
+	if border.DamagePerBlock != 1 || border.SafeZone != 2 || border.WarningBlocks != 10 || border.WarningTime != 30 {
+		t.Errorf("The damage and the warning should have been saved, got %+v", border)
+	}
+}
@@ -1,17 +1,148 @@
 package world
 
 import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/nbt"
 	"github.com/olsdavis/goelan/world/val"
+	"sync"
 )
 
+const (
+	// PlainsBiome is the default biome of the chunks.
+	PlainsBiome = 1
+)
+
+// Chunk struct represents a chunk column: 16 sections stacked
+// on top of each other. Its methods do not lock: once the chunk is
+// loaded, it must be locked to be modified, and read-locked to be read
+// by another routine (e.g. to be encoded or saved).
 type Chunk struct {
-	Blocks     [][][]*Block
-	StartPoint Location3i
+	X, Z     int32                          // the coordinates of the chunk (block coordinates / 16)
+	Sections [val.SectionsPerChunk]*Section // nil if the section is empty
+	Biomes   [val.ChunkSize * val.ChunkSize]byte
+	dirty    bool // true if the chunk has changed since it was loaded or saved
+	unloaded bool // true once the chunk has been removed from the loaded chunks
+	// the Level tag read from the region file: the tags which are not
+	// handled (entities, tile entities, ...) are kept when saving
+	level nbt.Compound
+	sync.RWMutex
+}
+
+// NewChunk creates an empty chunk at the given chunk coordinates.
+func NewChunk(x, z int32) *Chunk {
+	c := &Chunk{X: x, Z: z}
+	for i := range c.Biomes {
+		c.Biomes[i] = PlainsBiome
+	}
+	return c
+}
+
+// GetBlockState returns the state of the block at the given coordinates,
+// relative to the chunk.
+func (c *Chunk) GetBlockState(x, y, z int) BlockState {
+	if y < 0 || y >= val.ChunkHeight {
+		return Air
+	}
+	section := c.Sections[y/val.SectionHeight]
+	if section == nil {
+		return Air
+	}
+	return section.GetBlockState(x, y%val.SectionHeight, z)
+}
+
+// SetBlockState sets the state of the block at the given coordinates,
+// relative to the chunk.
+func (c *Chunk) SetBlockState(x, y, z int, state BlockState) {
+	if y < 0 || y >= val.ChunkHeight {
+		return
+	}
+	section := c.Sections[y/val.SectionHeight]
+	if section == nil {
+		if state == Air {
+			return
+		}
+		section = NewSection()
+		c.Sections[y/val.SectionHeight] = section
+	}
+	section.SetBlockState(x, y%val.SectionHeight, z, state)
+}
+
+// SetBlock sets the material of the block at the given coordinates,
+// relative to the chunk.
+func (c *Chunk) SetBlock(x, y, z int, mat material.Material, metadata byte) {
+	c.SetBlockState(x, y, z, NewBlockState(mat, metadata))
+}
+
+// GetBiome returns the biome at the given coordinates, relative to the chunk.
+func (c *Chunk) GetBiome(x, z int) byte {
+	return c.Biomes[z<<4|x]
 }
 
-func NewChunk(startPoint Location3i) *Chunk {
-	return &Chunk{
-		Blocks:     make([][][]*Block, val.ChunkSize),
-		StartPoint: startPoint,
+// SetBiome sets the biome at the given coordinates, relative to the chunk.
+func (c *Chunk) SetBiome(x, z int, biome byte) {
+	c.Biomes[z<<4|x] = biome
+}
+
+// GetSkyLight returns the sky light of the block at the given coordinates,
+// relative to the chunk. The blocks of the missing sections, and the ones
+// above the world, are lit by the sky.
+func (c *Chunk) GetSkyLight(x, y, z int) byte {
+	if y < 0 {
+		return 0
+	}
+	if y >= val.ChunkHeight {
+		return MaxLight
+	}
+	section := c.Sections[y/val.SectionHeight]
+	if section == nil {
+		return MaxLight
+	}
+	return section.SkyLight.Get(sectionIndex(x, y%val.SectionHeight, z))
+}
+
+// SetSkyLight sets the sky light of the block at the given coordinates,
+// relative to the chunk.
+func (c *Chunk) SetSkyLight(x, y, z int, level byte) {
+	if y < 0 || y >= val.ChunkHeight {
+		return
+	}
+	section := c.Sections[y/val.SectionHeight]
+	if section == nil {
+		if level == MaxLight {
+			return
+		}
+		section = NewSection()
+		c.Sections[y/val.SectionHeight] = section
+	}
+	section.SkyLight.Set(sectionIndex(x, y%val.SectionHeight, z), level)
+}
+
+// GetBlockLight returns the light emitted by the blocks at the given
+// coordinates, relative to the chunk.
+func (c *Chunk) GetBlockLight(x, y, z int) byte {
+	if y < 0 || y >= val.ChunkHeight {
+		return 0
+	}
+	section := c.Sections[y/val.SectionHeight]
+	if section == nil {
+		return 0
+	}
+	return section.BlockLight.Get(sectionIndex(x, y%val.SectionHeight, z))
+}
+
+// SetBlockLight sets the light emitted by the blocks at the given
+// coordinates, relative to the chunk.
+func (c *Chunk) SetBlockLight(x, y, z int, level byte) {
+	if y < 0 || y >= val.ChunkHeight {
+		return
+	}
+	section := c.Sections[y/val.SectionHeight]
+	if section == nil {
+		if level == 0 {
+			return
+		}
+		section = NewSection()
+		c.Sections[y/val.SectionHeight] = section
 	}
+	section.BlockLight.Set(sectionIndex(x, y%val.SectionHeight, z), level)
 }
@@ -0,0 +1,260 @@
+package world
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// ChunkFuture struct is the pending result of a chunk requested
+// to a ChunkPool.
+type ChunkFuture struct {
+	Pos      ChunkPos
+	chunk    *Chunk
+	done     chan struct{}
+	priority int
+	sequence uint64     // the order of the requests of the same priority
+	index    int        // the index in the queue; -1 once taken by a worker
+	pool     *ChunkPool // nil if the future has been completed by Request
+	refs     int        // the number of requests sharing the future, not cancelled
+}
+
+// newChunkFuture creates a future which has not been completed.
+func newChunkFuture(pos ChunkPos, priority int, sequence uint64) *ChunkFuture {
+	return &ChunkFuture{
+		Pos:      pos,
+		done:     make(chan struct{}),
+		priority: priority,
+		sequence: sequence,
+		index:    -1,
+	}
+}
+
+// complete sets the result of the future, and wakes up its waiters.
+func (f *ChunkFuture) complete(chunk *Chunk) {
+	f.chunk = chunk
+	close(f.done)
+}
+
+// Done returns a channel closed once the chunk is available.
+func (f *ChunkFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait waits for the chunk, and returns it. The chunk is nil if
+// it could neither be loaded nor generated, or if the pool has
+// been closed before.
+func (f *ChunkFuture) Wait() *Chunk {
+	<-f.done
+	return f.chunk
+}
+
+// Cancel cancels the request. Once all the requests sharing the future
+// are cancelled, it is removed from the queue of its pool and completed
+// with a nil chunk, unless a worker is already servicing it.
+func (f *ChunkFuture) Cancel() {
+	if f.pool == nil {
+		return
+	}
+	p := f.pool
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	if f.refs == 0 {
+		return
+	}
+	f.refs--
+	if f.refs > 0 || f.index < 0 {
+		return
+	}
+	heap.Remove(&p.queue, f.index)
+	delete(p.pending, f.Pos)
+	p.cancelled++
+	f.complete(nil)
+}
+
+// Chunk returns the chunk without waiting for it. The second
+// value is false if the chunk is not available yet.
+func (f *ChunkFuture) Chunk() (*Chunk, bool) {
+	select {
+	case <-f.done:
+		return f.chunk, true
+	default:
+		return nil, false
+	}
+}
+
+// chunkQueue is the priority queue of the pending requests
+// (see container/heap): the lowest priority first.
+type chunkQueue []*ChunkFuture
+
+func (q chunkQueue) Len() int {
+	return len(q)
+}
+
+func (q chunkQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].sequence < q[j].sequence
+}
+
+func (q chunkQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *chunkQueue) Push(x interface{}) {
+	f := x.(*ChunkFuture)
+	f.index = len(*q)
+	*q = append(*q, f)
+}
+
+func (q *chunkQueue) Pop() interface{} {
+	old := *q
+	f := old[len(old)-1]
+	old[len(old)-1] = nil
+	f.index = -1
+	*q = old[:len(old)-1]
+	return f
+}
+
+// ChunkPoolStats struct is a snapshot of the activity of a ChunkPool.
+type ChunkPoolStats struct {
+	Queued    int    // the requests waiting for a worker
+	Active    int    // the requests being loaded or generated
+	Completed uint64 // the requests completed since the creation of the pool
+	Cancelled uint64 // the requests cancelled before a worker serviced them
+}
+
+// ChunkPool struct loads and generates the chunks of a world in a fixed
+// number of goroutines, so that the disk and the generator do not block
+// the routines which need chunks. The requests with the lowest priority
+// are serviced first, and the concurrent requests of the same chunk
+// share the same future.
+type ChunkPool struct {
+	world     *World
+	queue     chunkQueue
+	pending   map[ChunkPos]*ChunkFuture // the queued and active requests
+	sequence  uint64
+	active    int
+	completed uint64
+	cancelled uint64
+	closed    bool
+	lock      sync.Mutex
+	cond      *sync.Cond // signaled when a request is queued, or the pool closed
+	workers   sync.WaitGroup
+}
+
+// NewChunkPool creates a pool of the given number of workers (at least one)
+// servicing the given world, and starts them.
+func NewChunkPool(w *World, workers int) *ChunkPool {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &ChunkPool{
+		world:   w,
+		queue:   make(chunkQueue, 0),
+		pending: make(map[ChunkPos]*ChunkFuture),
+	}
+	p.cond = sync.NewCond(&p.lock)
+	p.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Request requests the chunk at the given chunk coordinates, with the
+// given priority (the lowest first, typically the distance to the
+// nearest player). If the chunk has already been requested, its request
+// is shared, and moved forward if the new priority is lower. The requests
+// which are not needed anymore should be cancelled.
+func (p *ChunkPool) Request(x, z int32, priority int) *ChunkFuture {
+	pos := ChunkPos{x, z}
+	if chunk := p.world.GetLoadedChunk(x, z); chunk != nil {
+		f := newChunkFuture(pos, priority, 0)
+		f.complete(chunk)
+		return f
+	}
+
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	if f, ok := p.pending[pos]; ok {
+		if priority < f.priority && f.index >= 0 {
+			f.priority = priority
+			heap.Fix(&p.queue, f.index)
+		}
+		f.refs++
+		return f
+	}
+	p.sequence++
+	f := newChunkFuture(pos, priority, p.sequence)
+	if p.closed {
+		f.complete(nil)
+		return f
+	}
+	f.pool = p
+	f.refs = 1
+	p.pending[pos] = f
+	heap.Push(&p.queue, f)
+	p.cond.Signal()
+	return f
+}
+
+// Stats returns the current activity of the pool.
+func (p *ChunkPool) Stats() ChunkPoolStats {
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	return ChunkPoolStats{
+		Queued:    len(p.queue),
+		Active:    p.active,
+		Completed: p.completed,
+		Cancelled: p.cancelled,
+	}
+}
+
+// Close stops the workers once they have finished their current
+// request, and completes the queued ones with a nil chunk.
+func (p *ChunkPool) Close() {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return
+	}
+	p.closed = true
+	for len(p.queue) > 0 {
+		f := heap.Pop(&p.queue).(*ChunkFuture)
+		delete(p.pending, f.Pos)
+		f.complete(nil)
+	}
+	p.cond.Broadcast()
+	p.lock.Unlock()
+	p.workers.Wait()
+}
+
+// work services the requests until the pool is closed.
+func (p *ChunkPool) work() {
+	defer p.workers.Done()
+	for {
+		p.lock.Lock()
+		for len(p.queue) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if p.closed {
+			p.lock.Unlock()
+			return
+		}
+		f := heap.Pop(&p.queue).(*ChunkFuture)
+		p.active++
+		p.lock.Unlock()
+
+		chunk := p.world.GetChunk(f.Pos.X, f.Pos.Z)
+
+		p.lock.Lock()
+		delete(p.pending, f.Pos)
+		p.active--
+		p.completed++
+		p.lock.Unlock()
+		f.complete(chunk)
+	}
+}
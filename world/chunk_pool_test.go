@@ -0,0 +1,166 @@
+package world
+
+import (
+	"sync"
+	"testing"
+)
+
+// testGenerator generates empty chunks, records the order of the
+// generation, and waits for its gate to be opened if it has one.
+type testGenerator struct {
+	gate  chan struct{}
+	order []ChunkPos
+	lock  sync.Mutex
+}
+
+func (g *testGenerator) GenerateChunkColumn(x, z int, w *World) *Chunk {
+	if g.gate != nil {
+		<-g.gate
+	}
+	g.lock.Lock()
+	g.order = append(g.order, ChunkPos{int32(x), int32(z)})
+	g.lock.Unlock()
+	return NewChunk(int32(x), int32(z))
+}
+
+func TestChunkPoolDeduplication(t *testing.T) {
+	generator := &testGenerator{gate: make(chan struct{})}
+	w := NewWorld("test")
+	w.Generator = generator
+	pool := NewChunkPool(w, 4)
+	defer pool.Close()
+
+	futures := make([]*ChunkFuture, 10)
+	var wg sync.WaitGroup
+	for i := range futures {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			futures[i] = pool.Request(3, -7, i)
+		}(i)
+	}
+	wg.Wait()
+	if _, ok := futures[0].Chunk(); ok {
+		t.Fatal("The chunk should not be available before its generation")
+	}
+	close(generator.gate)
+
+	chunk := futures[0].Wait()
+	if chunk == nil || chunk.X != 3 || chunk.Z != -7 {
+		t.Fatal("Unexpected chunk", chunk)
+	}
+	for _, f := range futures {
+		if f.Wait() != chunk {
+			t.Error("The concurrent requests should get the same chunk")
+		}
+	}
+	if len(generator.order) != 1 {
+		t.Errorf("The chunk should have been generated once, not %v times", len(generator.order))
+	}
+	if w.GetLoadedChunk(3, -7) != chunk {
+		t.Error("The chunk should have been loaded in the world")
+	}
+	if f := pool.Request(3, -7, 0); f.Wait() != chunk {
+		t.Error("A loaded chunk should be returned right away")
+	}
+	if stats := pool.Stats(); stats.Queued != 0 || stats.Active != 0 || stats.Completed != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestChunkPoolPriority(t *testing.T) {
+	generator := &testGenerator{gate: make(chan struct{})}
+	w := NewWorld("test")
+	w.Generator = generator
+	pool := NewChunkPool(w, 1)
+	defer pool.Close()
+
+	// keeps the worker busy while the other requests are queued
+	first := pool.Request(0, 0, 0)
+	for pool.Stats().Active != 1 {
+	}
+	requests := map[ChunkPos]int{{X: 1}: 5, {X: 2}: 1, {X: 3}: 3, {X: 4}: 9}
+	futures := make([]*ChunkFuture, 0)
+	for pos, priority := range requests {
+		futures = append(futures, pool.Request(pos.X, pos.Z, priority))
+	}
+	// moves the last one forward
+	pool.Request(4, 0, 2)
+	if stats := pool.Stats(); stats.Queued != 4 || stats.Active != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	close(generator.gate)
+	first.Wait()
+	for _, f := range futures {
+		f.Wait()
+	}
+
+	expected := []ChunkPos{{X: 0}, {X: 2}, {X: 4}, {X: 3}, {X: 1}}
+	for i, pos := range expected {
+		if generator.order[i] != pos {
+			t.Fatalf("Expected the order %v, got %v", expected, generator.order)
+		}
+	}
+}
+
+func TestChunkPoolClose(t *testing.T) {
+	generator := &testGenerator{gate: make(chan struct{})}
+	w := NewWorld("test")
+	w.Generator = generator
+	pool := NewChunkPool(w, 1)
+
+	first := pool.Request(0, 0, 0)
+	for pool.Stats().Active != 1 {
+	}
+	queued := pool.Request(1, 0, 0)
+	closed := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(closed)
+	}()
+	if queued.Wait() != nil {
+		t.Error("The queued request should have been cancelled")
+	}
+	close(generator.gate)
+	<-closed
+	if first.Wait() == nil {
+		t.Error("The active request should have been completed")
+	}
+	if pool.Request(2, 0, 0).Wait() != nil {
+		t.Error("A closed pool should not service any request")
+	}
+}
+
+func TestChunkPoolCancel(t *testing.T) {
+	generator := &testGenerator{gate: make(chan struct{})}
+	w := NewWorld("test")
+	w.Generator = generator
+	pool := NewChunkPool(w, 1)
+	defer pool.Close()
+
+	first := pool.Request(0, 0, 0)
+	for pool.Stats().Active != 1 {
+	}
+	queued := pool.Request(1, 0, 0)
+	pool.Request(1, 0, 0)
+	queued.Cancel()
+	if _, ok := queued.Chunk(); ok || pool.Stats().Queued != 1 {
+		t.Fatal("A request shared with another one should not be cancelled")
+	}
+	queued.Cancel()
+	if queued.Wait() != nil {
+		t.Error("A cancelled request should be completed with a nil chunk")
+	}
+	// the active requests are not cancelled
+	first.Cancel()
+	close(generator.gate)
+	if first.Wait() == nil {
+		t.Error("The active request should have been completed")
+	}
+	if stats := pool.Stats(); stats.Queued != 0 || stats.Cancelled != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if len(generator.order) != 1 {
+		t.Errorf("The cancelled chunk should not have been generated, got %v", generator.order)
+	}
+}
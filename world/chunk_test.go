@@ -0,0 +1,36 @@
+package world
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"testing"
+)
+
+func TestChunkBlocks(t *testing.T) {
+	chunk := NewChunk(0, 0)
+	chunk.SetBlock(3, 70, 5, material.Dirt, 0)
+	if state := chunk.GetBlockState(3, 70, 5); state.ID() != material.Dirt.ID || state.Metadata() != 0 {
+		t.Error("Block should be dirt, currently:", state)
+	}
+	if chunk.Sections[4] == nil || chunk.Sections[4].IsEmpty() {
+		t.Error("The section containing the block should have been created")
+	}
+	chunk.SetBlockState(3, 70, 5, Air)
+	if !chunk.Sections[4].IsEmpty() {
+		t.Error("The section should be empty once the block is removed")
+	}
+	if state := chunk.GetBlockState(0, 300, 0); state != Air {
+		t.Error("Blocks out of the chunk should be air, currently:", state)
+	}
+}
+
+func TestNibbleArray(t *testing.T) {
+	var array NibbleArray
+	array.Set(0, 3)
+	array.Set(1, 12)
+	if array[0] != 0xC3 {
+		t.Errorf("Nibbles should be stored as 0xC3, currently: %#x", array[0])
+	}
+	if array.Get(0) != 3 || array.Get(1) != 12 {
+		t.Error("Nibbles should be 3 and 12, currently:", array.Get(0), array.Get(1))
+	}
+}
@@ -0,0 +1,46 @@
+package world
+
+import "strings"
+
+// Dimension of a world, as sent to the clients: it defines the sky
+// and the fog they render.
+type Dimension int32
+
+const (
+	Nether    Dimension = -1
+	Overworld Dimension = 0
+	End       Dimension = 1
+)
+
+// dimensionNames contains the names of the dimensions, as written in the configuration.
+var dimensionNames = map[Dimension]string{
+	Nether:    "nether",
+	Overworld: "overworld",
+	End:       "the_end",
+}
+
+// ParseDimension returns the dimension of the given name ("overworld",
+// "nether" or "the_end"). Returns false if it does not exist.
+func ParseDimension(name string) (Dimension, bool) {
+	name = strings.TrimPrefix(strings.ToLower(name), "minecraft:")
+	for dimension, n := range dimensionNames {
+		// "the_nether" and "end" are also accepted
+		if n == name || n == strings.TrimPrefix(name, "the_") || n == "the_"+name {
+			return dimension, true
+		}
+	}
+	return Overworld, false
+}
+
+// HasSkyLight returns true if the blocks of the dimension are lit by the sky.
+func (d Dimension) HasSkyLight() bool {
+	return d == Overworld
+}
+
+// String returns the name of the dimension.
+func (d Dimension) String() string {
+	if name, ok := dimensionNames[d]; ok {
+		return name
+	}
+	return "unknown"
+}
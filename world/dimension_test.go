@@ -0,0 +1,23 @@
+package world
+
+import "testing"
+
+func TestParseDimension(t *testing.T) {
+	for name, expected := range map[string]Dimension{
+		"overworld":            Overworld,
+		"nether":               Nether,
+		"minecraft:the_nether": Nether,
+		"the_end":              End,
+		"End":                  End,
+	} {
+		if dimension, ok := ParseDimension(name); !ok || dimension != expected {
+			t.Errorf("Dimension %v: expected %v, got %v", name, expected, dimension)
+		}
+	}
+	if _, ok := ParseDimension("the_moon"); ok {
+		t.Error("the_moon is not a dimension")
+	}
+	if !Overworld.HasSkyLight() || Nether.HasSkyLight() || End.HasSkyLight() {
+		t.Error("Only the overworld is lit by the sky")
+	}
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/world"
+)
+
+// The IDs of the generated biomes, as sent to the clients.
+const (
+	OceanBiome        = 0
+	PlainsBiome       = world.PlainsBiome
+	DesertBiome       = 2
+	ExtremeHillsBiome = 3
+	ForestBiome       = 4
+	BeachBiome        = 16
+	ColdTaigaBiome    = 30
+)
+
+// the metadata of the spruce logs and leaves
+const spruceMetadata = 1
+
+// biome struct describes how the surface of a biome is generated.
+type biome struct {
+	id      byte
+	top     world.BlockState // the block of the surface
+	filler  world.BlockState // the blocks right below the surface
+	base    world.BlockState // the blocks below the filler, above the stone
+	trees   int              // the chances, out of 10, to plant a tree at each attempt
+	spruces bool             // true if the trees are spruces instead of oaks
+	snowy   bool             // true if the surface is covered with snow
+}
+
+var (
+	grass     = world.NewBlockState(material.Grass, 0)
+	dirt      = world.NewBlockState(material.Dirt, 0)
+	stone     = world.NewBlockState(material.Stone, 0)
+	sand      = world.NewBlockState(material.Sand, 0)
+	sandstone = world.NewBlockState(material.Sandstone, 0)
+	gravel    = world.NewBlockState(material.Gravel, 0)
+
+	oceanBiome        = &biome{id: OceanBiome, top: gravel, filler: gravel, base: stone}
+	plainsBiome       = &biome{id: PlainsBiome, top: grass, filler: dirt, base: stone, trees: 1}
+	desertBiome       = &biome{id: DesertBiome, top: sand, filler: sand, base: sandstone}
+	extremeHillsBiome = &biome{id: ExtremeHillsBiome, top: grass, filler: dirt, base: stone, trees: 2, spruces: true}
+	forestBiome       = &biome{id: ForestBiome, top: grass, filler: dirt, base: stone, trees: 8}
+	beachBiome        = &biome{id: BeachBiome, top: sand, filler: sand, base: sandstone}
+	coldTaigaBiome    = &biome{id: ColdTaigaBiome, top: grass, filler: dirt, base: stone, trees: 6, spruces: true, snowy: true}
+)
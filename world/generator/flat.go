@@ -1,30 +1,196 @@
 package generator
 
 import (
-	"github.com/olsdavis/goelan/world"
+	"fmt"
 	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/world"
 	"github.com/olsdavis/goelan/world/val"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FlatName is the name of the superflat generator.
+	FlatName = "flat"
+	// DefaultFlatPreset is the preset of vanilla's "Classic Flat" world.
+	DefaultFlatPreset = "3;minecraft:bedrock,2*minecraft:dirt,minecraft:grass;1;village"
+	// the version of the preset format, written by 1.12
+	flatPresetVersion = 3
 )
 
-type FlatGenerator struct{}
-
-func (generator FlatGenerator) GenerateChunkColumn(x, z int, w *world.World) *world.Chunk {
-	ret := &world.Chunk{}
-	for y := 0; y < 4; y++ {
-		var mat material.Material
-		switch y {
-		case 0:
-			mat = material.Bedrock
-		case 1:
-			fallthrough
-		case 2:
-			mat = material.Dirt
-		case 3:
-			mat = material.Grass
-		}
-		for x1 := 0; x1 < val.ChunkSize; x1++ {
-			for z1 := 0; z1 < val.ChunkSize; z1++ {
-				ret.Blocks[x1][y][z1] = world.NewBlock(world.NewLocation3i(int32(x+x1), int32(y), int32(z+z1), w), mat, 0)
+func init() {
+	Register(FlatName, func(seed int64, options string) (WorldGenerator, error) {
+		return NewFlatGenerator(options)
+	})
+}
+
+// FlatLayer struct is a layer of identical blocks of a flat world.
+type FlatLayer struct {
+	Block  world.BlockState
+	Height int
+}
+
+// FlatGenerator generates flat worlds: layers of blocks stacked from y = 0.
+// Its zero value generates the default preset.
+type FlatGenerator struct {
+	Layers     []FlatLayer                  // from the bottom to the top
+	Biome      byte                         // the biome of the whole world
+	Structures map[string]map[string]string // name => parameters (not generated yet)
+}
+
+// NewFlatGenerator creates a generator from the given preset,
+// or from the default one if it is empty.
+func NewFlatGenerator(preset string) (*FlatGenerator, error) {
+	if preset == "" {
+		preset = DefaultFlatPreset
+	}
+	return ParseFlatPreset(preset)
+}
+
+// ParseFlatPreset parses a preset of the superflat customization screen:
+// "version;layers;biome;structures", where the layers are separated by
+// commas and written "[count*]block[:metadata]" ("[count x]id[:metadata]"
+// in the version 2 of the format). Only the layers are required; the
+// blocks may be names (namespaced or not) or numeric IDs.
+func ParseFlatPreset(preset string) (*FlatGenerator, error) {
+	parts := strings.Split(preset, ";")
+	version := flatPresetVersion
+	if len(parts) > 1 {
+		v, err := strconv.Atoi(parts[0])
+		if err != nil || v < 0 || v > flatPresetVersion {
+			return nil, fmt.Errorf("invalid preset version %q", parts[0])
+		}
+		version = v
+		parts = parts[1:]
+	}
+
+	generator := &FlatGenerator{Biome: world.PlainsBiome, Structures: make(map[string]map[string]string)}
+	layers, err := parseFlatLayers(parts[0], version)
+	if err != nil {
+		return nil, err
+	}
+	generator.Layers = layers
+	if len(parts) > 1 && parts[1] != "" {
+		biome, err := strconv.ParseUint(parts[1], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid biome %q", parts[1])
+		}
+		generator.Biome = byte(biome)
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		if generator.Structures, err = parseFlatStructures(parts[2]); err != nil {
+			return nil, err
+		}
+	}
+	return generator, nil
+}
+
+// parseFlatLayers parses the layers of a preset.
+func parseFlatLayers(s string, version int) ([]FlatLayer, error) {
+	separator := "*"
+	if version < flatPresetVersion {
+		separator = "x"
+	}
+	layers := make([]FlatLayer, 0)
+	total := 0
+	for _, layer := range strings.Split(s, ",") {
+		layer = strings.TrimSpace(layer)
+		height := 1
+		if i := strings.Index(layer, separator); i >= 0 {
+			h, err := strconv.Atoi(layer[:i])
+			if err != nil || h <= 0 {
+				return nil, fmt.Errorf("invalid layer height in %q", layer)
+			}
+			height = h
+			layer = layer[i+1:]
+		}
+		block, err := parseBlock(layer)
+		if err != nil {
+			return nil, err
+		}
+		total += height
+		if total > val.ChunkHeight {
+			return nil, fmt.Errorf("the layers are higher than %v blocks", val.ChunkHeight)
+		}
+		layers = append(layers, FlatLayer{block, height})
+	}
+	return layers, nil
+}
+
+// parseBlock parses a block of a layer: "minecraft:stone", "stone:1",
+// "1:1" or "granite".
+func parseBlock(s string) (world.BlockState, error) {
+	var metadata uint64
+	// the metadata follows the last colon, if it is a number
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		if m, err := strconv.ParseUint(s[i+1:], 10, 4); err == nil {
+			metadata = m
+			s = s[:i]
+		}
+	}
+	if id, err := strconv.ParseUint(s, 10, 12); err == nil {
+		return world.BlockState(id<<4 | metadata), nil
+	}
+	// the variants ("granite") carry their own metadata
+	mat, variant, ok := material.GetVariantByName(s)
+	if !ok || !mat.IsBlock() {
+		return world.Air, fmt.Errorf("unknown block %q", s)
+	}
+	if metadata == 0 {
+		metadata = uint64(variant)
+	}
+	return world.NewBlockState(mat, byte(metadata)), nil
+}
+
+// parseFlatStructures parses the structures of a preset:
+// "village(size=1 distance=32),mineshaft".
+func parseFlatStructures(s string) (map[string]map[string]string, error) {
+	structures := make(map[string]map[string]string)
+	for _, structure := range strings.Split(s, ",") {
+		parameters := make(map[string]string)
+		name := structure
+		if i := strings.Index(structure, "("); i >= 0 {
+			if !strings.HasSuffix(structure, ")") {
+				return nil, fmt.Errorf("invalid structure %q", structure)
+			}
+			name = structure[:i]
+			for _, parameter := range strings.Fields(structure[i+1 : len(structure)-1]) {
+				kv := strings.SplitN(parameter, "=", 2)
+				if len(kv) != 2 {
+					return nil, fmt.Errorf("invalid parameter %q of structure %v", parameter, name)
+				}
+				parameters[kv[0]] = kv[1]
+			}
+		}
+		if name != "" {
+			structures[name] = parameters
+		}
+	}
+	return structures, nil
+}
+
+// GenerateChunkColumn stacks the layers.
+func (generator *FlatGenerator) GenerateChunkColumn(x, z int, w *world.World) *world.Chunk {
+	layers, biome := generator.Layers, generator.Biome
+	if layers == nil {
+		def, _ := ParseFlatPreset(DefaultFlatPreset)
+		layers, biome = def.Layers, def.Biome
+	}
+
+	ret := world.NewChunk(int32(x), int32(z))
+	for i := range ret.Biomes {
+		ret.Biomes[i] = biome
+	}
+	y := 0
+	for _, layer := range layers {
+		for top := y + layer.Height; y < top; y++ {
+			if layer.Block == world.Air {
+				continue
+			}
+			for x1 := 0; x1 < val.ChunkSize; x1++ {
+				for z1 := 0; z1 < val.ChunkSize; z1++ {
+					ret.SetBlockState(x1, y, z1, layer.Block)
+				}
 			}
 		}
 	}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/world"
+	"testing"
+)
+
+func TestParseFlatPreset(t *testing.T) {
+	generator, err := ParseFlatPreset("3;minecraft:bedrock,2*minecraft:dirt,minecraft:grass;1;village(size=1 distance=32),mineshaft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []FlatLayer{
+		{world.NewBlockState(material.Bedrock, 0), 1},
+		{world.NewBlockState(material.Dirt, 0), 2},
+		{world.NewBlockState(material.Grass, 0), 1},
+	}
+	if len(generator.Layers) != len(expected) {
+		t.Fatalf("Expected %v layers, got %v", len(expected), generator.Layers)
+	}
+	for i, layer := range expected {
+		if generator.Layers[i] != layer {
+			t.Errorf("Layer %v: expected %v, got %v", i, layer, generator.Layers[i])
+		}
+	}
+	if generator.Biome != 1 {
+		t.Error("Biome should be 1, got", generator.Biome)
+	}
+	if village, ok := generator.Structures["village"]; !ok || village["distance"] != "32" {
+		t.Error("Unexpected village parameters", village)
+	}
+	if _, ok := generator.Structures["mineshaft"]; !ok {
+		t.Error("The mineshafts should have been parsed")
+	}
+}
+
+func TestParseFlatPresetFormats(t *testing.T) {
+	presets := map[string][]FlatLayer{
+		// version 2, with numeric IDs
+		"2;7,2x3,2;1;village": {{world.BlockState(7 << 4), 1}, {world.BlockState(3 << 4), 2}, {world.BlockState(2 << 4), 1}},
+		// the layers only, with metadata
+		"bedrock,3*stone:1,minecraft:air": {{world.BlockState(7 << 4), 1}, {world.BlockState(1<<4 | 1), 3}, {world.Air, 1}},
+		"3;35:14;2":                       {{world.BlockState(35<<4 | 14), 1}},
+		"minecraft:granite,red_wool":      {{world.BlockState(1<<4 | 1), 1}, {world.BlockState(35<<4 | 14), 1}},
+	}
+	for preset, expected := range presets {
+		generator, err := ParseFlatPreset(preset)
+		if err != nil {
+			t.Errorf("%v: %v", preset, err)
+			continue
+		}
+		if len(generator.Layers) != len(expected) {
+			t.Errorf("%v: expected %v, got %v", preset, expected, generator.Layers)
+			continue
+		}
+		for i := range expected {
+			if generator.Layers[i] != expected[i] {
+				t.Errorf("%v: expected %v, got %v", preset, expected, generator.Layers)
+			}
+		}
+	}
+
+	for _, preset := range []string{"4;minecraft:stone", "3;minecraft:unknown", "3;0*minecraft:stone", "3;300*minecraft:stone", "3;stone;biome", "3;stone;1;village(size", "3;minecraft:diamond"} {
+		if _, err := ParseFlatPreset(preset); err == nil {
+			t.Errorf("%v should be invalid", preset)
+		}
+	}
+}
+
+func TestFlatGenerator(t *testing.T) {
+	generator, err := New("FLAT", 0, "3;minecraft:bedrock,60*minecraft:stone,minecraft:dirt;4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := world.NewWorld("flat")
+	w.Generator = generator
+	chunk := w.GetChunk(-2, 5)
+	if chunk == nil {
+		t.Fatal("The chunk should have been generated")
+	}
+	if chunk.X != -2 || chunk.Z != 5 {
+		t.Error("Unexpected coordinates", chunk.X, chunk.Z)
+	}
+	if w.GetChunk(-2, 5) != chunk {
+		t.Error("The generated chunk should have been kept")
+	}
+	if h := chunk.HighestBlock(7, 7); h != 61 {
+		t.Error("The highest block should be at 61, got", h)
+	}
+	if state := chunk.GetBlockState(3, 30, 9); state.ID() != material.Stone.ID {
+		t.Error("Block should be stone, got", state)
+	}
+	if biome := chunk.GetBiome(0, 15); biome != 4 {
+		t.Error("Biome should be 4, got", biome)
+	}
+
+	if _, err := New("unknown", 0, ""); err == nil {
+		t.Error("Creating an unknown generator should fail")
+	}
+	if chunk := (&FlatGenerator{}).GenerateChunkColumn(0, 0, w); chunk.HighestBlock(0, 0) != 3 {
+		t.Error("The zero generator should generate the default preset")
+	}
+}
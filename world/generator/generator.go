@@ -1,7 +1,66 @@
 package generator
 
-import "github.com/olsdavis/goelan/world"
+import (
+	"fmt"
+	"github.com/olsdavis/goelan/world"
+	"sort"
+	"strings"
+	"sync"
+)
 
+// WorldGenerator generates the chunks of a world. It satisfies
+// world.ChunkGenerator, and must be safe for concurrent use.
 type WorldGenerator interface {
 	GenerateChunkColumn(x, z int, world *world.World) *world.Chunk
 }
+
+// Factory creates a generator from the seed and the options
+// (generatorOptions in level.dat) of a world.
+type Factory func(seed int64, options string) (WorldGenerator, error)
+
+var (
+	factories   = make(map[string]Factory)
+	factoryLock sync.RWMutex
+)
+
+// Register registers the generator of the given name, as written in the
+// generatorName tag of level.dat. Panics if the name is already taken.
+func Register(name string, factory Factory) {
+	defer factoryLock.Unlock()
+	factoryLock.Lock()
+	if _, ok := factories[name]; ok {
+		panic(fmt.Sprintf("generator %v is already registered", name))
+	}
+	factories[name] = factory
+}
+
+// New creates the generator of the given name,
+// which case is ignored if no name matches exactly.
+func New(name string, seed int64, options string) (WorldGenerator, error) {
+	factoryLock.RLock()
+	factory, ok := factories[name]
+	if !ok {
+		for registered, f := range factories {
+			if strings.EqualFold(registered, name) {
+				factory, ok = f, true
+			}
+		}
+	}
+	factoryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown generator %q (available: %v)", name, Names())
+	}
+	return factory(seed, options)
+}
+
+// Names returns the names of the registered generators, sorted.
+func Names() []string {
+	factoryLock.RLock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	factoryLock.RUnlock()
+	sort.Strings(names)
+	return names
+}
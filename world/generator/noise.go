@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+)
+
+// perlin is the improved Perlin noise, with a permutation drawn from a seed.
+// It is read-only once created, and may thus be shared by goroutines.
+type perlin struct {
+	permutation [512]int
+	// the offsets of the origin, so that two noises with the same
+	// permutation differ at the integer coordinates
+	offsetX, offsetY, offsetZ float64
+}
+
+// newPerlin creates a noise from the given random source.
+func newPerlin(random *rand.Rand) *perlin {
+	p := &perlin{
+		offsetX: random.Float64() * 256,
+		offsetY: random.Float64() * 256,
+		offsetZ: random.Float64() * 256,
+	}
+	for i := 0; i < 256; i++ {
+		p.permutation[i] = i
+	}
+	for i := 0; i < 256; i++ {
+		j := i + random.Intn(256-i)
+		p.permutation[i], p.permutation[j] = p.permutation[j], p.permutation[i]
+		p.permutation[i+256] = p.permutation[i]
+	}
+	return p
+}
+
+// noise3 returns the noise at the given coordinates, in [-1; 1].
+func (p *perlin) noise3(x, y, z float64) float64 {
+	x, y, z = x+p.offsetX, y+p.offsetY, z+p.offsetZ
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	x, y, z = x-fx, y-fy, z-fz
+	i, j, k := int(fx)&0xFF, int(fy)&0xFF, int(fz)&0xFF
+	u, v, w := fade(x), fade(y), fade(z)
+
+	perm := &p.permutation
+	a := perm[i] + j
+	aa, ab := perm[a]+k, perm[a+1]+k
+	b := perm[i+1] + j
+	ba, bb := perm[b]+k, perm[b+1]+k
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad(perm[aa], x, y, z), grad(perm[ba], x-1, y, z)),
+			lerp(u, grad(perm[ab], x, y-1, z), grad(perm[bb], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, grad(perm[aa+1], x, y, z-1), grad(perm[ba+1], x-1, y, z-1)),
+			lerp(u, grad(perm[ab+1], x, y-1, z-1), grad(perm[bb+1], x-1, y-1, z-1))))
+}
+
+// noise2 returns the noise at the given horizontal coordinates, in [-1; 1].
+func (p *perlin) noise2(x, z float64) float64 {
+	return p.noise3(x, 0, z)
+}
+
+// fade is the smoothing curve of the improved noise: 6t^5 - 15t^4 + 10t^3.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// lerp interpolates linearly between a and b.
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// grad returns the dot product of the distance vector with
+// one of the 12 gradients, chosen by the hash.
+func grad(hash int, x, y, z float64) float64 {
+	h := hash & 15
+	u := y
+	if h < 8 {
+		u = x
+	}
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+// octaves struct sums several Perlin noises of decreasing
+// amplitude and increasing frequency (fractal noise).
+type octaves struct {
+	noises []*perlin
+}
+
+// newOctaves creates a fractal noise of the given number of octaves.
+func newOctaves(random *rand.Rand, count int) *octaves {
+	o := &octaves{noises: make([]*perlin, count)}
+	for i := range o.noises {
+		o.noises[i] = newPerlin(random)
+	}
+	return o
+}
+
+// noise2 returns the fractal noise at the given horizontal
+// coordinates, normalized to [-1; 1].
+func (o *octaves) noise2(x, z float64) float64 {
+	return o.noise3(x, 0, z)
+}
+
+// noise3 returns the fractal noise at the given coordinates, normalized to [-1; 1].
+func (o *octaves) noise3(x, y, z float64) float64 {
+	sum, amplitude, total := 0.0, 1.0, 0.0
+	for _, noise := range o.noises {
+		sum += noise.noise3(x, y, z) * amplitude
+		total += amplitude
+		x, y, z = x*2, y*2, z*2
+		amplitude /= 2
+	}
+	return sum / total
+}
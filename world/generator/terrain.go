@@ -0,0 +1,289 @@
+package generator
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/world"
+	"github.com/olsdavis/goelan/world/val"
+	"math"
+	"math/rand"
+)
+
+const (
+	// DefaultName is the name of the generator of the default worlds.
+	DefaultName = "default"
+	// SeaLevel is the level of the water of the oceans.
+	SeaLevel = 62
+	// the height from which the hills are covered with snow
+	snowLine = 110
+	// the number of attempts to plant a tree in a chunk
+	treeAttempts = 10
+	// the squared radius of the caves, in noise units
+	caveRadius = 0.0025
+	// the lowest level of the caves
+	caveBottom = 5
+	// the thickness of the ground above the caves
+	caveRoof = 5
+)
+
+func init() {
+	Register(DefaultName, func(seed int64, options string) (WorldGenerator, error) {
+		return NewTerrainGenerator(seed), nil
+	})
+}
+
+// ore struct describes how the veins of an ore are distributed.
+type ore struct {
+	block world.BlockState
+	veins int // per chunk
+	size  int // the number of blocks of a vein
+	maxY  int // the level below which the veins are generated
+}
+
+var ores = []ore{
+	{world.NewBlockState(material.CoalOre, 0), 20, 12, 128},
+	{world.NewBlockState(material.IronOre, 0), 20, 8, 64},
+	{world.NewBlockState(material.GoldOre, 0), 2, 8, 32},
+	{world.NewBlockState(material.RedstoneOre, 0), 8, 7, 16},
+	{world.NewBlockState(material.DiamondOre, 0), 1, 7, 16},
+	{world.NewBlockState(material.LapisOre, 0), 1, 6, 32},
+}
+
+// TerrainGenerator generates natural terrain: hills and oceans shaped
+// by Perlin noise, biomes, caves, ores and trees. The generated chunks
+// only depend on the seed and their coordinates.
+type TerrainGenerator struct {
+	seed        int64
+	continents  *octaves // the oceans and the lands
+	hills       *octaves
+	roughness   *octaves // where the hills turn into mountains
+	detail      *octaves
+	temperature *octaves
+	humidity    *octaves
+	caves       [2]*octaves // the caves are where both are close to 0
+}
+
+// NewTerrainGenerator creates a generator from the given seed.
+func NewTerrainGenerator(seed int64) *TerrainGenerator {
+	random := rand.New(rand.NewSource(seed))
+	return &TerrainGenerator{
+		seed:        seed,
+		continents:  newOctaves(random, 4),
+		hills:       newOctaves(random, 4),
+		roughness:   newOctaves(random, 2),
+		detail:      newOctaves(random, 3),
+		temperature: newOctaves(random, 2),
+		humidity:    newOctaves(random, 2),
+		caves:       [2]*octaves{newOctaves(random, 2), newOctaves(random, 2)},
+	}
+}
+
+// GenerateChunkColumn generates the terrain, then carves the caves,
+// and finally populates the chunk with ores and trees.
+func (generator *TerrainGenerator) GenerateChunkColumn(x, z int, w *world.World) *world.Chunk {
+	chunk := world.NewChunk(int32(x), int32(z))
+	random := generator.chunkRandom(x, z)
+	var heights [val.ChunkSize * val.ChunkSize]int
+	var biomes [val.ChunkSize * val.ChunkSize]*biome
+
+	for x1 := 0; x1 < val.ChunkSize; x1++ {
+		for z1 := 0; z1 < val.ChunkSize; z1++ {
+			bx, bz := float64(x<<4+x1), float64(z<<4+z1)
+			height := generator.height(bx, bz)
+			b := generator.biome(bx, bz, height)
+			heights[z1<<4|x1], biomes[z1<<4|x1] = height, b
+			chunk.SetBiome(x1, z1, b.id)
+			generator.fillColumn(chunk, x1, z1, height, b, random)
+		}
+	}
+	generator.carveCaves(chunk, &heights)
+	generateOres(chunk, random)
+	plantTrees(chunk, &heights, &biomes, random)
+	return chunk
+}
+
+// chunkRandom returns the random source of the population of the
+// given chunk, which only depends on the seed and the coordinates.
+func (generator *TerrainGenerator) chunkRandom(x, z int) *rand.Rand {
+	return rand.New(rand.NewSource(generator.seed ^ int64(x)*341873128712 ^ int64(z)*132897987541))
+}
+
+// height returns the level of the surface at the given coordinates.
+func (generator *TerrainGenerator) height(x, z float64) int {
+	continent := generator.continents.noise2(x/512, z/512)
+	roughness := clamp((generator.roughness.noise2(x/256, z/256)+0.1)*2, 0, 1)
+	hills := generator.hills.noise2(x/128, z/128)
+	detail := generator.detail.noise2(x/24, z/24)
+	height := SeaLevel + 4 + continent*56 + hills*(10+72*roughness) + detail*3
+	return int(clamp(height, 1, val.ChunkHeight-16))
+}
+
+// biome returns the biome at the given coordinates, from the
+// climate and the height of the surface.
+func (generator *TerrainGenerator) biome(x, z float64, height int) *biome {
+	temperature := generator.temperature.noise2(x/384, z/384)
+	humidity := generator.humidity.noise2(x/384, z/384)
+	switch {
+	case height < SeaLevel-2:
+		return oceanBiome
+	case height <= SeaLevel+1:
+		return beachBiome
+	case height > 95:
+		return extremeHillsBiome
+	case temperature > 0.2 && humidity < 0:
+		return desertBiome
+	case temperature < -0.2:
+		return coldTaigaBiome
+	case humidity > 0.1:
+		return forestBiome
+	}
+	return plainsBiome
+}
+
+// fillColumn fills the given column with the blocks of the biome,
+// from the bedrock to the surface, and the water up to the sea level.
+func (generator *TerrainGenerator) fillColumn(chunk *world.Chunk, x, z, height int, b *biome, random *rand.Rand) {
+	bedrock := world.NewBlockState(material.Bedrock, 0)
+	water := world.NewBlockState(material.Water, 0)
+	depth := 3 + random.Intn(2)
+	for y := 0; y <= height; y++ {
+		state := stone
+		switch {
+		case y < 5 && y <= random.Intn(5):
+			state = bedrock
+		case y == height:
+			state = b.top
+		case y > height-depth:
+			state = b.filler
+		case y > height-depth-3:
+			state = b.base
+		}
+		chunk.SetBlockState(x, y, z, state)
+	}
+	for y := height + 1; y <= SeaLevel; y++ {
+		chunk.SetBlockState(x, y, z, water)
+	}
+	if height >= SeaLevel && (b.snowy || height >= snowLine) {
+		chunk.SetBlock(x, height+1, z, material.SnowLayer, 0)
+	}
+}
+
+// carveCaves carves the tunnels where both cave noises are close to 0,
+// without breaking through the surface.
+func (generator *TerrainGenerator) carveCaves(chunk *world.Chunk, heights *[val.ChunkSize * val.ChunkSize]int) {
+	for x1 := 0; x1 < val.ChunkSize; x1++ {
+		for z1 := 0; z1 < val.ChunkSize; z1++ {
+			bx, bz := float64(int(chunk.X)<<4+x1), float64(int(chunk.Z)<<4+z1)
+			for y := caveBottom; y < heights[z1<<4|x1]-caveRoof; y++ {
+				a := generator.caves[0].noise3(bx/48, float64(y)/24, bz/48)
+				b := generator.caves[1].noise3(bx/48, float64(y)/24, bz/48)
+				if a*a+b*b < caveRadius {
+					chunk.SetBlockState(x1, y, z1, world.Air)
+				}
+			}
+		}
+	}
+}
+
+// generateOres generates the veins of the ores, replacing the stone.
+// The veins do not cross the borders of the chunk.
+func generateOres(chunk *world.Chunk, random *rand.Rand) {
+	for _, o := range ores {
+		for i := 0; i < o.veins; i++ {
+			x, y, z := random.Intn(val.ChunkSize), random.Intn(o.maxY), random.Intn(val.ChunkSize)
+			for j := 0; j < o.size; j++ {
+				if x >= 0 && x < val.ChunkSize && z >= 0 && z < val.ChunkSize && chunk.GetBlockState(x, y, z) == stone {
+					chunk.SetBlockState(x, y, z, o.block)
+				}
+				x, y, z = x+random.Intn(3)-1, y+random.Intn(3)-1, z+random.Intn(3)-1
+			}
+		}
+	}
+}
+
+// plantTrees plants the trees of the biomes on the grass.
+// The trees do not cross the borders of the chunk.
+func plantTrees(chunk *world.Chunk, heights *[val.ChunkSize * val.ChunkSize]int, biomes *[val.ChunkSize * val.ChunkSize]*biome, random *rand.Rand) {
+	for i := 0; i < treeAttempts; i++ {
+		// keeps 2 blocks for the leaves
+		x, z := 2+random.Intn(val.ChunkSize-4), 2+random.Intn(val.ChunkSize-4)
+		b, y := biomes[z<<4|x], heights[z<<4|x]
+		if random.Intn(10) >= b.trees || chunk.GetBlockState(x, y, z) != grass {
+			continue
+		}
+		chunk.SetBlockState(x, y, z, dirt)
+		if b.spruces {
+			plantSpruce(chunk, x, y+1, z, random)
+		} else {
+			plantOak(chunk, x, y+1, z, random)
+		}
+	}
+}
+
+// plantOak plants an oak which trunk starts at the given coordinates.
+func plantOak(chunk *world.Chunk, x, y, z int, random *rand.Rand) {
+	leaves := world.NewBlockState(material.Leaves, 0)
+	top := y + 4 + random.Intn(3)
+	for y1 := top - 3; y1 <= top; y1++ {
+		radius := 2
+		if y1 >= top-1 {
+			radius = 1
+		}
+		for dx := -radius; dx <= radius; dx++ {
+			for dz := -radius; dz <= radius; dz++ {
+				// randomly rounds the corners
+				if abs(dx) == radius && abs(dz) == radius && (y1 == top || random.Intn(2) == 0) {
+					continue
+				}
+				setIfAir(chunk, x+dx, y1, z+dz, leaves)
+			}
+		}
+	}
+	for y1 := y; y1 < top; y1++ {
+		chunk.SetBlock(x, y1, z, material.Log, 0)
+	}
+}
+
+// plantSpruce plants a spruce which trunk starts at the given coordinates.
+func plantSpruce(chunk *world.Chunk, x, y, z int, random *rand.Rand) {
+	leaves := world.NewBlockState(material.Leaves, spruceMetadata)
+	top := y + 6 + random.Intn(3)
+	setIfAir(chunk, x, top, z, leaves)
+	// the layers of leaves alternate between a wide and a narrow one
+	for y1 := top - 1; y1 >= y+2; y1-- {
+		radius := 1
+		if (top-y1)%2 == 0 && top-y1 > 2 {
+			radius = 2
+		}
+		for dx := -radius; dx <= radius; dx++ {
+			for dz := -radius; dz <= radius; dz++ {
+				if radius > 1 && abs(dx) == radius && abs(dz) == radius {
+					continue
+				}
+				setIfAir(chunk, x+dx, y1, z+dz, leaves)
+			}
+		}
+	}
+	for y1 := y; y1 < top; y1++ {
+		chunk.SetBlock(x, y1, z, material.Log, spruceMetadata)
+	}
+}
+
+// setIfAir sets the given block if it is not occupied, or only by snow.
+func setIfAir(chunk *world.Chunk, x, y, z int, state world.BlockState) {
+	if current := chunk.GetBlockState(x, y, z); current == world.Air || current.ID() == material.SnowLayer.ID {
+		chunk.SetBlockState(x, y, z, state)
+	}
+}
+
+// clamp returns the value constrained to [min; max].
+func clamp(value, min, max float64) float64 {
+	return math.Max(min, math.Min(max, value))
+}
+
+// abs returns the absolute value of the given integer.
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/world"
+	"github.com/olsdavis/goelan/world/val"
+	"testing"
+)
+
+// sameChunks returns true if both chunks have the same blocks and biomes.
+func sameChunks(a, b *world.Chunk) bool {
+	if a.Biomes != b.Biomes {
+		return false
+	}
+	for x := 0; x < val.ChunkSize; x++ {
+		for z := 0; z < val.ChunkSize; z++ {
+			for y := 0; y < val.ChunkHeight; y++ {
+				if a.GetBlockState(x, y, z) != b.GetBlockState(x, y, z) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func TestTerrainGeneratorDeterminism(t *testing.T) {
+	positions := []world.ChunkPos{{X: 0, Z: 0}, {X: -3, Z: 7}, {X: 120, Z: -45}}
+	first, second := NewTerrainGenerator(42), NewTerrainGenerator(42)
+	chunks := make([]*world.Chunk, len(positions))
+	for i, pos := range positions {
+		chunks[i] = first.GenerateChunkColumn(int(pos.X), int(pos.Z), nil)
+	}
+	// in the reverse order, with another generator
+	for i := len(positions) - 1; i >= 0; i-- {
+		pos := positions[i]
+		if chunk := second.GenerateChunkColumn(int(pos.X), int(pos.Z), nil); !sameChunks(chunks[i], chunk) {
+			t.Errorf("Chunk %v differs with the same seed", pos)
+		}
+		if chunk := first.GenerateChunkColumn(int(pos.X), int(pos.Z), nil); !sameChunks(chunks[i], chunk) {
+			t.Errorf("Chunk %v differs when generated twice", pos)
+		}
+	}
+	if sameChunks(chunks[0], NewTerrainGenerator(43).GenerateChunkColumn(0, 0, nil)) {
+		t.Error("Different seeds should generate different chunks")
+	}
+}
+
+func TestTerrainGenerator(t *testing.T) {
+	generator, err := New(DefaultName, 42, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	water := world.NewBlockState(material.Water, 0)
+	bedrock := world.NewBlockState(material.Bedrock, 0)
+	for x := -4; x < 4; x++ {
+		for z := -4; z < 4; z++ {
+			chunk := generator.GenerateChunkColumn(x, z, nil)
+			if chunk.X != int32(x) || chunk.Z != int32(z) {
+				t.Fatal("Unexpected coordinates", chunk.X, chunk.Z)
+			}
+			for x1 := 0; x1 < val.ChunkSize; x1++ {
+				for z1 := 0; z1 < val.ChunkSize; z1++ {
+					if state := chunk.GetBlockState(x1, 0, z1); state != bedrock {
+						t.Fatalf("The bottom of chunk %v %v should be bedrock, got %v", x, z, state)
+					}
+					top := chunk.HighestBlock(x1, z1)
+					if top < SeaLevel {
+						t.Fatalf("The oceans of chunk %v %v should be filled with water", x, z)
+					}
+					if top == SeaLevel && chunk.GetBlockState(x1, SeaLevel, z1) != water && chunk.GetBiome(x1, z1) == OceanBiome {
+						t.Fatalf("The surface of the oceans should be water")
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkTerrainGenerator(b *testing.B) {
+	generator := NewTerrainGenerator(42)
+	for i := 0; i < b.N; i++ {
+		generator.GenerateChunkColumn(i, i, nil)
+	}
+}
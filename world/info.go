@@ -0,0 +1,196 @@
+package world
+
+import (
+	"github.com/olsdavis/goelan/nbt"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+	"unicode/utf16"
+)
+
+// This file reads and writes level.dat, which contains the
+// metadata of a world.
+
+const (
+	levelFile    = "level.dat"
+	levelNewFile = "level.dat_new"
+	levelOldFile = "level.dat_old"
+	// the version of the Anvil format
+	anvilVersion = 19133
+)
+
+// Difficulty of a world.
+type Difficulty int8
+
+const (
+	Peaceful Difficulty = iota
+	Easy
+	Normal
+	Hard
+)
+
+// WorldInfo struct contains the metadata of a world, stored in the
+// "Data" compound of level.dat.
+type WorldInfo struct {
+	LevelName   string `nbt:"LevelName"`
+	Version     int32  `nbt:"version"`
+	DataVersion int32  `nbt:"DataVersion"`
+	Initialized bool   `nbt:"initialized"`
+	Seed        int64  `nbt:"RandomSeed"`
+	// the coordinates of the spawn point
+	SpawnX int32 `nbt:"SpawnX"`
+	SpawnY int32 `nbt:"SpawnY"`
+	SpawnZ int32 `nbt:"SpawnZ"`
+	// the age of the world, and the time of the day (in ticks)
+	Time             int64      `nbt:"Time"`
+	DayTime          int64      `nbt:"DayTime"`
+	LastPlayed       int64      `nbt:"LastPlayed"` // in milliseconds since the epoch
+	Difficulty       Difficulty `nbt:"Difficulty"`
+	DifficultyLocked bool       `nbt:"DifficultyLocked"`
+	GameType         int32      `nbt:"GameType"` // the default gamemode
+	Hardcore         bool       `nbt:"hardcore"`
+	AllowCommands    bool       `nbt:"allowCommands"`
+	MapFeatures      bool       `nbt:"MapFeatures"`
+	GeneratorName    string     `nbt:"generatorName"`
+	GeneratorVersion int32      `nbt:"generatorVersion"`
+	GeneratorOptions string     `nbt:"generatorOptions"`
+	// vanilla stores the values of the game rules as strings
+	GameRules map[string]string `nbt:"GameRules"`
+	// the weather, and the number of ticks before it changes (the
+	// clear weather set by a command lasts clearWeatherTime ticks)
+	ClearWeatherTime int32 `nbt:"clearWeatherTime"`
+	Raining          bool  `nbt:"raining"`
+	RainTime         int32 `nbt:"rainTime"`
+	Thundering       bool  `nbt:"thundering"`
+	ThunderTime      int32 `nbt:"thunderTime"`
+	// the world border (see border.go)
+	BorderCenterX        float64 `nbt:"BorderCenterX"`
+	BorderCenterZ        float64 `nbt:"BorderCenterZ"`
+	BorderSize           float64 `nbt:"BorderSize"`
+	BorderSizeLerpTarget float64 `nbt:"BorderSizeLerpTarget"`
+	BorderSizeLerpTime   int64   `nbt:"BorderSizeLerpTime"` // the remaining time of the resizing, in milliseconds
+	BorderSafeZone       float64 `nbt:"BorderSafeZone"`
+	BorderDamagePerBlock float64 `nbt:"BorderDamagePerBlock"`
+	BorderWarningBlocks  float64 `nbt:"BorderWarningBlocks"`
+	BorderWarningTime    float64 `nbt:"BorderWarningTime"`
+
+	// the tags read from level.dat which are not handled (kept when saving)
+	unknown nbt.Compound
+}
+
+// NewWorldInfo creates the metadata of a new world.
+func NewWorldInfo(name string, seed int64) *WorldInfo {
+	info := &WorldInfo{
+		LevelName:        name,
+		Version:          anvilVersion,
+		DataVersion:      DataVersion,
+		Seed:             seed,
+		SpawnY:           64,
+		Difficulty:       Easy,
+		MapFeatures:      true,
+		GeneratorName:    "default",
+		GeneratorVersion: 1,
+		GameRules:        make(map[string]string),
+	}
+	info.resetBorder()
+	return info
+}
+
+// ParseSeed returns the seed described by the given string: the number
+// itself, or the hash of the string as computed by vanilla. Returns a
+// random seed if the string is empty.
+func ParseSeed(s string) int64 {
+	if s == "" {
+		return rand.Int63()
+	}
+	if seed, err := strconv.ParseInt(s, 10, 64); err == nil && seed != 0 {
+		return seed
+	}
+	// Java's String.hashCode()
+	var hash int32
+	for _, c := range utf16.Encode([]rune(s)) {
+		hash = 31*hash + int32(c)
+	}
+	return int64(hash)
+}
+
+// LoadWorldInfo reads the level.dat file of the world stored in the given
+// directory. Returns nil and no error if the file does not exist.
+func LoadWorldInfo(directory string) (*WorldInfo, error) {
+	file, err := os.Open(levelPath(directory, levelFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	_, root, err := nbt.ReadCompressed(file)
+	if err != nil {
+		return nil, err
+	}
+
+	data := root.GetCompound("Data")
+	info := &WorldInfo{GameRules: make(map[string]string)}
+	// the files written before the border was handled have no border
+	info.resetBorder()
+	if err = nbt.UnmarshalTag(data, info); err != nil {
+		return nil, err
+	}
+	info.unknown = data
+	return info, nil
+}
+
+// Save writes the metadata in the level.dat file of the given directory.
+// As vanilla does, the file is written to level.dat_new, and the
+// previous one is kept as level.dat_old.
+func (info *WorldInfo) Save(directory string) error {
+	info.LastPlayed = time.Now().UnixNano() / int64(time.Millisecond)
+	data, err := nbt.MarshalCompound(info)
+	if err != nil {
+		return err
+	}
+	for name, tag := range info.unknown {
+		if _, ok := data[name]; !ok {
+			data[name] = tag
+		}
+	}
+
+	newPath := levelPath(directory, levelNewFile)
+	file, err := os.Create(newPath)
+	if err != nil {
+		return err
+	}
+	err = nbt.WriteGzip(file, "", nbt.Compound{"Data": data})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	path := levelPath(directory, levelFile)
+	oldPath := levelPath(directory, levelOldFile)
+	os.Remove(oldPath)
+	if err = os.Rename(path, oldPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Rename(newPath, path)
+}
+
+// GetGameRule returns the value of the given game rule,
+// or the given default value if it is not set.
+func (info *WorldInfo) GetGameRule(name, defaultValue string) string {
+	if value, ok := info.GameRules[name]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+// SetGameRule sets the value of the given game rule.
+func (info *WorldInfo) SetGameRule(name, value string) {
+	if info.GameRules == nil {
+		info.GameRules = make(map[string]string)
+	}
+	info.GameRules[name] = value
+}
@@ -0,0 +1,62 @@
+package world
+
+import (
+	"github.com/olsdavis/goelan/nbt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSeed(t *testing.T) {
+	cases := map[string]int64{
+		"12345":  12345,
+		"-42":    -42,
+		"goelan": -1240538116,
+		"a":      97,
+	}
+	for s, expected := range cases {
+		if seed := ParseSeed(s); seed != expected {
+			t.Errorf("Seed of %q: expected %v, got %v", s, expected, seed)
+		}
+	}
+}
+
+func TestWorldInfo(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	if info, err := LoadWorldInfo(dir); info != nil || err != nil {
+		t.Fatal("A world without level.dat should not have any info")
+	}
+	info := NewWorldInfo("test", 42)
+	info.SpawnX, info.SpawnY, info.SpawnZ = 10, 70, -20
+	info.Difficulty = Hard
+	info.SetGameRule("doDaylightCycle", "false")
+	info.unknown = nbt.Compound{"CustomTag": nbt.Double(1000), "RandomSeed": nbt.Long(1)}
+	if err := info.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := info.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, levelOldFile)); err != nil {
+		t.Error("The previous level.dat should have been kept:", err)
+	}
+
+	read, err := LoadWorldInfo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.LevelName != "test" || read.Seed != 42 || read.Difficulty != Hard {
+		t.Errorf("Unexpected info %+v", read)
+	}
+	if read.SpawnX != 10 || read.SpawnY != 70 || read.SpawnZ != -20 {
+		t.Error("Unexpected spawn", read.SpawnX, read.SpawnY, read.SpawnZ)
+	}
+	if read.GetGameRule("doDaylightCycle", "true") != "false" || read.GetGameRule("keepInventory", "false") != "false" {
+		t.Error("Unexpected game rules", read.GameRules)
+	}
+	if read.unknown.GetDouble("CustomTag") != 1000 {
+		t.Error("The unknown tags should have been kept")
+	}
+}
@@ -0,0 +1,378 @@
+package world
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/world/val"
+)
+
+// This file contains the light engine. The light is propagated from the
+// sky and from the luminous blocks with a breadth-first search, through
+// the loaded chunks; each block absorbs max(1, opacity) levels, except
+// the sky light which goes down through the transparent blocks without
+// being absorbed. When a block changes, the light which went through it
+// is removed first, then the light of the blocks around is propagated again.
+
+var (
+	// the opacity and the light emission of the blocks, by ID
+	blockOpacity  [1 << 12]byte
+	blockEmission [1 << 12]byte
+	// the offsets of the neighbours of a block: below, above, north, south, west and east
+	lightDirections = [6][3]int32{{0, -1, 0}, {0, 1, 0}, {0, 0, -1}, {0, 0, 1}, {-1, 0, 0}, {1, 0, 0}}
+)
+
+const (
+	// the indexes of lightDirections
+	belowDirection = 0
+	aboveDirection = 1
+)
+
+func init() {
+	for _, mat := range material.All() {
+		if mat.IsBlock() {
+			blockOpacity[mat.ID] = mat.Opacity
+			blockEmission[mat.ID] = mat.LightEmission
+		}
+	}
+}
+
+// lightNode is a block whose light is propagated, or removed.
+type lightNode struct {
+	x, y, z int32
+	level   byte // the previous level of the removed light
+}
+
+// lightEngine propagates one kind of light, the sky light or the block
+// light, through the loaded chunks of a world. It is not safe for
+// concurrent use: the world serializes its updates. The loaded chunks
+// it accesses stay locked until the end of the update, so that they are
+// not read (e.g. encoded) while their light changes.
+type lightEngine struct {
+	world    *World
+	sky      bool                // true for the sky light, false for the block light
+	increase []lightNode         // the blocks whose light is propagated
+	decrease []lightNode         // the blocks whose previous light is removed
+	lit      *Chunk              // the chunk being lit, which may not be loaded yet
+	cache    *Chunk              // the last chunk accessed
+	locked   map[ChunkPos]*Chunk // the loaded chunks accessed during the update
+}
+
+// newLightEngine creates an engine for the sky light if sky is true,
+// or for the block light otherwise.
+func newLightEngine(w *World, sky bool) *lightEngine {
+	return &lightEngine{
+		world:    w,
+		sky:      sky,
+		increase: make([]lightNode, 0),
+		decrease: make([]lightNode, 0),
+		locked:   make(map[ChunkPos]*Chunk),
+	}
+}
+
+// getChunk returns the chunk containing the block at the given
+// coordinates, or nil if it is not loaded. The loaded chunks are
+// locked until unlock is called.
+func (e *lightEngine) getChunk(x, z int32) *Chunk {
+	cx, cz := x>>4, z>>4
+	if e.cache != nil && e.cache.X == cx && e.cache.Z == cz {
+		return e.cache
+	}
+	if e.lit != nil && e.lit.X == cx && e.lit.Z == cz {
+		e.cache = e.lit
+		return e.lit
+	}
+	pos := ChunkPos{cx, cz}
+	chunk, ok := e.locked[pos]
+	if !ok {
+		if chunk = e.world.GetLoadedChunk(cx, cz); chunk == nil {
+			return nil
+		}
+		chunk.Lock()
+		e.locked[pos] = chunk
+	}
+	e.cache = chunk
+	return chunk
+}
+
+// unlock unlocks the chunks accessed during the update.
+func (e *lightEngine) unlock() {
+	for pos, chunk := range e.locked {
+		chunk.Unlock()
+		delete(e.locked, pos)
+	}
+	e.cache = nil
+}
+
+// get returns the light of the given block of the given chunk.
+func (e *lightEngine) get(chunk *Chunk, x, y, z int32) byte {
+	if e.sky {
+		return chunk.GetSkyLight(int(x&0x0F), int(y), int(z&0x0F))
+	}
+	return chunk.GetBlockLight(int(x&0x0F), int(y), int(z&0x0F))
+}
+
+// set sets the light of the given block of the given chunk.
+func (e *lightEngine) set(chunk *Chunk, x, y, z int32, level byte) {
+	chunk.dirty = true
+	if e.sky {
+		chunk.SetSkyLight(int(x&0x0F), int(y), int(z&0x0F), level)
+	} else {
+		chunk.SetBlockLight(int(x&0x0F), int(y), int(z&0x0F), level)
+	}
+}
+
+// spread returns the light received by a block of the given opacity from
+// a neighbour of the given level; down is true if the neighbour is above.
+func (e *lightEngine) spread(level, opacity byte, down bool) byte {
+	if e.sky && down && level == MaxLight && opacity == 0 {
+		return MaxLight
+	}
+	if opacity < 1 {
+		opacity = 1
+	}
+	if level <= opacity {
+		return 0
+	}
+	return level - opacity
+}
+
+// propagate propagates the light of the blocks of the increase queue.
+func (e *lightEngine) propagate() {
+	for i := 0; i < len(e.increase); i++ {
+		n := e.increase[i]
+		chunk := e.getChunk(n.x, n.z)
+		if chunk == nil {
+			continue
+		}
+		level := e.get(chunk, n.x, n.y, n.z)
+		if level <= 1 {
+			continue
+		}
+		for d, offset := range lightDirections {
+			x, y, z := n.x+offset[0], n.y+offset[1], n.z+offset[2]
+			if y < 0 || y >= val.ChunkHeight {
+				continue
+			}
+			neighbour := e.getChunk(x, z)
+			if neighbour == nil {
+				continue
+			}
+			state := neighbour.GetBlockState(int(x&0x0F), int(y), int(z&0x0F))
+			if l := e.spread(level, blockOpacity[state.ID()], d == belowDirection); l > e.get(neighbour, x, y, z) {
+				e.set(neighbour, x, y, z, l)
+				e.increase = append(e.increase, lightNode{x: x, y: y, z: z})
+			}
+		}
+	}
+	e.increase = e.increase[:0]
+}
+
+// unpropagate removes the light which came from the blocks of the
+// decrease queue, and adds the blocks lit by other sources around the
+// darkened area to the increase queue.
+func (e *lightEngine) unpropagate() {
+	for i := 0; i < len(e.decrease); i++ {
+		n := e.decrease[i]
+		for d, offset := range lightDirections {
+			x, y, z := n.x+offset[0], n.y+offset[1], n.z+offset[2]
+			if y < 0 || y >= val.ChunkHeight {
+				continue
+			}
+			neighbour := e.getChunk(x, z)
+			if neighbour == nil {
+				continue
+			}
+			current := e.get(neighbour, x, y, z)
+			if current == 0 {
+				continue
+			}
+			// the full sky light below a block only comes from this block
+			if current < n.level || (e.sky && d == belowDirection && n.level == MaxLight && current == MaxLight) {
+				e.set(neighbour, x, y, z, 0)
+				e.decrease = append(e.decrease, lightNode{x, y, z, current})
+				if !e.sky {
+					state := neighbour.GetBlockState(int(x&0x0F), int(y), int(z&0x0F))
+					if emission := blockEmission[state.ID()]; emission > 0 {
+						e.set(neighbour, x, y, z, emission)
+						e.increase = append(e.increase, lightNode{x: x, y: y, z: z})
+					}
+				}
+			} else {
+				e.increase = append(e.increase, lightNode{x: x, y: y, z: z})
+			}
+		}
+	}
+	e.decrease = e.decrease[:0]
+}
+
+// source returns the light of the given block of the given chunk,
+// from its emission and from the light of its neighbours.
+func (e *lightEngine) source(chunk *Chunk, x, y, z int32) byte {
+	state := chunk.GetBlockState(int(x&0x0F), int(y), int(z&0x0F))
+	opacity := blockOpacity[state.ID()]
+	var level byte
+	if !e.sky {
+		level = blockEmission[state.ID()]
+	}
+	for d, offset := range lightDirections {
+		// the blocks out of the world are lit as the world's borders
+		nx, ny, nz := x+offset[0], y+offset[1], z+offset[2]
+		neighbour := e.getChunk(nx, nz)
+		if neighbour == nil {
+			continue
+		}
+		if l := e.spread(e.get(neighbour, nx, ny, nz), opacity, d == aboveDirection); l > level {
+			level = l
+		}
+	}
+	return level
+}
+
+// update updates the light around the block at the given
+// coordinates, whose state has changed.
+func (e *lightEngine) update(x, y, z int32) {
+	e.cache = nil
+	defer e.unlock()
+	chunk := e.getChunk(x, z)
+	if chunk == nil || y < 0 || y >= val.ChunkHeight {
+		return
+	}
+	if old := e.get(chunk, x, y, z); old > 0 {
+		e.set(chunk, x, y, z, 0)
+		e.decrease = append(e.decrease, lightNode{x, y, z, old})
+		e.unpropagate()
+	}
+	if level := e.source(chunk, x, y, z); level > 0 {
+		e.set(chunk, x, y, z, level)
+		e.increase = append(e.increase, lightNode{x: x, y: y, z: z})
+	}
+	e.propagate()
+}
+
+// lightChunk computes the light of all the blocks of the given chunk,
+// and propagates it through the loaded chunks around, and the light of
+// these chunks through the given one.
+func (e *lightEngine) lightChunk(chunk *Chunk) {
+	e.lit = chunk
+	e.cache = chunk
+	// the lowest y from which the columns receive the full sky light
+	var heights [val.ChunkSize * val.ChunkSize]int32
+	if e.sky {
+		heights = e.lightSky(chunk)
+	} else {
+		e.lightBlocks(chunk)
+	}
+
+	// the light of the neighbours enters the chunk
+	baseX, baseZ := chunk.X<<4, chunk.Z<<4
+	for i := int32(0); i < val.ChunkSize; i++ {
+		e.seedBorder(chunk, baseX-1, baseZ+i, heights[i<<4])
+		e.seedBorder(chunk, baseX+val.ChunkSize, baseZ+i, heights[i<<4|(val.ChunkSize-1)])
+		e.seedBorder(chunk, baseX+i, baseZ-1, heights[i])
+		e.seedBorder(chunk, baseX+i, baseZ+val.ChunkSize, heights[(val.ChunkSize-1)<<4|i])
+	}
+	e.propagate()
+	e.lit = nil
+	e.unlock()
+}
+
+// lightSky lights the columns of the given chunk by the sky, and adds the
+// blocks which may light their neighbours to the increase queue. Returns
+// the lowest y from which each column receives the full sky light.
+func (e *lightEngine) lightSky(chunk *Chunk) [val.ChunkSize * val.ChunkSize]int32 {
+	var heights [val.ChunkSize * val.ChunkSize]int32
+	for z := 0; z < val.ChunkSize; z++ {
+		for x := 0; x < val.ChunkSize; x++ {
+			level := byte(MaxLight)
+			height := int32(val.ChunkHeight)
+			for y := val.ChunkHeight - 1; y >= 0; y-- {
+				// the missing sections are already lit by the sky
+				if level == MaxLight && chunk.Sections[y/val.SectionHeight] == nil {
+					y -= y % val.SectionHeight
+					height = int32(y)
+					continue
+				}
+				state := chunk.GetBlockState(x, y, z)
+				level = e.spread(level, blockOpacity[state.ID()], true)
+				chunk.SetSkyLight(x, y, z, level)
+				if level == MaxLight {
+					height = int32(y)
+				}
+			}
+			heights[z<<4|x] = height
+		}
+	}
+
+	// the blocks above all their neighbours' heights only light blocks
+	// already fully lit; the neighbours out of the chunk may be darker
+	baseX, baseZ := chunk.X<<4, chunk.Z<<4
+	for z := int32(0); z < val.ChunkSize; z++ {
+		for x := int32(0); x < val.ChunkSize; x++ {
+			top := heights[z<<4|x]
+			for _, offset := range lightDirections[2:] {
+				nx, nz := x+offset[0], z+offset[2]
+				var h int32
+				if nx >= 0 && nx < val.ChunkSize && nz >= 0 && nz < val.ChunkSize {
+					h = heights[nz<<4|nx]
+				} else if e.getChunk(baseX+nx, baseZ+nz) != nil {
+					h = val.ChunkHeight
+				}
+				if h > top {
+					top = h
+				}
+			}
+			for y := int32(0); y < top && y < val.ChunkHeight; y++ {
+				if chunk.GetSkyLight(int(x), int(y), int(z)) > 1 {
+					e.increase = append(e.increase, lightNode{x: baseX + x, y: y, z: baseZ + z})
+				}
+			}
+		}
+	}
+	return heights
+}
+
+// lightBlocks resets the block light of the given chunk, and adds
+// its luminous blocks to the increase queue.
+func (e *lightEngine) lightBlocks(chunk *Chunk) {
+	baseX, baseZ := chunk.X<<4, chunk.Z<<4
+	for i, section := range chunk.Sections {
+		if section == nil {
+			continue
+		}
+		section.BlockLight = NibbleArray{}
+		for index := 0; index < val.SectionVolume; index++ {
+			emission := blockEmission[section.GetBlockStateAt(index).ID()]
+			if emission == 0 {
+				continue
+			}
+			section.BlockLight.Set(index, emission)
+			e.increase = append(e.increase, lightNode{
+				x: baseX + int32(index&0x0F),
+				y: int32(i*val.SectionHeight + index>>8),
+				z: baseZ + int32(index>>4&0x0F),
+			})
+		}
+	}
+}
+
+// seedBorder adds the blocks of the given column of a neighbour of the
+// given chunk, which may light the chunk, to the increase queue. The
+// blocks of the chunk from the given height are fully lit by the sky.
+func (e *lightEngine) seedBorder(chunk *Chunk, x, z int32, height int32) {
+	neighbour := e.getChunk(x, z)
+	if neighbour == nil || neighbour == chunk {
+		return
+	}
+	top := int32(val.ChunkHeight)
+	if e.sky {
+		top = height
+	}
+	for y := int32(0); y < top; y++ {
+		if neighbour.Sections[y/val.SectionHeight] == nil && !e.sky {
+			y += val.SectionHeight - 1
+			continue
+		}
+		if e.get(neighbour, x, y, z) > 1 {
+			e.increase = append(e.increase, lightNode{x: x, y: y, z: z})
+		}
+	}
+}
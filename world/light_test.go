@@ -0,0 +1,208 @@
+package world
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/world/val"
+	"math/rand"
+	"testing"
+)
+
+// stoneChunk returns a chunk filled with stone up to the given height (excluded).
+func stoneChunk(x, z int32, height int) *Chunk {
+	chunk := NewChunk(x, z)
+	for y := 0; y < height; y++ {
+		for bz := 0; bz < val.ChunkSize; bz++ {
+			for bx := 0; bx < val.ChunkSize; bx++ {
+				chunk.SetBlock(bx, y, bz, material.Stone, 0)
+			}
+		}
+	}
+	return chunk
+}
+
+// litWorld returns a world containing the given chunks, lit in this order.
+func litWorld(chunks ...*Chunk) *World {
+	w := NewWorld("light")
+	for _, chunk := range chunks {
+		w.lightLock.Lock()
+		w.lightChunk(chunk)
+		w.lightLock.Unlock()
+		w.SetChunk(chunk)
+	}
+	return w
+}
+
+func TestLightChunk(t *testing.T) {
+	chunk := stoneChunk(0, 0, 64)
+	chunk.SetBlock(4, 64, 4, material.Glowstone, 0)
+	chunk.SetBlock(8, 63, 8, material.Air, 0)
+	litWorld(chunk)
+
+	if l := chunk.GetSkyLight(0, 64, 0); l != MaxLight {
+		t.Error("The surface should be lit by the sky, got", l)
+	}
+	if l := chunk.GetSkyLight(0, 63, 0); l != 0 {
+		t.Error("The stone should not be lit, got", l)
+	}
+	if l := chunk.GetSkyLight(8, 63, 8); l != MaxLight {
+		t.Error("The hole should be lit by the sky, got", l)
+	}
+	if l := chunk.GetBlockLight(4, 64, 4); l != 15 {
+		t.Error("The glowstone should emit 15, got", l)
+	}
+	if l := chunk.GetBlockLight(4, 64, 7); l != 12 {
+		t.Error("The block 3 blocks away from the glowstone should be lit at 12, got", l)
+	}
+	if l := chunk.GetBlockLight(4, 60, 4); l != 0 {
+		t.Error("The light should not go through the stone, got", l)
+	}
+}
+
+func TestLightUpdate(t *testing.T) {
+	chunk := stoneChunk(0, 0, 64)
+	chunk.SetBlock(8, 63, 8, material.Air, 0)
+	chunk.SetBlock(8, 62, 8, material.Air, 0)
+	w := litWorld(chunk)
+
+	// covering the pit darkens it
+	w.SetBlockState(8, 64, 8, NewBlockState(material.Stone, 0))
+	if l := chunk.GetSkyLight(8, 62, 8); l != 0 {
+		t.Error("The covered pit should be dark, got", l)
+	}
+	if l := chunk.GetSkyLight(8, 65, 8); l != MaxLight {
+		t.Error("The block above the roof should be lit, got", l)
+	}
+	// a torch lights it
+	w.SetBlockState(8, 62, 8, NewBlockState(material.Torch, 0))
+	if l := chunk.GetBlockLight(8, 63, 8); l != 13 {
+		t.Error("The block above the torch should be lit at 13, got", l)
+	}
+	// removing the torch darkens it again
+	w.SetBlockState(8, 62, 8, Air)
+	if l := chunk.GetBlockLight(8, 63, 8); l != 0 {
+		t.Error("The pit should be dark without the torch, got", l)
+	}
+	// uncovering it lights it
+	w.SetBlockState(8, 64, 8, Air)
+	if l := chunk.GetSkyLight(8, 62, 8); l != MaxLight {
+		t.Error("The uncovered pit should be lit by the sky, got", l)
+	}
+}
+
+func TestLightCrossesChunks(t *testing.T) {
+	for _, order := range [][2]int{{0, 1}, {1, 0}} {
+		chunks := [2]*Chunk{stoneChunk(0, 0, 64), stoneChunk(1, 0, 64)}
+		// a tunnel from a torch in the first chunk to the second one
+		for x := 12; x < 16; x++ {
+			chunks[0].SetBlock(x, 40, 8, material.Air, 0)
+		}
+		for x := 0; x < 4; x++ {
+			chunks[1].SetBlock(x, 40, 8, material.Air, 0)
+		}
+		chunks[0].SetBlock(12, 40, 8, material.Torch, 0)
+		w := litWorld(chunks[order[0]], chunks[order[1]])
+
+		if l := chunks[1].GetBlockLight(0, 40, 8); l != 10 {
+			t.Errorf("Order %v: the light of the torch should enter the second chunk at 10, got %v", order, l)
+		}
+		if l := chunks[1].GetSkyLight(0, 40, 8); l != 0 {
+			t.Errorf("Order %v: the tunnel should not be lit by the sky, got %v", order, l)
+		}
+		w.SetBlockState(12, 40, 8, Air)
+		if l := chunks[1].GetBlockLight(0, 40, 8); l != 0 {
+			t.Errorf("Order %v: the light of the removed torch should leave the second chunk, got %v", order, l)
+		}
+	}
+}
+
+// TestLightUpdateConsistency checks that the light updated after
+// random changes equals the light computed from scratch.
+func TestLightUpdateConsistency(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	states := []BlockState{
+		Air, Air, Air,
+		NewBlockState(material.Stone, 0),
+		NewBlockState(material.Glass, 0),
+		NewBlockState(material.Leaves, 0),
+		NewBlockState(material.Water, 0),
+		NewBlockState(material.Torch, 0),
+		NewBlockState(material.Glowstone, 0),
+	}
+	updated := litWorld(stoneChunk(0, 0, 60), stoneChunk(1, 0, 60))
+	for i := 0; i < 2000; i++ {
+		x, y, z := random.Int31n(32), 50+random.Int31n(20), random.Int31n(16)
+		updated.SetBlockState(x, y, z, states[random.Intn(len(states))])
+	}
+
+	copies := [2]*Chunk{NewChunk(0, 0), NewChunk(1, 0)}
+	for _, chunk := range copies {
+		original := updated.GetLoadedChunk(chunk.X, chunk.Z)
+		for y := 0; y < val.ChunkHeight; y++ {
+			for z := 0; z < val.ChunkSize; z++ {
+				for x := 0; x < val.ChunkSize; x++ {
+					chunk.SetBlockState(x, y, z, original.GetBlockState(x, y, z))
+				}
+			}
+		}
+	}
+	litWorld(copies[0], copies[1])
+
+	for _, chunk := range copies {
+		original := updated.GetLoadedChunk(chunk.X, chunk.Z)
+		for y := 0; y < val.ChunkHeight; y++ {
+			for z := 0; z < val.ChunkSize; z++ {
+				for x := 0; x < val.ChunkSize; x++ {
+					if a, b := original.GetSkyLight(x, y, z), chunk.GetSkyLight(x, y, z); a != b {
+						t.Fatalf("Sky light at %v %v %v of chunk %v: updated %v, computed %v", x, y, z, chunk.X, a, b)
+					}
+					if a, b := original.GetBlockLight(x, y, z), chunk.GetBlockLight(x, y, z); a != b {
+						t.Fatalf("Block light at %v %v %v of chunk %v: updated %v, computed %v", x, y, z, chunk.X, a, b)
+					}
+				}
+			}
+		}
+	}
+}
+
+// benchmarkChunk returns a chunk with hills, caves and torches.
+func benchmarkChunk(x, z int32) *Chunk {
+	random := rand.New(rand.NewSource(int64(x)<<32 | int64(z)))
+	chunk := NewChunk(x, z)
+	for bz := 0; bz < val.ChunkSize; bz++ {
+		for bx := 0; bx < val.ChunkSize; bx++ {
+			height := 60 + (bx*bz)%9
+			for y := 0; y < height; y++ {
+				if y > 10 && y < height-5 && random.Intn(4) == 0 {
+					continue
+				}
+				chunk.SetBlock(bx, y, bz, material.Stone, 0)
+			}
+			if random.Intn(20) == 0 {
+				chunk.SetBlock(bx, height, bz, material.Torch, 0)
+			}
+		}
+	}
+	return chunk
+}
+
+func BenchmarkLightChunk(b *testing.B) {
+	chunk := benchmarkChunk(0, 0)
+	w := litWorld(benchmarkChunk(-1, 0), benchmarkChunk(1, 0), benchmarkChunk(0, -1), benchmarkChunk(0, 1), chunk)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.lightLock.Lock()
+		w.lightChunk(chunk)
+		w.lightLock.Unlock()
+	}
+}
+
+func BenchmarkLightUpdate(b *testing.B) {
+	w := litWorld(benchmarkChunk(-1, 0), benchmarkChunk(1, 0), benchmarkChunk(0, -1), benchmarkChunk(0, 1), benchmarkChunk(0, 0))
+	stone := NewBlockState(material.Stone, 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// covers a column open to the sky, then uncovers it
+		w.SetBlockState(8, 100, 8, stone)
+		w.SetBlockState(8, 100, 8, Air)
+	}
+}
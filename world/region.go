@@ -0,0 +1,278 @@
+package world
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// This file reads and writes the Anvil region files (.mca), which
+// store 32x32 chunks each.
+
+const (
+	// RegionSize is the number of chunks on each side of a region.
+	RegionSize = 32
+	// the chunks are stored in sectors of 4KiB
+	sectorSize = 4096
+	// the first sectors are the header: the locations, then the timestamps
+	headerSectors = 2
+	// a chunk may use at most 255 sectors (the count is stored in one byte)
+	maxChunkSectors = 255
+	// the chunk length and the compression type precede the data
+	chunkHeaderLength = 5
+
+	GzipCompression = 1
+	ZlibCompression = 2
+	NoCompression   = 3
+)
+
+// RegionFile struct represents an opened region file.
+type RegionFile struct {
+	file       *os.File
+	locations  [RegionSize * RegionSize]uint32 // offset (in sectors) << 8 | count (in sectors)
+	timestamps [RegionSize * RegionSize]uint32 // the last modification of each chunk (in seconds)
+	used       []bool                          // the sectors used
+	lock       sync.Mutex
+}
+
+// RegionFileName returns the name of the file of the region
+// containing the given chunk.
+func RegionFileName(chunkX, chunkZ int32) string {
+	return fmt.Sprintf("r.%d.%d.mca", chunkX>>5, chunkZ>>5)
+}
+
+// OpenRegionFile opens the region file at the given path,
+// and creates it if it does not exist.
+func OpenRegionFile(path string) (*RegionFile, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	r := &RegionFile{file: file}
+	if err = r.readHeader(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return r, nil
+}
+
+// readHeader reads the locations and the timestamps of the chunks, or
+// writes an empty header if the file is new.
+func (r *RegionFile) readHeader() error {
+	info, err := r.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < headerSectors*sectorSize {
+		r.used = make([]bool, headerSectors)
+		r.used[0], r.used[1] = true, true
+		_, err = r.file.WriteAt(make([]byte, headerSectors*sectorSize), 0)
+		return err
+	}
+
+	header := make([]byte, headerSectors*sectorSize)
+	if _, err = r.file.ReadAt(header, 0); err != nil {
+		return err
+	}
+	sectors := int((info.Size() + sectorSize - 1) / sectorSize)
+	r.used = make([]bool, sectors)
+	r.used[0], r.used[1] = true, true
+	for i := range r.locations {
+		r.locations[i] = binary.BigEndian.Uint32(header[i*4:])
+		r.timestamps[i] = binary.BigEndian.Uint32(header[sectorSize+i*4:])
+		offset, count := int(r.locations[i]>>8), int(r.locations[i]&0xFF)
+		if r.locations[i] == 0 {
+			continue
+		}
+		if offset < headerSectors || offset+count > sectors {
+			// corrupted entry: the chunk is considered missing
+			r.locations[i] = 0
+			continue
+		}
+		for s := offset; s < offset+count; s++ {
+			r.used[s] = true
+		}
+	}
+	return nil
+}
+
+// regionIndex returns the index of the given chunk in the header.
+func regionIndex(chunkX, chunkZ int32) int {
+	return int(chunkX&(RegionSize-1)) + int(chunkZ&(RegionSize-1))*RegionSize
+}
+
+// HasChunk returns true if the given chunk is stored in the region.
+func (r *RegionFile) HasChunk(chunkX, chunkZ int32) bool {
+	defer r.lock.Unlock()
+	r.lock.Lock()
+	return r.locations[regionIndex(chunkX, chunkZ)] != 0
+}
+
+// Timestamp returns the last time the given chunk has been written.
+func (r *RegionFile) Timestamp(chunkX, chunkZ int32) time.Time {
+	defer r.lock.Unlock()
+	r.lock.Lock()
+	return time.Unix(int64(r.timestamps[regionIndex(chunkX, chunkZ)]), 0)
+}
+
+// ReadChunk returns the uncompressed data of the given chunk,
+// or nil if it is not stored in the region.
+func (r *RegionFile) ReadChunk(chunkX, chunkZ int32) ([]byte, error) {
+	r.lock.Lock()
+	location := r.locations[regionIndex(chunkX, chunkZ)]
+	if location == 0 {
+		r.lock.Unlock()
+		return nil, nil
+	}
+	data := make([]byte, int(location&0xFF)*sectorSize)
+	_, err := r.file.ReadAt(data, int64(location>>8)*sectorSize)
+	r.lock.Unlock()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	length := int(binary.BigEndian.Uint32(data))
+	if length < 1 || length+4 > len(data) {
+		return nil, fmt.Errorf("chunk %v, %v has an invalid length %v", chunkX, chunkZ, length)
+	}
+	compressed := bytes.NewReader(data[chunkHeaderLength : length+4])
+	var reader io.Reader
+	switch data[4] {
+	case GzipCompression:
+		gz, err := gzip.NewReader(compressed)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	case ZlibCompression:
+		z, err := zlib.NewReader(compressed)
+		if err != nil {
+			return nil, err
+		}
+		defer z.Close()
+		reader = z
+	case NoCompression:
+		reader = compressed
+	default:
+		return nil, fmt.Errorf("chunk %v, %v has an unknown compression type %v", chunkX, chunkZ, data[4])
+	}
+	return ioutil.ReadAll(reader)
+}
+
+// WriteChunk compresses the given data with zlib, and writes it as
+// the data of the given chunk.
+func (r *RegionFile) WriteChunk(chunkX, chunkZ int32, data []byte) error {
+	buf := bytes.NewBuffer(make([]byte, chunkHeaderLength, chunkHeaderLength+len(data)/2))
+	z := zlib.NewWriter(buf)
+	if _, err := z.Write(data); err != nil {
+		return err
+	}
+	if err := z.Close(); err != nil {
+		return err
+	}
+	payload := buf.Bytes()
+	binary.BigEndian.PutUint32(payload, uint32(len(payload)-4))
+	payload[4] = ZlibCompression
+
+	count := (len(payload) + sectorSize - 1) / sectorSize
+	if count > maxChunkSectors {
+		return fmt.Errorf("chunk %v, %v is too big (%v bytes)", chunkX, chunkZ, len(payload))
+	}
+	// pad to whole sectors
+	payload = append(payload, make([]byte, count*sectorSize-len(payload))...)
+
+	defer r.lock.Unlock()
+	r.lock.Lock()
+	index := regionIndex(chunkX, chunkZ)
+	previous, timestamp := r.locations[index], r.timestamps[index]
+	// the previous sectors are only freed once the new ones are written,
+	// so that the chunk is not lost if the write fails
+	sectors := len(r.used)
+	offset := r.allocate(count)
+	if _, err := r.file.WriteAt(payload, int64(offset)*sectorSize); err != nil {
+		r.free(offset, count, sectors)
+		return err
+	}
+	r.locations[index] = uint32(offset)<<8 | uint32(count)
+	r.timestamps[index] = uint32(time.Now().Unix())
+	if err := r.writeHeaderEntry(index); err != nil {
+		r.locations[index], r.timestamps[index] = previous, timestamp
+		// the entry may have been partially written
+		r.writeHeaderEntry(index)
+		r.free(offset, count, sectors)
+		return err
+	}
+	if previous != 0 {
+		r.free(int(previous>>8), int(previous&0xFF), len(r.used))
+	}
+	return nil
+}
+
+// allocate returns the offset of count free sectors, and marks them used.
+func (r *RegionFile) allocate(count int) int {
+	// first fit
+	run := 0
+	for s := headerSectors; s < len(r.used); s++ {
+		if r.used[s] {
+			run = 0
+			continue
+		}
+		run++
+		if run == count {
+			offset := s - count + 1
+			r.markUsed(offset, count)
+			return offset
+		}
+	}
+	// append at the end of the file, reusing the free sectors before it
+	offset := len(r.used) - run
+	for len(r.used) < offset+count {
+		r.used = append(r.used, false)
+	}
+	r.markUsed(offset, count)
+	return offset
+}
+
+// free marks the given sectors free, and forgets the ones beyond
+// the given number of sectors.
+func (r *RegionFile) free(offset, count, sectors int) {
+	for s := offset; s < offset+count; s++ {
+		r.used[s] = false
+	}
+	if len(r.used) > sectors {
+		r.used = r.used[:sectors]
+	}
+}
+
+func (r *RegionFile) markUsed(offset, count int) {
+	for s := offset; s < offset+count; s++ {
+		r.used[s] = true
+	}
+}
+
+// writeHeaderEntry writes the location and the timestamp at the given index.
+func (r *RegionFile) writeHeaderEntry(index int) error {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], r.locations[index])
+	if _, err := r.file.WriteAt(buf[:], int64(index*4)); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(buf[:], r.timestamps[index])
+	_, err := r.file.WriteAt(buf[:], int64(sectorSize+index*4))
+	return err
+}
+
+// Close closes the file.
+func (r *RegionFile) Close() error {
+	defer r.lock.Unlock()
+	r.lock.Lock()
+	return r.file.Close()
+}
@@ -0,0 +1,272 @@
+package world
+
+import (
+	"bytes"
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/nbt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "goelan-world")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRegionFileName(t *testing.T) {
+	if name := RegionFileName(31, 32); name != "r.0.1.mca" {
+		t.Error("Expected r.0.1.mca, got", name)
+	}
+	if name := RegionFileName(-1, -33); name != "r.-1.-2.mca" {
+		t.Error("Expected r.-1.-2.mca, got", name)
+	}
+}
+
+func TestRegionFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "r.0.0.mca")
+
+	region, err := OpenRegionFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := region.ReadChunk(1, 2); data != nil || err != nil {
+		t.Fatal("A new region should not contain any chunk")
+	}
+	small := []byte("small chunk")
+	// random data does not compress: it needs 3 sectors
+	big := make([]byte, 3*sectorSize-100)
+	rand.New(rand.NewSource(1)).Read(big)
+	if err = region.WriteChunk(1, 2, small); err != nil {
+		t.Fatal(err)
+	}
+	if err = region.WriteChunk(3, 4, small); err != nil {
+		t.Fatal(err)
+	}
+	// grows, and must be moved after the other chunk
+	if err = region.WriteChunk(1, 2, big); err != nil {
+		t.Fatal(err)
+	}
+	if err = region.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	region, err = OpenRegionFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer region.Close()
+	if !region.HasChunk(1, 2) || !region.HasChunk(3, 4) || region.HasChunk(0, 0) {
+		t.Error("Unexpected chunks in the region")
+	}
+	if data, err := region.ReadChunk(1, 2); err != nil || !bytes.Equal(data, big) {
+		t.Error("Could not read the big chunk back:", err)
+	}
+	if data, err := region.ReadChunk(3, 4); err != nil || !bytes.Equal(data, small) {
+		t.Error("Could not read the small chunk back:", err)
+	}
+	if region.Timestamp(3, 4).IsZero() {
+		t.Error("The timestamp of the chunk should have been written")
+	}
+	// the sector freed by the first chunk is reused
+	if err = region.WriteChunk(5, 6, small); err != nil {
+		t.Fatal(err)
+	}
+	if offset := region.locations[regionIndex(5, 6)] >> 8; offset != headerSectors {
+		t.Error("The first free sector should be reused, got offset", offset)
+	}
+}
+
+func TestRegionFileWriteError(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	region, err := OpenRegionFile(filepath.Join(dir, "r.0.0.mca"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = region.WriteChunk(1, 2, []byte("small chunk")); err != nil {
+		t.Fatal(err)
+	}
+	location, used := region.locations[regionIndex(1, 2)], append([]bool(nil), region.used...)
+	// the writes fail once the file is closed
+	region.Close()
+	big := make([]byte, 3*sectorSize-100)
+	rand.New(rand.NewSource(1)).Read(big)
+	if err = region.WriteChunk(1, 2, big); err == nil {
+		t.Fatal("Writing to a closed region should fail")
+	}
+	if region.locations[regionIndex(1, 2)] != location {
+		t.Error("The location of the chunk should not change if it could not be written")
+	}
+	if !reflect.DeepEqual(region.used, used) {
+		t.Errorf("The used sectors should not change if the chunk could not be written, got %v instead of %v", region.used, used)
+	}
+}
+
+func TestWorldPersistence(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "world")
+
+	w, err := OpenWorld(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunk := NewChunk(-3, 40)
+	chunk.SetBlock(1, 2, 3, material.Stone, 0)
+	chunk.SetBlockState(15, 255, 15, BlockState(300<<4|5))
+	chunk.Sections[0].BlockLight.Set(7, 12)
+	chunk.SetBiome(4, 5, 2)
+	w.SetChunk(chunk)
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err = OpenWorld(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.GetChunk(0, 0) != nil {
+		t.Error("A chunk which has not been saved should be nil")
+	}
+	loaded := w.GetChunk(-3, 40)
+	if loaded == nil {
+		t.Fatal("The saved chunk should have been loaded")
+	}
+	if state := loaded.GetBlockState(1, 2, 3); state.ID() != material.Stone.ID {
+		t.Error("Block should be stone, got", state)
+	}
+	if state := loaded.GetBlockState(15, 255, 15); state.ID() != 300 || state.Metadata() != 5 {
+		t.Error("The block id above 255 should be read from the Add array, got", state)
+	}
+	if light := loaded.Sections[0].BlockLight.Get(7); light != 12 {
+		t.Error("Block light should be 12, got", light)
+	}
+	if biome := loaded.GetBiome(4, 5); biome != 2 {
+		t.Error("Biome should be 2, got", biome)
+	}
+	for y, section := range loaded.Sections {
+		if (section != nil) != (y == 0 || y == 15) {
+			t.Error("Unexpected section", y)
+		}
+	}
+}
+
+func TestUnloadReload(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	w, err := OpenWorld(filepath.Join(dir, "world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	chunk := NewChunk(1, -1)
+	chunk.SetBlock(3, 70, 4, material.Stone, 0)
+	chunk.SetBiome(2, 2, 4)
+	w.SetChunk(chunk)
+	all := func(ChunkPos) bool { return false }
+	for i := 0; i < 2; i++ {
+		w.SetBlockState(16+int32(i), 10, -16, NewBlockState(material.Dirt, 0))
+		chunk = w.GetLoadedChunk(1, -1)
+		chunk.RLock()
+		sections := chunk.Sections
+		copies := make([]Section, len(sections))
+		for y, section := range sections {
+			if section != nil {
+				copies[y] = *section
+			}
+		}
+		biomes := chunk.Biomes
+		chunk.RUnlock()
+
+		if unloaded, err := w.UnloadChunks(all); err != nil || unloaded != 1 {
+			t.Fatalf("The chunk should have been unloaded, got %v %v", unloaded, err)
+		}
+		loaded := w.GetChunk(1, -1)
+		if loaded == nil || loaded == chunk {
+			t.Fatal("The chunk should have been read again")
+		}
+		for y, section := range loaded.Sections {
+			if (section == nil) != (sections[y] == nil) || section != nil && !reflect.DeepEqual(*section, copies[y]) {
+				t.Errorf("Section %v differs after reloading the chunk", y)
+			}
+		}
+		if loaded.Biomes != biomes {
+			t.Error("The biomes differ after reloading the chunk")
+		}
+		if loaded.dirty {
+			t.Error("A chunk which has just been read should not be dirty")
+		}
+	}
+}
+
+func TestChunkUnhandledTags(t *testing.T) {
+	entity := nbt.Compound{"id": nbt.String("minecraft:pig")}
+	level := chunkToNBT(NewChunk(2, 3)).GetCompound("Level")
+	level["Entities"] = nbt.NewList(nbt.TagCompound, entity)
+	level["TileTicks"] = nbt.NewList(nbt.TagCompound)
+	level["InhabitedTime"] = nbt.Long(1200)
+	level["TerrainPopulated"] = nbt.Byte(0)
+
+	c, err := chunkFromNBT(nbt.Compound{"Level": level})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetBlock(0, 0, 0, material.Stone, 0)
+	saved := chunkToNBT(c).GetCompound("Level")
+	for _, name := range []string{"Entities", "TileTicks", "InhabitedTime", "TerrainPopulated"} {
+		if !reflect.DeepEqual(saved[name], level[name]) {
+			t.Errorf("The %v tag should have been kept, got %v", name, saved[name])
+		}
+	}
+	if sections := saved.GetList("Sections"); sections == nil || sections.Len() != 1 {
+		t.Error("The sections should have been written from the chunk")
+	}
+}
+
+func TestUnloadChunks(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	w, err := OpenWorld(filepath.Join(dir, "world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.SetChunk(NewChunk(0, 0))
+	w.SetChunk(NewChunk(5, 0))
+	w.SetBlockState(5<<4, 10, 0, NewBlockState(material.Stone, 0))
+	unloaded, err := w.UnloadChunks(func(pos ChunkPos) bool {
+		return pos == ChunkPos{}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unloaded != 1 || w.GetLoadedChunk(5, 0) != nil {
+		t.Fatalf("Only the chunk 5, 0 should have been unloaded, got %v", unloaded)
+	}
+	if w.GetLoadedChunk(0, 0) == nil {
+		t.Error("The kept chunk should stay loaded")
+	}
+	// the unloaded chunk has been saved
+	if state := w.GetBlockState(5<<4, 10, 0); state.ID() != material.Stone.ID {
+		t.Error("The unloaded chunk should have been saved, got", state)
+	}
+
+	memory := NewWorld("memory")
+	memory.SetChunk(NewChunk(0, 0))
+	if unloaded, _ := memory.UnloadChunks(func(ChunkPos) bool { return false }); unloaded != 0 {
+		t.Error("The chunks of the worlds kept in memory should not be unloaded")
+	}
+}
@@ -0,0 +1,108 @@
+package world
+
+import (
+	"github.com/olsdavis/goelan/material"
+	"github.com/olsdavis/goelan/world/val"
+)
+
+const (
+	// MaxLight is the maximal light level.
+	MaxLight = 15
+)
+
+// BlockState represents a block and its metadata: id << 4 | metadata.
+type BlockState uint16
+
+// Air is the state of the empty blocks.
+const Air BlockState = 0
+
+// NewBlockState returns the state of the given material with the given metadata.
+func NewBlockState(mat material.Material, metadata byte) BlockState {
+	return BlockState(mat.ID<<4 | int(metadata&0x0F))
+}
+
+// ID returns the id of the block.
+func (state BlockState) ID() int {
+	return int(state >> 4)
+}
+
+// Metadata returns the metadata of the block.
+func (state BlockState) Metadata() byte {
+	return byte(state & 0x0F)
+}
+
+// Material returns the material of the block.
+func (state BlockState) Material() material.Material {
+	mat, _ := material.GetByState(int(state))
+	return mat
+}
+
+// Section struct represents a 16x16x16 cube of a chunk column.
+// The blocks are indexed by y << 8 | z << 4 | x, the coordinates
+// being relative to the section.
+type Section struct {
+	blocks     [val.SectionVolume]BlockState
+	BlockLight NibbleArray
+	SkyLight   NibbleArray
+	nonAir     int // the number of blocks which are not air
+}
+
+// NibbleArray stores 4-bit values (light levels), two per byte.
+type NibbleArray [val.SectionVolume / 2]byte
+
+// NewSection creates a new section full of air, lighted by the sky.
+func NewSection() *Section {
+	s := &Section{}
+	for i := range s.SkyLight {
+		s.SkyLight[i] = MaxLight<<4 | MaxLight
+	}
+	return s
+}
+
+// sectionIndex returns the index of the block at the given coordinates.
+func sectionIndex(x, y, z int) int {
+	return y<<8 | z<<4 | x
+}
+
+// GetBlockState returns the state of the block at the given coordinates.
+func (s *Section) GetBlockState(x, y, z int) BlockState {
+	return s.blocks[sectionIndex(x, y, z)]
+}
+
+// SetBlockState sets the state of the block at the given coordinates.
+func (s *Section) SetBlockState(x, y, z int, state BlockState) {
+	i := sectionIndex(x, y, z)
+	if s.blocks[i] == Air && state != Air {
+		s.nonAir++
+	} else if s.blocks[i] != Air && state == Air {
+		s.nonAir--
+	}
+	s.blocks[i] = state
+}
+
+// GetBlockStateAt returns the state of the block at the given index.
+func (s *Section) GetBlockStateAt(index int) BlockState {
+	return s.blocks[index]
+}
+
+// IsEmpty returns true if the section only contains air.
+func (s *Section) IsEmpty() bool {
+	return s.nonAir == 0
+}
+
+// Get returns the value at the given index.
+func (a *NibbleArray) Get(index int) byte {
+	if index&1 == 0 {
+		return a[index>>1] & 0x0F
+	}
+	return a[index>>1] >> 4
+}
+
+// Set sets the value at the given index.
+func (a *NibbleArray) Set(index int, value byte) {
+	if index&1 == 0 {
+		a[index>>1] = a[index>>1]&0xF0 | value&0x0F
+	} else {
+		a[index>>1] = a[index>>1]&0x0F | value<<4
+	}
+}
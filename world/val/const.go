@@ -1,5 +1,9 @@
 package val
 
 const (
-	ChunkSize = 16
-)
\ No newline at end of file
+	ChunkSize        = 16
+	SectionHeight    = 16
+	SectionsPerChunk = 16
+	ChunkHeight      = SectionHeight * SectionsPerChunk
+	SectionVolume    = ChunkSize * ChunkSize * SectionHeight
+)
@@ -0,0 +1,222 @@
+package world
+
+import "strings"
+
+// This file advances the time and the weather of the worlds every
+// tick, as vanilla does.
+
+const (
+	// the game rules stopping the time and the weather when set to "false"
+	DaylightCycleRule = "doDaylightCycle"
+	WeatherCycleRule  = "doWeatherCycle"
+	// the length of a day, in ticks
+	TicksPerDay = 24000
+	// the time of the day at noon
+	Noon = 6000
+	// the strength above which the clients consider that it rains
+	rainThreshold = 0.2
+	// the change of the strengths of the rain and the thunder per tick
+	strengthStep = 0.01
+)
+
+// Weather of a world.
+type Weather int
+
+const (
+	Clear Weather = iota
+	Rain
+	Thunder
+)
+
+// weatherNames contains the names of the weathers, as written in the configuration.
+var weatherNames = map[Weather]string{
+	Clear:   "clear",
+	Rain:    "rain",
+	Thunder: "thunder",
+}
+
+// ParseWeather returns the weather of the given name ("clear", "rain"
+// or "thunder"). Returns false if it does not exist.
+func ParseWeather(name string) (Weather, bool) {
+	name = strings.ToLower(name)
+	for weather, n := range weatherNames {
+		if n == name {
+			return weather, true
+		}
+	}
+	return Clear, false
+}
+
+// String returns the name of the weather.
+func (w Weather) String() string {
+	return weatherNames[w]
+}
+
+// WeatherChange struct describes how the weather of a world changed during a tick.
+type WeatherChange struct {
+	Toggled         bool // the rain started or stopped
+	Raining         bool // the clients render the rain
+	RainStrength    float32
+	ThunderStrength float32
+	Changed         bool // the strengths changed
+}
+
+// IsDaylightCycle returns true if the time of the day advances.
+func (w *World) IsDaylightCycle() bool {
+	defer w.timeLock.Unlock()
+	w.timeLock.Lock()
+	return w.Info != nil && w.Info.GetGameRule(DaylightCycleRule, "true") != "false"
+}
+
+// Tick advances the time of the world by a tick, and its weather if it
+// has a sky. Returns how the weather changed.
+func (w *World) Tick() WeatherChange {
+	defer w.timeLock.Unlock()
+	w.timeLock.Lock()
+	info := w.Info
+	if info == nil {
+		return WeatherChange{}
+	}
+	info.Time++
+	if info.GetGameRule(DaylightCycleRule, "true") != "false" {
+		info.DayTime++
+	}
+	wasRaining := w.rainStrength > rainThreshold
+	rain, thunder := w.rainStrength, w.thunderStrength
+	if w.Dimension.HasSkyLight() && info.GetGameRule(WeatherCycleRule, "true") != "false" {
+		w.updateWeather()
+	}
+	raining := w.rainStrength > rainThreshold
+	return WeatherChange{
+		Toggled:         raining != wasRaining,
+		Raining:         raining,
+		RainStrength:    w.rainStrength,
+		ThunderStrength: w.thunderStrength,
+		Changed:         rain != w.rainStrength || thunder != w.thunderStrength,
+	}
+}
+
+// updateWeather counts down the durations of the weather, toggles the rain
+// and the thunder when they end, and moves the strengths towards them.
+func (w *World) updateWeather() {
+	info := w.Info
+	if info.ClearWeatherTime > 0 {
+		info.ClearWeatherTime--
+		// the rain and the thunder stop at the next tick
+		info.ThunderTime, info.RainTime = 2, 2
+		if info.Thundering {
+			info.ThunderTime = 1
+		}
+		if info.Raining {
+			info.RainTime = 1
+		}
+	}
+
+	if info.ThunderTime <= 0 {
+		if info.Thundering {
+			info.ThunderTime = w.random.Int31n(12000) + 3600
+		} else {
+			info.ThunderTime = w.random.Int31n(168000) + 12000
+		}
+	} else if info.ThunderTime--; info.ThunderTime <= 0 {
+		info.Thundering = !info.Thundering
+	}
+	w.thunderStrength = step(w.thunderStrength, info.Thundering)
+
+	if info.RainTime <= 0 {
+		if info.Raining {
+			info.RainTime = w.random.Int31n(12000) + 12000
+		} else {
+			info.RainTime = w.random.Int31n(168000) + 12000
+		}
+	} else if info.RainTime--; info.RainTime <= 0 {
+		info.Raining = !info.Raining
+	}
+	w.rainStrength = step(w.rainStrength, info.Raining)
+}
+
+// step moves the given strength towards 1 if up is true, and towards 0 otherwise.
+func step(strength float32, up bool) float32 {
+	if up {
+		strength += strengthStep
+	} else {
+		strength -= strengthStep
+	}
+	if strength < 0 {
+		return 0
+	}
+	if strength > 1 {
+		return 1
+	}
+	return strength
+}
+
+// GetTime returns the age of the world and the time of the day, in ticks.
+func (w *World) GetTime() (age int64, dayTime int64) {
+	defer w.timeLock.Unlock()
+	w.timeLock.Lock()
+	if w.Info == nil {
+		return 0, 0
+	}
+	return w.Info.Time, w.Info.DayTime
+}
+
+// SetTime sets the time of the day, in ticks.
+func (w *World) SetTime(dayTime int64) {
+	defer w.timeLock.Unlock()
+	w.timeLock.Lock()
+	if w.Info != nil {
+		w.Info.DayTime = dayTime
+	}
+}
+
+// GetWeather returns the current weather of the world.
+func (w *World) GetWeather() Weather {
+	defer w.timeLock.Unlock()
+	w.timeLock.Lock()
+	switch {
+	case w.Info == nil || !w.Info.Raining:
+		return Clear
+	case w.Info.Thundering:
+		return Thunder
+	}
+	return Rain
+}
+
+// GetWeatherStrengths returns the strengths of the rain and the thunder, from 0 to 1.
+func (w *World) GetWeatherStrengths() (rain float32, thunder float32) {
+	defer w.timeLock.Unlock()
+	w.timeLock.Lock()
+	return w.rainStrength, w.thunderStrength
+}
+
+// SetWeather sets the weather of the world, as the weather command of
+// vanilla does: it lasts the given number of ticks, or a random duration
+// if it is 0. The weather changes at once, without transition.
+func (w *World) SetWeather(weather Weather, duration int32) {
+	defer w.timeLock.Unlock()
+	w.timeLock.Lock()
+	info := w.Info
+	if info == nil {
+		return
+	}
+	if duration <= 0 {
+		duration = (300 + w.random.Int31n(600)) * 20
+	}
+	info.Raining = weather != Clear
+	info.Thundering = weather == Thunder
+	if weather == Clear {
+		info.ClearWeatherTime = duration
+		info.RainTime, info.ThunderTime = 0, 0
+	} else {
+		info.ClearWeatherTime = 0
+		info.RainTime, info.ThunderTime = duration, duration
+	}
+	w.rainStrength, w.thunderStrength = 0, 0
+	if info.Raining {
+		w.rainStrength = 1
+	}
+	if info.Thundering {
+		w.thunderStrength = 1
+	}
+}
@@ -0,0 +1,107 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// weatherWorld returns a new overworld, whose weather is drawn from the given seed.
+func weatherWorld(seed int64) *World {
+	w := NewWorld("weather")
+	w.Info = NewWorldInfo("weather", 0)
+	w.random = rand.New(rand.NewSource(seed))
+	return w
+}
+
+func TestParseWeather(t *testing.T) {
+	for name, expected := range map[string]Weather{"clear": Clear, "Rain": Rain, "THUNDER": Thunder} {
+		if weather, ok := ParseWeather(name); !ok || weather != expected {
+			t.Errorf("%v: expected %v, got %v", name, expected, weather)
+		}
+	}
+	if _, ok := ParseWeather("snow"); ok {
+		t.Error("snow is not a weather")
+	}
+}
+
+func TestTickTime(t *testing.T) {
+	w := weatherWorld(1)
+	w.SetTime(Noon)
+	w.Tick()
+	if age, dayTime := w.GetTime(); age != 1 || dayTime != Noon+1 {
+		t.Errorf("Expected age 1 and time %v, got %v and %v", Noon+1, age, dayTime)
+	}
+
+	w.Info.SetGameRule(DaylightCycleRule, "false")
+	w.Tick()
+	if age, dayTime := w.GetTime(); age != 2 || dayTime != Noon+1 {
+		t.Errorf("The time of the day should be stopped, got age %v and time %v", age, dayTime)
+	}
+	if w.IsDaylightCycle() {
+		t.Error("The daylight cycle should be disabled")
+	}
+}
+
+func TestWeatherCycle(t *testing.T) {
+	w := weatherWorld(2)
+	toggles := 0
+	// a whole cycle lasts 363000 ticks at most
+	for i := 0; i < 400000; i++ {
+		change := w.Tick()
+		if change.Toggled {
+			toggles++
+			if change.Raining != (change.RainStrength > rainThreshold) {
+				t.Fatalf("Tick %v: the rain toggled to %v with strength %v", i, change.Raining, change.RainStrength)
+			}
+		}
+		if change.RainStrength < 0 || change.RainStrength > 1 || change.ThunderStrength < 0 || change.ThunderStrength > 1 {
+			t.Fatalf("Tick %v: invalid strengths %v and %v", i, change.RainStrength, change.ThunderStrength)
+		}
+	}
+	if toggles < 2 {
+		t.Error("The rain should have started and stopped, got", toggles, "toggles")
+	}
+}
+
+func TestSetWeather(t *testing.T) {
+	w := weatherWorld(3)
+	w.SetWeather(Thunder, 100)
+	if weather := w.GetWeather(); weather != Thunder {
+		t.Error("Expected thunder, got", weather)
+	}
+	if rain, thunder := w.GetWeatherStrengths(); rain != 1 || thunder != 1 {
+		t.Errorf("The weather should change at once, got strengths %v and %v", rain, thunder)
+	}
+	for i := 0; i < 100; i++ {
+		w.Tick()
+	}
+	if weather := w.GetWeather(); weather != Clear {
+		t.Error("The thunder should have stopped after 100 ticks, got", weather)
+	}
+
+	w.SetWeather(Clear, 1000)
+	for i := 0; i < 1000; i++ {
+		if change := w.Tick(); w.GetWeather() != Clear || change.Toggled {
+			t.Fatal("The weather should stay clear, tick", i)
+		}
+	}
+
+	// the weather does not change without the weather cycle
+	w = weatherWorld(4)
+	w.Info.SetGameRule(WeatherCycleRule, "false")
+	w.SetWeather(Rain, 1)
+	for i := 0; i < 10; i++ {
+		if change := w.Tick(); change.Changed || change.Toggled {
+			t.Fatal("The weather should not change, tick", i)
+		}
+	}
+
+	// the nether has no weather
+	w = weatherWorld(5)
+	w.Dimension = Nether
+	for i := 0; i < 200000; i++ {
+		if w.Tick().Toggled {
+			t.Fatal("It should not rain in the nether")
+		}
+	}
+}
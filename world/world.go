@@ -1,13 +1,409 @@
 package world
 
+import (
+	"bytes"
+	"fmt"
+	"github.com/olsdavis/goelan/log"
+	"github.com/olsdavis/goelan/nbt"
+	"github.com/olsdavis/goelan/world/val"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// the directory of the region files, in the directory of the world
+	regionDirectory = "region"
+)
+
+// ChunkPos struct represents the coordinates of a chunk.
+type ChunkPos struct {
+	X, Z int32
+}
+
+// ChunkGenerator generates the chunks which have never been stored.
+// (See the generator package.)
+type ChunkGenerator interface {
+	GenerateChunkColumn(x, z int, world *World) *Chunk
+}
+
 type World struct {
-	Name   string
-	Chunks [][][]*Chunk
+	Name      string
+	Directory string              // the directory where the world is stored (empty if kept in memory)
+	Dimension Dimension           // the dimension rendered by the clients
+	Info      *WorldInfo          // the metadata of the world (level.dat)
+	Generator ChunkGenerator      // generates the missing chunks (nil if none)
+	chunks    map[ChunkPos]*Chunk // the loaded chunks
+	lock      sync.RWMutex        // lock for the chunks map
+
+	regions    map[ChunkPos]*RegionFile // the opened region files, by region coordinates
+	regionLock sync.Mutex               // lock for the regions map
+
+	skyLight   *lightEngine // propagates the sky light
+	blockLight *lightEngine // propagates the light of the luminous blocks
+	lightLock  sync.Mutex   // lock for the light engines
+
+	rainStrength    float32    // from 0 to 1
+	thunderStrength float32    // from 0 to 1
+	random          *rand.Rand // draws the durations of the weather
+	timeLock        sync.Mutex // lock for the time and the weather
+
+	borderStart time.Time  // the time the resizing of the border of the metadata starts at
+	borderLock  sync.Mutex // lock for the border
 }
 
+// NewWorld creates a world which is only kept in memory.
 func NewWorld(name string) *World {
-	return &World{
-		Name: name,
-		Chunks: make([][][]*Chunk, 16),
+	w := &World{
+		Name:    name,
+		chunks:  make(map[ChunkPos]*Chunk),
+		regions: make(map[ChunkPos]*RegionFile),
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+
+		borderStart: time.Now(),
+	}
+	w.skyLight = newLightEngine(w, true)
+	w.blockLight = newLightEngine(w, false)
+	return w
+}
+
+// OpenWorld opens the world stored in the given directory, as vanilla
+// does, and creates the directory if it does not exist. The chunks are
+// loaded from the region files when they are requested. The Info of the
+// world is nil if it has no level.dat file yet.
+func OpenWorld(directory string) (*World, error) {
+	if err := os.MkdirAll(filepath.Join(directory, regionDirectory), 0755); err != nil {
+		return nil, err
+	}
+	info, err := LoadWorldInfo(directory)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %v: %v", levelFile, err)
+	}
+	w := NewWorld(filepath.Base(directory))
+	w.Directory = directory
+	w.Info = info
+	if info != nil && info.Raining {
+		w.rainStrength = 1
+		if info.Thundering {
+			w.thunderStrength = 1
+		}
+	}
+	return w, nil
+}
+
+// IsPersistent returns true if the world is saved to region files.
+func (w *World) IsPersistent() bool {
+	return w.Directory != ""
+}
+
+// GetSpawnLocation returns the location where the players spawn:
+// the center of the spawn block of the metadata.
+func (w *World) GetSpawnLocation() *Location {
+	return NewLocation(float32(w.Info.SpawnX)+0.5, float32(w.Info.SpawnY), float32(w.Info.SpawnZ)+0.5, w)
+}
+
+// GetChunk returns the chunk at the given chunk coordinates, loading it
+// from its region file if needed, or generating it if it has never been
+// stored, in which case its light is computed. Returns nil if it does not
+// exist, and the world has no generator.
+func (w *World) GetChunk(x, z int32) *Chunk {
+	chunk := w.GetLoadedChunk(x, z)
+	if chunk != nil {
+		return chunk
+	}
+
+	if w.IsPersistent() {
+		var err error
+		if chunk, err = w.loadChunk(x, z); err != nil {
+			// do not generate over a chunk which may be recovered
+			log.Error("Could not load chunk", x, z, "of world", w.Name+":", err)
+			return nil
+		}
+	}
+	if chunk == nil && w.Generator != nil {
+		if chunk = w.Generator.GenerateChunkColumn(int(x), int(z), w); chunk != nil {
+			// the chunk is added before another one is lit, so that
+			// the light of the next ones goes through it
+			w.lightLock.Lock()
+			defer w.lightLock.Unlock()
+			w.lightChunk(chunk)
+			chunk.dirty = true
+		}
+	}
+	if chunk == nil {
+		return nil
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	// another routine may have loaded it meanwhile
+	if loaded, ok := w.chunks[ChunkPos{x, z}]; ok {
+		return loaded
+	}
+	w.chunks[ChunkPos{x, z}] = chunk
+	return chunk
+}
+
+// GetLoadedChunk returns the chunk at the given chunk coordinates
+// if it is loaded, or nil, without loading nor generating it.
+func (w *World) GetLoadedChunk(x, z int32) *Chunk {
+	defer w.lock.RUnlock()
+	w.lock.RLock()
+	return w.chunks[ChunkPos{x, z}]
+}
+
+// GetBlockState returns the state of the block at the given coordinates,
+// loading or generating its chunk if needed. Returns Air if the chunk
+// does not exist.
+func (w *World) GetBlockState(x, y, z int32) BlockState {
+	chunk := w.GetChunk(x>>4, z>>4)
+	if chunk == nil {
+		return Air
+	}
+	defer chunk.RUnlock()
+	chunk.RLock()
+	return chunk.GetBlockState(int(x&0x0F), int(y), int(z&0x0F))
+}
+
+// SetBlockState sets the state of the block at the given coordinates,
+// loading or generating its chunk if needed, and updates the light
+// around it. Returns false if the chunk does not exist, or if the
+// coordinates are out of the world.
+func (w *World) SetBlockState(x, y, z int32, state BlockState) bool {
+	if y < 0 || y >= val.ChunkHeight {
+		return false
+	}
+	chunk := w.GetChunk(x>>4, z>>4)
+	if chunk == nil {
+		return false
+	}
+	chunk.Lock()
+	// the chunk may have been unloaded meanwhile: it is loaded again
+	for chunk.unloaded {
+		chunk.Unlock()
+		if chunk = w.GetChunk(x>>4, z>>4); chunk == nil {
+			return false
+		}
+		chunk.Lock()
+	}
+	changed := chunk.GetBlockState(int(x&0x0F), int(y), int(z&0x0F)) != state
+	if changed {
+		chunk.SetBlockState(int(x&0x0F), int(y), int(z&0x0F), state)
+		chunk.dirty = true
+	}
+	chunk.Unlock()
+	if !changed {
+		return true
+	}
+	w.lightLock.Lock()
+	if w.Dimension.HasSkyLight() {
+		w.skyLight.update(x, y, z)
+	}
+	w.blockLight.update(x, y, z)
+	w.lightLock.Unlock()
+	return true
+}
+
+// lightChunk computes the sky light (if the dimension has any) and the
+// block light of the given chunk, and propagates them through the loaded
+// chunks around. The light lock must be held.
+func (w *World) lightChunk(chunk *Chunk) {
+	if w.Dimension.HasSkyLight() {
+		w.skyLight.lightChunk(chunk)
+	}
+	w.blockLight.lightChunk(chunk)
+}
+
+// SetChunk adds the given chunk to the loaded chunks. It is
+// saved with the world.
+func (w *World) SetChunk(chunk *Chunk) {
+	chunk.dirty = true
+	w.lock.Lock()
+	w.chunks[ChunkPos{chunk.X, chunk.Z}] = chunk
+	w.lock.Unlock()
+}
+
+// loadChunk reads the given chunk from its region file. Returns nil
+// if it has not been stored.
+func (w *World) loadChunk(x, z int32) (*Chunk, error) {
+	region, err := w.getRegion(x, z)
+	if err != nil {
+		return nil, err
+	}
+	data, err := region.ReadChunk(x, z)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	_, root, err := nbt.Read(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	chunk, err := chunkFromNBT(root)
+	if err != nil {
+		return nil, err
+	}
+	if chunk.X != x || chunk.Z != z {
+		return nil, fmt.Errorf("stored chunk has coordinates %v, %v", chunk.X, chunk.Z)
+	}
+	return chunk, nil
+}
+
+// SaveChunk writes the given chunk to its region file.
+func (w *World) SaveChunk(chunk *Chunk) error {
+	defer chunk.Unlock()
+	chunk.Lock()
+	return w.saveChunk(chunk)
+}
+
+// saveChunk is SaveChunk, without locking the chunk.
+func (w *World) saveChunk(chunk *Chunk) error {
+	if !w.IsPersistent() {
+		return nil
+	}
+	region, err := w.getRegion(chunk.X, chunk.Z)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	if err = nbt.Write(buf, "", chunkToNBT(chunk)); err != nil {
+		return err
+	}
+	if err = region.WriteChunk(chunk.X, chunk.Z, buf.Bytes()); err != nil {
+		return err
+	}
+	chunk.dirty = false
+	return nil
+}
+
+// saveIfDirty saves the given chunk if it has changed since it was loaded or saved.
+func (w *World) saveIfDirty(chunk *Chunk) error {
+	defer chunk.Unlock()
+	chunk.Lock()
+	if !chunk.dirty {
+		return nil
+	}
+	return w.saveChunk(chunk)
+}
+
+// UnloadChunks saves the loaded chunks for which keep returns false if
+// they have changed, and unloads them. The chunks of the worlds kept in
+// memory are never unloaded, and the ones which could not be saved stay
+// loaded. Returns the number of unloaded chunks, and the last error
+// encountered.
+func (w *World) UnloadChunks(keep func(pos ChunkPos) bool) (int, error) {
+	if !w.IsPersistent() {
+		return 0, nil
+	}
+	w.lock.RLock()
+	chunks := make([]*Chunk, 0)
+	for pos, chunk := range w.chunks {
+		if !keep(pos) {
+			chunks = append(chunks, chunk)
+		}
+	}
+	w.lock.RUnlock()
+
+	unloaded := 0
+	var ret error
+	for _, chunk := range chunks {
+		if err := w.unloadChunk(chunk); err != nil {
+			log.Error("Could not save chunk", chunk.X, chunk.Z, "of world", w.Name+":", err)
+			ret = err
+		} else {
+			unloaded++
+		}
+	}
+	return unloaded, ret
+}
+
+// unloadChunk saves the given chunk if it has changed, and removes it
+// from the loaded chunks.
+func (w *World) unloadChunk(chunk *Chunk) error {
+	// the chunk is locked before the chunks map, as the light engines do
+	defer chunk.Unlock()
+	chunk.Lock()
+	// the chunk must not be read from its region file before it is saved
+	defer w.lock.Unlock()
+	w.lock.Lock()
+	pos := ChunkPos{chunk.X, chunk.Z}
+	if chunk.unloaded || w.chunks[pos] != chunk {
+		return nil
+	}
+	if chunk.dirty {
+		if err := w.saveChunk(chunk); err != nil {
+			return err
+		}
+	}
+	chunk.unloaded = true
+	delete(w.chunks, pos)
+	return nil
+}
+
+// Save writes the metadata and the loaded chunks which have changed.
+// Returns the last error encountered.
+func (w *World) Save() error {
+	w.lock.RLock()
+	chunks := make([]*Chunk, 0, len(w.chunks))
+	for _, chunk := range w.chunks {
+		chunks = append(chunks, chunk)
+	}
+	w.lock.RUnlock()
+
+	var ret error
+	if w.IsPersistent() && w.Info != nil {
+		w.timeLock.Lock()
+		w.borderLock.Lock()
+		w.updateBorder(time.Now())
+		err := w.Info.Save(w.Directory)
+		w.borderLock.Unlock()
+		w.timeLock.Unlock()
+		if err != nil {
+			log.Error("Could not save", levelFile, "of world", w.Name+":", err)
+			ret = err
+		}
+	}
+	for _, chunk := range chunks {
+		if err := w.saveIfDirty(chunk); err != nil {
+			log.Error("Could not save chunk", chunk.X, chunk.Z, "of world", w.Name+":", err)
+			ret = err
+		}
+	}
+	return ret
+}
+
+// Close saves the world and closes its region files.
+func (w *World) Close() error {
+	err := w.Save()
+	w.regionLock.Lock()
+	for pos, region := range w.regions {
+		if e := region.Close(); e != nil {
+			err = e
+		}
+		delete(w.regions, pos)
+	}
+	w.regionLock.Unlock()
+	return err
+}
+
+// levelPath returns the path of the given file of the world's directory.
+func levelPath(directory, file string) string {
+	return filepath.Join(directory, file)
+}
+
+// getRegion returns the region file containing the given chunk,
+// opening it if needed.
+func (w *World) getRegion(x, z int32) (*RegionFile, error) {
+	defer w.regionLock.Unlock()
+	w.regionLock.Lock()
+	pos := ChunkPos{x >> 5, z >> 5}
+	if region, ok := w.regions[pos]; ok {
+		return region, nil
+	}
+	region, err := OpenRegionFile(filepath.Join(w.Directory, regionDirectory, RegionFileName(x, z)))
+	if err != nil {
+		return nil, err
 	}
+	w.regions[pos] = region
+	return region, nil
 }